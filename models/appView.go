@@ -1,19 +1,65 @@
 package models
 
 import (
+	"fmt"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	statusBarStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	statusWarningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
 )
 
 type AppView int
 
 type AppModel struct {
-	currentView  AppView
-	login        Login
-	mainMenu     MainMenu
-	AWSMenu      AWSMenu
-	ClickUpMenu  ClickUpMenu
-	PostgresMenu PostgresMenu
-	OpenAIMenu   OpenAIMenu
+	currentView    AppView
+	login          Login
+	mainMenu       MainMenu
+	AWSMenu        AWSMenu
+	ClickUpMenu    ClickUpMenu
+	PostgresMenu   PostgresMenu
+	OpenAIMenu     OpenAIMenu
+	dbOps          DatabaseOperations
+	imageGen       ImageGeneration
+	imageVision    ImageVision
+	usage          OpenAIUsage
+	s3Browser      S3Browser
+	s3Transfers    S3Transfers
+	s3Share        S3Share
+	s3Bulk         S3Bulk
+	rekognition    RekognitionAnalysis
+	faces          FaceCollections
+	batch          RekognitionBatch
+	dynamo         DynamoExplorer
+	dynamoEditor   DynamoItemEditor
+	dynamoTransfer DynamoTransfer
+	clickUpLogin   ClickUpLogin
+	clickUpNav     ClickUpNavigator
+	clickUpTasks   ClickUpTasks
+	clickUpTask    ClickUpTaskDetail
+	quickTask      ClickUpQuickTask
+	comments       ClickUpComments
+	timeTracking   ClickUpTimeTracking
+	attachments    ClickUpAttachments
+	docs           ClickUpDocs
+	goals          ClickUpGoals
+	webhooks       ClickUpWebhooks
+	people         ClickUpPeople
+	// timer is the running ClickUp timer shown in the status bar, if any.
+	timer    *ClickUpTimeEntry
+	timerErr error
+	// timerGen stops ticks of a timer that has since been replaced.
+	timerGen int
+	// quickTaskReturn is the view the quick task form was opened from.
+	quickTaskReturn AppView
+	// commentsReturn is the view the comments were opened from.
+	commentsReturn AppView
+	// attachmentsReturn is the view the attachments were opened from.
+	attachmentsReturn AppView
+	config            Config
 }
 
 const (
@@ -23,9 +69,38 @@ const (
 	ViewClickUpMenu
 	ViewPostgresMenu
 	ViewOpenAIMenu
+	ViewDatabaseOperations
+	ViewImageGeneration
+	ViewImageVision
+	ViewOpenAIUsage
+	ViewS3Browser
+	ViewS3Transfers
+	ViewS3Share
+	ViewS3Bulk
+	ViewRekognition
+	ViewFaceCollections
+	ViewRekognitionBatch
+	ViewDynamoExplorer
+	ViewDynamoEditor
+	ViewDynamoTransfer
+	ViewClickUpLogin
+	ViewClickUpNavigator
+	ViewClickUpTasks
+	ViewClickUpTaskDetail
+	ViewClickUpQuickTask
+	ViewClickUpComments
+	ViewClickUpTimeTracking
+	ViewClickUpAttachments
+	ViewClickUpDocs
+	ViewClickUpGoals
+	ViewClickUpWebhooks
+	ViewClickUpPeople
 )
 
 func InitialAppModel() AppModel {
+	// A missing or unreadable config just means the defaults.
+	config, _ := LoadConfig()
+
 	return AppModel{
 		currentView:  ViewLogin,
 		login:        InitialLogin(),
@@ -34,6 +109,7 @@ func InitialAppModel() AppModel {
 		ClickUpMenu:  ClickUpMenu{},
 		PostgresMenu: PostgresMenu{},
 		OpenAIMenu:   OpenAIMenu{},
+		config:       config,
 	}
 }
 
@@ -51,34 +127,337 @@ func (m AppModel) Init() tea.Cmd {
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
+	case tea.KeyMsg:
+		// Signing in to ClickUp waits on the browser; the form needs the
+		// profile that produces anyway.
+		if msg.String() == quickTaskKey && m.currentView != ViewLogin && m.currentView != ViewClickUpLogin && m.currentView != ViewClickUpQuickTask {
+			return m, m.openQuickTask()
+		}
+
+	case ClickUpQuickTaskDataMsg:
+		// Lists may finish loading after the form was closed; keep them for next time.
+		updatedQuickTask, cmd := m.quickTask.Update(msg)
+		m.quickTask = updatedQuickTask.(ClickUpQuickTask)
+		return m, cmd
+
+	// Background runs keep reporting to their screen while another one, such
+	// as the quick task form, is open; otherwise their next wait is never
+	// armed and the workers block.
+	case TransferProgressMsg, TransferFinishedMsg, TransfersDoneMsg:
+		updatedTransfers, cmd := m.s3Transfers.Update(msg)
+		m.s3Transfers = updatedTransfers.(S3Transfers)
+		return m, cmd
+
+	case DynamoTransferProgressMsg, DynamoTransferDoneMsg:
+		updatedTransfer, cmd := m.dynamoTransfer.Update(msg)
+		m.dynamoTransfer = updatedTransfer.(DynamoTransfer)
+		return m, cmd
+
+	case BatchItemMsg, BatchDoneMsg:
+		updatedBatch, cmd := m.batch.Update(msg)
+		m.batch = updatedBatch.(RekognitionBatch)
+		return m, cmd
+
+	case WebhookDeliveryMsg, WebhookReceiverStoppedMsg:
+		// The receiver keeps logging while another screen, such as the quick
+		// task form, is open.
+		updatedWebhooks, cmd := m.webhooks.Update(msg)
+		m.webhooks = updatedWebhooks.(ClickUpWebhooks)
+		return m, cmd
+
+	case CloseQuickTaskMsg:
+		m.currentView = m.quickTaskReturn
+		if msg.created && m.currentView == ViewClickUpTasks {
+			cmd := m.clickUpTasks.reload()
+			return m, cmd
+		}
+		return m, nil
+
+	case ClickUpTimerToggleMsg:
+		return m, toggleClickUpTimer(msg, m.timer)
+
+	case ClickUpTimerMsg:
+		m.timer, m.timerErr = msg.timer, msg.err
+		m.timerGen++
+		var cmds []tea.Cmd
+		if m.timer != nil {
+			cmds = append(cmds, tickClickUpTimer(m.timerGen))
+		}
+		if m.currentView == ViewClickUpTimeTracking {
+			updatedTime, cmd := m.timeTracking.Update(msg)
+			m.timeTracking = updatedTime.(ClickUpTimeTracking)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case ClickUpTimerTickMsg:
+		if msg.gen != m.timerGen || m.timer == nil {
+			return m, nil
+		}
+		if m.currentView == ViewClickUpTimeTracking {
+			updatedTime, _ := m.timeTracking.Update(msg)
+			m.timeTracking = updatedTime.(ClickUpTimeTracking)
+		}
+		return m, tickClickUpTimer(m.timerGen)
+
+	case BudgetUpdatedMsg:
+		m.config.OpenAIBudget = msg.budget
+
 	case LoginSuccessMsg:
 		m.mainMenu = InitialMainMenu(msg.Token, msg.RefreshToken, msg.User)
 		m.currentView = ViewMainMenu
-		return m, nil
+		// Pick up a timer left running by an earlier session or the web app.
+		profile, _, _ := LoadClickUpProfile(msg.User)
+		return m, checkClickUpTimer(profile)
 
 	case MainMenuMsg:
 		switch msg.selected {
 		case 0: // Postgres
-			m.mainMenu = InitialPostgresMenu(msg.token, msg.refreshToken, msg.user)
+			m.PostgresMenu = InitialPostgresMenu(msg.token, msg.refreshToken, msg.user)
 			m.currentView = ViewPostgresMenu
 			return m, nil
 		case 1: // OpenAI
-			m.mainMenu = InitialOpemAIMenu(msg.token, msg.refreshToken, msg.user)
+			m.OpenAIMenu = InitialOpemAIMenu(msg.token, msg.refreshToken, msg.user)
 			m.currentView = ViewOpenAIMenu
 			return m, nil
 		case 2: // AWS
-			m.mainMenu = InitialAWSMenu(msg.token, msg.refreshToken, msg.user)
+			m.AWSMenu = InitialAWSMenu(msg.token, msg.refreshToken, msg.user)
 			m.currentView = ViewAWSMenu
 			return m, nil
 		case 3: // ClickUp
-			m.mainMenu = InitialClickUpMenu(msg.token, msg.refreshToken, msg.user)
-			m.currentView = ViewClickUpMenu
-			return m, nil
+			profile, _, err := LoadClickUpProfile(msg.user)
+			if err == nil && profile.WorkspaceID != "" {
+				m.ClickUpMenu = InitialClickUpMenu(msg.token, msg.refreshToken, msg.user, profile)
+				m.currentView = ViewClickUpMenu
+				return m, m.ClickUpMenu.Init()
+			}
+			m.clickUpLogin = InitialClickUpLogin(msg.token, msg.refreshToken, msg.user, profile)
+			if err != nil {
+				m.clickUpLogin.status = fmt.Sprintf("Error: %v", err)
+			}
+			m.currentView = ViewClickUpLogin
+			return m, m.clickUpLogin.Init()
 		default:
 			m.mainMenu = InitialMainMenu(msg.token, msg.refreshToken, msg.user)
 			m.currentView = ViewMainMenu
 			return m, nil
 		}
+
+	case PostgresMenuMsg:
+		switch msg.selected {
+		case 2: // Database Operations
+			m.dbOps = InitialDatabaseOperations(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewDatabaseOperations
+			return m, m.dbOps.Init()
+		}
+
+	case OpenAIMenuMsg:
+		switch msg.selected {
+		case 2: // Generate Image
+			m.imageGen = InitialImageGeneration(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewImageGeneration
+			return m, m.imageGen.Init()
+		case 3: // Describe Image
+			m.imageVision = InitialImageVision(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewImageVision
+			return m, m.imageVision.Init()
+		case 4: // Usage
+			m.usage = InitialOpenAIUsage(msg.token, msg.refreshToken, msg.user, m.config.OpenAIBudget)
+			m.currentView = ViewOpenAIUsage
+			return m, m.usage.Init()
+		}
+
+	case AWSMenuMsg:
+		switch msg.selected {
+		case 0: // S3
+			m.s3Browser = InitialS3Browser(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewS3Browser
+			return m, m.s3Browser.Init()
+		case 1: // Rekognition
+			m.rekognition = InitialRekognitionAnalysis(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewRekognition
+			return m, m.rekognition.Init()
+		case 2: // Face Collections
+			m.faces = InitialFaceCollections(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewFaceCollections
+			return m, m.faces.Init()
+		case 3: // Batch Rekognition
+			m.batch = InitialRekognitionBatch(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewRekognitionBatch
+			return m, m.batch.Init()
+		case 4: // DynamoDB
+			m.dynamo = InitialDynamoExplorer(msg.token, msg.refreshToken, msg.user)
+			m.currentView = ViewDynamoExplorer
+			return m, m.dynamo.Init()
+		}
+
+	case S3TransferRequestMsg:
+		if msg.upload {
+			m.s3Transfers = InitialS3Upload(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, m.config, msg.bucket, msg.prefix)
+		} else {
+			m.s3Transfers = InitialS3Download(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, m.config, msg.entry)
+		}
+		m.currentView = ViewS3Transfers
+		return m, m.s3Transfers.Init()
+
+	case S3ShareRequestMsg:
+		m.s3Share = InitialS3Share(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, msg.bucket, msg.key)
+		m.currentView = ViewS3Share
+		return m, m.s3Share.Init()
+
+	case RekognitionRequestMsg:
+		m.rekognition = InitialRekognitionObject(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, msg.bucket, msg.key)
+		m.currentView = ViewRekognition
+		return m, m.rekognition.Init()
+
+	case S3BulkRequestMsg:
+		if msg.sync {
+			m.s3Bulk = InitialS3Sync(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, m.config, msg.bucket, msg.prefix)
+		} else {
+			m.s3Bulk = InitialS3Bulk(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, msg.op, msg.bucket, msg.prefix, msg.entries)
+		}
+		m.currentView = ViewS3Bulk
+		return m, m.s3Bulk.Init()
+
+	case S3RunJobsMsg:
+		m.s3Transfers = InitialS3Run(m.s3Browser.token, m.s3Browser.refreshToken, m.s3Browser.user, m.config, msg.title, msg.jobs)
+		m.currentView = ViewS3Transfers
+		return m, m.s3Transfers.Init()
+
+	case DynamoEditRequestMsg:
+		m.dynamoEditor = InitialDynamoItemEditor(m.dynamo.token, m.dynamo.refreshToken, m.dynamo.user, msg.table, msg.item, msg.delete)
+		m.currentView = ViewDynamoEditor
+		return m, m.dynamoEditor.Init()
+	case ShowClickUpLoginMsg:
+		m.clickUpLogin = InitialClickUpLogin(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, msg.profile)
+		m.currentView = ViewClickUpLogin
+		return m, m.clickUpLogin.Init()
+	case ClickUpSignedOutMsg:
+		// Nothing may keep using the old credential: not the screens opened
+		// from the menu, the navigator's cache or the status bar timer.
+		m.ClickUpMenu.clickup = ClickUpProfile{}
+		m.clickUpNav.clickup = ClickUpProfile{}
+		m.timer, m.timerErr = nil, nil
+		m.timerGen++
+		m.clickUpLogin = InitialClickUpLogin(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, ClickUpProfile{})
+		m.currentView = ViewClickUpLogin
+		return m, m.clickUpLogin.Init()
+	case ClickUpReadyMsg:
+		m.ClickUpMenu = InitialClickUpMenu(m.clickUpLogin.token, m.clickUpLogin.refreshToken, m.clickUpLogin.user, msg.profile)
+		m.currentView = ViewClickUpMenu
+		return m, tea.Batch(m.ClickUpMenu.Init(), checkClickUpTimer(msg.profile))
+	case ClickUpTasksRequestMsg:
+		m.clickUpTasks = InitialClickUpTasks(m.clickUpNav.token, m.clickUpNav.refreshToken, m.clickUpNav.user, m.clickUpNav.clickup, msg.listID, msg.listName, true)
+		m.currentView = ViewClickUpTasks
+		return m, m.clickUpTasks.Init()
+	case ClickUpTaskRequestMsg:
+		m.clickUpTask = InitialClickUpTaskDetail(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup, msg.taskID, msg.fromNavigator)
+		m.currentView = ViewClickUpTaskDetail
+		return m, m.clickUpTask.Init()
+	case ClickUpCommentsRequestMsg:
+		m.comments = InitialClickUpComments(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup, msg.target)
+		m.commentsReturn = m.currentView
+		m.currentView = ViewClickUpComments
+		return m, m.comments.Init()
+	case ClickUpAttachmentsRequestMsg:
+		// The S3 browser has no ClickUp profile of its own; use the saved one.
+		profile := m.ClickUpMenu.clickup
+		if profile.WorkspaceID == "" {
+			profile, _, _ = LoadClickUpProfile(m.mainMenu.user)
+		}
+		m.attachments = InitialClickUpAttachments(m.mainMenu.token, m.mainMenu.refreshToken, m.mainMenu.user, profile, msg)
+		if profile.WorkspaceID == "" {
+			m.attachments.status = "Sign in to ClickUp from the main menu first."
+		}
+		m.attachmentsReturn = m.currentView
+		m.currentView = ViewClickUpAttachments
+		return m, m.attachments.Init()
+	case CloseClickUpAttachmentsMsg:
+		m.currentView = m.attachmentsReturn
+		if m.currentView == ViewClickUpMenu {
+			return m, m.ClickUpMenu.Init()
+		}
+		return m, nil
+	case CloseClickUpCommentsMsg:
+		m.currentView = m.commentsReturn
+		if m.currentView == ViewClickUpMenu {
+			return m, m.ClickUpMenu.Init()
+		}
+		return m, nil
+	case ShowClickUpTasksMsg:
+		m.currentView = ViewClickUpTasks
+		if msg.reload {
+			cmd := m.clickUpTasks.reload()
+			return m, cmd
+		}
+		return m, nil
+	case ShowClickUpNavigatorMsg:
+		m.currentView = ViewClickUpNavigator
+		return m, nil
+	case ShowClickUpMenuMsg:
+		m.currentView = ViewClickUpMenu
+		return m, m.ClickUpMenu.Init()
+	case ClickUpMenuMsg:
+		switch msg.choice {
+		case "Comments":
+			return m, func() tea.Msg { return ClickUpCommentsRequestMsg{} }
+		case "Attachments":
+			return m, func() tea.Msg { return ClickUpAttachmentsRequestMsg{} }
+		case "Docs":
+			m.docs = InitialClickUpDocs(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup)
+			m.currentView = ViewClickUpDocs
+			return m, m.docs.Init()
+		case "Goals":
+			m.goals = InitialClickUpGoals(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup)
+			m.currentView = ViewClickUpGoals
+			return m, m.goals.Init()
+		case "Webhooks":
+			m.webhooks = InitialClickUpWebhooks(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup)
+			m.currentView = ViewClickUpWebhooks
+			return m, m.webhooks.Init()
+		case "Members", "Users", "Guests", "User Groups (Teams)", "Roles", "Privacy & Access":
+			tab := map[string]peopleTab{"Guests": tabGuests, "User Groups (Teams)": tabGroups, "Roles": tabRoles, "Privacy & Access": tabAccess}[msg.choice]
+			m.people = InitialClickUpPeople(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup, tab)
+			m.currentView = ViewClickUpPeople
+			return m, m.people.Init()
+		case "Time Tracking":
+			m.timeTracking = InitialClickUpTimeTracking(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup)
+			m.currentView = ViewClickUpTimeTracking
+			return m, m.timeTracking.Init()
+		case "Tasks":
+			m.clickUpTasks = InitialClickUpTasks(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup, "", "", false)
+			m.currentView = ViewClickUpTasks
+			return m, m.clickUpTasks.Init()
+		case "Browse Hierarchy", "Workspaces", "Spaces", "Folders", "Lists", "Shared Hierarchy":
+			// Keep the navigator, and its cache, while the workspace stays the same.
+			var cmd tea.Cmd
+			if m.clickUpNav.clickup != m.ClickUpMenu.clickup {
+				m.clickUpNav = InitialClickUpNavigator(m.ClickUpMenu.token, m.ClickUpMenu.refreshToken, m.ClickUpMenu.user, m.ClickUpMenu.clickup)
+				cmd = m.clickUpNav.Init()
+			}
+			if msg.choice == "Shared Hierarchy" {
+				cmd = tea.Batch(cmd, m.clickUpNav.openShared())
+			}
+			m.currentView = ViewClickUpNavigator
+			return m, cmd
+		}
+	case DynamoTransferRequestMsg:
+		m.dynamoTransfer = InitialDynamoTransfer(m.dynamo.token, m.dynamo.refreshToken, m.dynamo.user, msg.kind, msg.table, msg.query)
+		m.currentView = ViewDynamoTransfer
+		return m, m.dynamoTransfer.Init()
+
+	case ShowDynamoExplorerMsg:
+		m.currentView = ViewDynamoExplorer
+		if msg.reload {
+			cmd := m.dynamo.reload()
+			return m, cmd
+		}
+		return m, nil
+
+	case ShowS3BrowserMsg:
+		m.currentView = ViewS3Browser
+		cmd := m.s3Browser.refresh()
+		return m, cmd
 	}
 
 	switch m.currentView {
@@ -108,12 +487,123 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		updatedOpenAIMenu, cmd := m.OpenAIMenu.Update(msg)
 		m.OpenAIMenu = updatedOpenAIMenu.(OpenAIMenu)
 		return m, cmd
+	case ViewDatabaseOperations:
+		updatedDBOps, cmd := m.dbOps.Update(msg)
+		m.dbOps = updatedDBOps.(DatabaseOperations)
+		return m, cmd
+	case ViewImageGeneration:
+		updatedImageGen, cmd := m.imageGen.Update(msg)
+		m.imageGen = updatedImageGen.(ImageGeneration)
+		return m, cmd
+	case ViewImageVision:
+		updatedImageVision, cmd := m.imageVision.Update(msg)
+		m.imageVision = updatedImageVision.(ImageVision)
+		return m, cmd
+	case ViewOpenAIUsage:
+		updatedUsage, cmd := m.usage.Update(msg)
+		m.usage = updatedUsage.(OpenAIUsage)
+		return m, cmd
+	case ViewS3Browser:
+		updatedS3Browser, cmd := m.s3Browser.Update(msg)
+		m.s3Browser = updatedS3Browser.(S3Browser)
+		return m, cmd
+	case ViewS3Transfers:
+		updatedS3Transfers, cmd := m.s3Transfers.Update(msg)
+		m.s3Transfers = updatedS3Transfers.(S3Transfers)
+		return m, cmd
+	case ViewS3Share:
+		updatedS3Share, cmd := m.s3Share.Update(msg)
+		m.s3Share = updatedS3Share.(S3Share)
+		return m, cmd
+	case ViewS3Bulk:
+		updatedS3Bulk, cmd := m.s3Bulk.Update(msg)
+		m.s3Bulk = updatedS3Bulk.(S3Bulk)
+		return m, cmd
+	case ViewRekognition:
+		updatedRekognition, cmd := m.rekognition.Update(msg)
+		m.rekognition = updatedRekognition.(RekognitionAnalysis)
+		return m, cmd
+	case ViewFaceCollections:
+		updatedFaces, cmd := m.faces.Update(msg)
+		m.faces = updatedFaces.(FaceCollections)
+		return m, cmd
+	case ViewRekognitionBatch:
+		updatedBatch, cmd := m.batch.Update(msg)
+		m.batch = updatedBatch.(RekognitionBatch)
+		return m, cmd
+	case ViewDynamoExplorer:
+		updatedDynamo, cmd := m.dynamo.Update(msg)
+		m.dynamo = updatedDynamo.(DynamoExplorer)
+		return m, cmd
+	case ViewDynamoEditor:
+		updatedEditor, cmd := m.dynamoEditor.Update(msg)
+		m.dynamoEditor = updatedEditor.(DynamoItemEditor)
+		return m, cmd
+	case ViewDynamoTransfer:
+		updatedTransfer, cmd := m.dynamoTransfer.Update(msg)
+		m.dynamoTransfer = updatedTransfer.(DynamoTransfer)
+		return m, cmd
+	case ViewClickUpLogin:
+		updatedLogin, cmd := m.clickUpLogin.Update(msg)
+		m.clickUpLogin = updatedLogin.(ClickUpLogin)
+		return m, cmd
+	case ViewClickUpNavigator:
+		updatedNav, cmd := m.clickUpNav.Update(msg)
+		m.clickUpNav = updatedNav.(ClickUpNavigator)
+		return m, cmd
+	case ViewClickUpTasks:
+		updatedTasks, cmd := m.clickUpTasks.Update(msg)
+		m.clickUpTasks = updatedTasks.(ClickUpTasks)
+		return m, cmd
+	case ViewClickUpTaskDetail:
+		updatedTask, cmd := m.clickUpTask.Update(msg)
+		m.clickUpTask = updatedTask.(ClickUpTaskDetail)
+		return m, cmd
+	case ViewClickUpQuickTask:
+		updatedQuickTask, cmd := m.quickTask.Update(msg)
+		m.quickTask = updatedQuickTask.(ClickUpQuickTask)
+		return m, cmd
+	case ViewClickUpComments:
+		updatedComments, cmd := m.comments.Update(msg)
+		m.comments = updatedComments.(ClickUpComments)
+		return m, cmd
+	case ViewClickUpTimeTracking:
+		updatedTime, cmd := m.timeTracking.Update(msg)
+		m.timeTracking = updatedTime.(ClickUpTimeTracking)
+		return m, cmd
+	case ViewClickUpAttachments:
+		updatedAttachments, cmd := m.attachments.Update(msg)
+		m.attachments = updatedAttachments.(ClickUpAttachments)
+		return m, cmd
+	case ViewClickUpDocs:
+		updatedDocs, cmd := m.docs.Update(msg)
+		m.docs = updatedDocs.(ClickUpDocs)
+		return m, cmd
+	case ViewClickUpGoals:
+		updatedGoals, cmd := m.goals.Update(msg)
+		m.goals = updatedGoals.(ClickUpGoals)
+		return m, cmd
+	case ViewClickUpWebhooks:
+		updatedWebhooks, cmd := m.webhooks.Update(msg)
+		m.webhooks = updatedWebhooks.(ClickUpWebhooks)
+		return m, cmd
+	case ViewClickUpPeople:
+		updatedPeople, cmd := m.people.Update(msg)
+		m.people = updatedPeople.(ClickUpPeople)
+		return m, cmd
 	}
 
 	return m, nil
 }
 
 func (m AppModel) View() string {
+	if m.currentView == ViewLogin {
+		return kittyReleases() + m.login.View()
+	}
+	return kittyReleases() + m.currentScreen() + "\n" + m.statusBar()
+}
+
+func (m AppModel) currentScreen() string {
 	switch m.currentView {
 	case ViewLogin:
 		return m.login.View()
@@ -127,7 +617,126 @@ func (m AppModel) View() string {
 		return m.PostgresMenu.View()
 	case ViewOpenAIMenu:
 		return m.OpenAIMenu.View()
+	case ViewDatabaseOperations:
+		return m.dbOps.View()
+	case ViewImageGeneration:
+		return m.imageGen.View()
+	case ViewImageVision:
+		return m.imageVision.View()
+	case ViewOpenAIUsage:
+		return m.usage.View()
+	case ViewS3Browser:
+		return m.s3Browser.View()
+	case ViewS3Transfers:
+		return m.s3Transfers.View()
+	case ViewS3Share:
+		return m.s3Share.View()
+	case ViewS3Bulk:
+		return m.s3Bulk.View()
+	case ViewRekognition:
+		return m.rekognition.View()
+	case ViewFaceCollections:
+		return m.faces.View()
+	case ViewRekognitionBatch:
+		return m.batch.View()
+	case ViewDynamoExplorer:
+		return m.dynamo.View()
+	case ViewDynamoEditor:
+		return m.dynamoEditor.View()
+	case ViewDynamoTransfer:
+		return m.dynamoTransfer.View()
+	case ViewClickUpLogin:
+		return m.clickUpLogin.View()
+	case ViewClickUpNavigator:
+		return m.clickUpNav.View()
+	case ViewClickUpTasks:
+		return m.clickUpTasks.View()
+	case ViewClickUpTaskDetail:
+		return m.clickUpTask.View()
+	case ViewClickUpQuickTask:
+		return m.quickTask.View()
+	case ViewClickUpComments:
+		return m.comments.View()
+	case ViewClickUpTimeTracking:
+		return m.timeTracking.View()
+	case ViewClickUpAttachments:
+		return m.attachments.View()
+	case ViewClickUpDocs:
+		return m.docs.View()
+	case ViewClickUpGoals:
+		return m.goals.View()
+	case ViewClickUpWebhooks:
+		return m.webhooks.View()
+	case ViewClickUpPeople:
+		return m.people.View()
 	default:
 		return "Unknown view"
 	}
 }
+
+// openQuickTask shows the quick task form, prefilled from the current screen.
+// Lists already loaded for the same workspace are reused.
+func (m *AppModel) openQuickTask() tea.Cmd {
+	// A missing profile leaves the form explaining how to sign in.
+	profile, _, _ := LoadClickUpProfile(m.mainMenu.user)
+
+	previous := m.quickTask
+	m.quickTask = InitialClickUpQuickTask(m.mainMenu.token, m.mainMenu.refreshToken, m.mainMenu.user, profile, m.taskDraft())
+	if previous.clickup == profile && previous.lists != nil {
+		m.quickTask.useLists(previous.lists, previous.members, previous.lastList)
+	}
+	m.quickTaskReturn = m.currentView
+	m.currentView = ViewClickUpQuickTask
+	return m.quickTask.Init()
+}
+
+// taskDraft is what the current screen offers to prefill a quick task.
+func (m AppModel) taskDraft() ClickUpTaskDraft {
+	switch m.currentView {
+	case ViewDatabaseOperations:
+		return m.dbOps.taskDraft()
+	case ViewS3Browser:
+		return m.s3Browser.taskDraft()
+	case ViewDynamoExplorer:
+		return m.dynamo.taskDraft()
+	case ViewClickUpNavigator:
+		return m.clickUpNav.taskDraft()
+	case ViewClickUpTasks:
+		return m.clickUpTasks.taskDraft()
+	case ViewClickUpTaskDetail:
+		return m.clickUpTask.taskDraft()
+	case ViewClickUpComments:
+		return m.comments.taskDraft()
+	case ViewClickUpDocs:
+		return m.docs.taskDraft()
+	}
+	return ClickUpTaskDraft{}
+}
+
+// statusBar is shown below every screen once logged in.
+func (m AppModel) statusBar() string {
+	status := m.budgetStatus() + "  " + helpStyle.Render(quickTaskKey+": new ClickUp task")
+	if timer := timerStatus(m.timer, m.timerErr); timer != "" {
+		status = timer + "  " + status
+	}
+	return status
+}
+
+func (m AppModel) budgetStatus() string {
+	spent := MonthToDateCost()
+	budget := m.config.OpenAIBudget
+
+	if budget <= 0 {
+		return statusBarStyle.Render(fmt.Sprintf("OpenAI this month: $%.2f", spent))
+	}
+
+	status := fmt.Sprintf("OpenAI this month: $%.2f / $%.2f", spent, budget)
+	switch {
+	case spent >= budget:
+		return statusWarningStyle.Render(status + " - budget exceeded")
+	case spent >= budget*0.8:
+		return statusWarningStyle.Render(status + " - over 80% of budget")
+	default:
+		return statusBarStyle.Render(status)
+	}
+}
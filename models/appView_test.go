@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestClickUpSignOutClearsCachedCredential(t *testing.T) {
+	profile := ClickUpProfile{Token: "pk_old", WorkspaceID: "9001", Username: "ada"}
+	m := AppModel{currentView: ViewClickUpMenu}
+	m.ClickUpMenu = InitialClickUpMenu("tok", "refresh", User{Name: "ada"}, profile)
+	m.clickUpNav.clickup = profile
+	m.timer = &ClickUpTimeEntry{ID: "te1", Duration: -1}
+	gen := m.timerGen
+
+	next, _ := m.Update(ClickUpSignedOutMsg{})
+	m = next.(AppModel)
+
+	if m.ClickUpMenu.clickup != (ClickUpProfile{}) || m.clickUpNav.clickup != (ClickUpProfile{}) {
+		t.Errorf("the old profile is still cached: menu %+v, navigator %+v", m.ClickUpMenu.clickup, m.clickUpNav.clickup)
+	}
+	if m.timer != nil {
+		t.Errorf("the status bar still shows timer %+v", m.timer)
+	}
+	if m.currentView != ViewClickUpLogin || m.clickUpLogin.user.Name != "ada" || m.clickUpLogin.profile.Token != "" {
+		t.Errorf("sign-out didn't return to an empty sign-in: view %d, profile %+v", m.currentView, m.clickUpLogin.profile)
+	}
+	if _, cmd := m.Update(ClickUpTimerTickMsg{gen: gen}); cmd != nil {
+		t.Error("the old timer kept ticking")
+	}
+}
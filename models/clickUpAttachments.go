@@ -0,0 +1,377 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type attachmentsMode int
+
+const (
+	attachmentsBrowse attachmentsMode = iota
+	// attachmentsTask asks which task to show, or to attach the S3 object to.
+	attachmentsTask
+	attachmentsDownload
+	attachmentsUpload
+	attachmentsToS3
+	attachmentsFromS3
+)
+
+// ClickUpAttachments lists a task's attachments and moves files between the
+// task, the local disk and S3.
+type ClickUpAttachments struct {
+	mode        attachmentsMode
+	taskID      string
+	taskName    string
+	attachments []ClickUpAttachment
+	list        table.Model
+	input       textinput.Model
+	// s3Bucket and s3Key are an object from the S3 browser waiting for the
+	// task to attach it to.
+	s3Bucket string
+	s3Key    string
+	loading  bool
+	status   string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+// ClickUpAttachmentsRequestMsg opens a task's attachments; an empty task asks
+// for one. With an S3 object it is attached to the task chosen.
+type ClickUpAttachmentsRequestMsg struct {
+	taskID   string
+	taskName string
+	bucket   string
+	key      string
+}
+
+// CloseClickUpAttachmentsMsg returns to the screen the attachments were opened from.
+type CloseClickUpAttachmentsMsg struct{}
+
+type ClickUpAttachmentsMsg struct {
+	task ClickUpTask
+	// done is the status to show once loaded, e.g. "Uploaded plan.pdf."
+	done string
+	err  error
+}
+
+type ClickUpAttachmentDoneMsg struct {
+	done string
+	err  error
+	// reload is set when the task's attachments changed.
+	reload bool
+}
+
+func InitialClickUpAttachments(token string, refreshToken string, user User, clickup ClickUpProfile, request ClickUpAttachmentsRequestMsg) ClickUpAttachments {
+	m := ClickUpAttachments{
+		taskID:   request.taskID,
+		taskName: request.taskName,
+		s3Bucket: request.bucket,
+		s3Key:    request.key,
+		list: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Name", Width: 40},
+				{Title: "Size", Width: 10},
+				{Title: "Added", Width: 16},
+				{Title: "By", Width: 20},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		input:        newFormInput("", 300, 60),
+		loading:      true,
+		status:       "Loading attachments...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+	if m.taskID == "" || m.s3Key != "" {
+		m.askTask()
+	}
+	return m
+}
+
+func (m ClickUpAttachments) Init() tea.Cmd {
+	if m.mode == attachmentsTask {
+		return tea.Batch(tea.SetWindowTitle("ClickUp Attachments"), textinput.Blink)
+	}
+	return tea.Batch(tea.SetWindowTitle("ClickUp Attachments"), m.load(""))
+}
+
+func (m *ClickUpAttachments) askTask() {
+	m.mode = attachmentsTask
+	m.loading = false
+	m.status = ""
+	m.input.Placeholder = "Task ID"
+	m.input.SetValue(m.taskID)
+	m.input.Focus()
+}
+
+func (m ClickUpAttachments) load(done string) tea.Cmd {
+	clickup, taskID := m.clickup, m.taskID
+	return func() tea.Msg {
+		task, err := ListClickUpAttachments(clickup, taskID)
+		return ClickUpAttachmentsMsg{task: task, done: done, err: err}
+	}
+}
+
+func (m *ClickUpAttachments) showAttachments() {
+	rows := make([]table.Row, len(m.attachments))
+	for i, a := range m.attachments {
+		rows[i] = table.Row{a.Title, formatBytes(a.Size), a.Date.Local().Format("2006-01-02 15:04"), a.User.Username}
+	}
+	m.list.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.list.Cursor(); c < 0 || c >= len(rows) {
+		m.list.SetCursor(0)
+	}
+}
+
+func (m ClickUpAttachments) selected() (ClickUpAttachment, bool) {
+	i := m.list.Cursor()
+	if i < 0 || i >= len(m.attachments) {
+		return ClickUpAttachment{}, false
+	}
+	return m.attachments[i], true
+}
+
+func (m *ClickUpAttachments) prompt(mode attachmentsMode, placeholder string, value string) tea.Cmd {
+	m.mode = mode
+	m.status = ""
+	m.input.Placeholder = placeholder
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func attachmentDone(done string, reload bool, f func() error) tea.Cmd {
+	return func() tea.Msg {
+		return ClickUpAttachmentDoneMsg{done: done, reload: reload, err: f()}
+	}
+}
+
+// submit carries out what the input was asked for.
+func (m *ClickUpAttachments) submit() tea.Cmd {
+	text := strings.TrimSpace(m.input.Value())
+	mode := m.mode
+	m.input.Blur()
+	m.mode = attachmentsBrowse
+	if text == "" {
+		if mode == attachmentsTask && m.taskID == "" {
+			return func() tea.Msg { return CloseClickUpAttachmentsMsg{} }
+		}
+		return nil
+	}
+
+	clickup, token, taskID := m.clickup, m.token, m.taskID
+	a, _ := m.selected()
+	m.loading = true
+
+	switch mode {
+	case attachmentsTask:
+		if text != m.taskID {
+			m.taskName = ""
+			m.attachments = nil
+			m.showAttachments()
+		}
+		m.taskID = text
+		if m.s3Key == "" {
+			m.status = "Loading attachments..."
+			return m.load("")
+		}
+		bucket, key := m.s3Bucket, m.s3Key
+		m.s3Bucket, m.s3Key = "", ""
+		m.status = fmt.Sprintf("Attaching s3://%s/%s...", bucket, key)
+		return attachmentDone(fmt.Sprintf("Attached s3://%s/%s.", bucket, key), true, func() error {
+			return AttachS3Object(clickup, token, bucket, key, text)
+		})
+
+	case attachmentsDownload:
+		m.status = "Downloading " + a.Title + "..."
+		return func() tea.Msg {
+			n, err := DownloadClickUpAttachment(a, text)
+			return ClickUpAttachmentDoneMsg{done: fmt.Sprintf("Saved %s (%s).", text, formatBytes(n)), err: err}
+		}
+
+	case attachmentsUpload:
+		m.status = "Uploading " + text + "..."
+		return attachmentDone("Uploaded "+text+".", true, func() error {
+			return UploadClickUpAttachmentFile(clickup, taskID, text)
+		})
+
+	case attachmentsToS3:
+		bucket, key, err := parseS3Object(text, a.Title)
+		if err != nil {
+			m.loading = false
+			m.status = err.Error()
+			return nil
+		}
+		m.status = fmt.Sprintf("Copying %s to s3://%s/%s...", a.Title, bucket, key)
+		return attachmentDone(fmt.Sprintf("Copied %s to s3://%s/%s.", a.Title, bucket, key), false, func() error {
+			return CopyClickUpAttachmentToS3(token, a, bucket, key)
+		})
+
+	case attachmentsFromS3:
+		bucket, key, err := parseS3Object(text, "")
+		if err != nil {
+			m.loading = false
+			m.status = err.Error()
+			return nil
+		}
+		m.status = fmt.Sprintf("Attaching s3://%s/%s...", bucket, key)
+		return attachmentDone(fmt.Sprintf("Attached s3://%s/%s.", bucket, key), true, func() error {
+			return AttachS3Object(clickup, token, bucket, key, taskID)
+		})
+	}
+	m.loading = false
+	return nil
+}
+
+func (m ClickUpAttachments) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpAttachmentsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.taskName = msg.task.Name
+		m.attachments = msg.task.Attachments
+		m.showAttachments()
+		m.status = msg.done
+		if m.status == "" {
+			m.status = countOf(len(m.attachments), "attachment")
+		}
+		return m, nil
+
+	case ClickUpAttachmentDoneMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if msg.reload {
+			m.loading = true
+			return m, m.load(msg.done)
+		}
+		m.status = msg.done
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		if m.mode != attachmentsBrowse {
+			switch msg.String() {
+			case "esc":
+				if m.mode == attachmentsTask && m.taskID == "" {
+					return m, func() tea.Msg { return CloseClickUpAttachmentsMsg{} }
+				}
+				m.mode = attachmentsBrowse
+				m.input.Blur()
+				m.s3Bucket, m.s3Key = "", ""
+				m.status = ""
+				return m, nil
+			case "enter":
+				return m, m.submit()
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return CloseClickUpAttachmentsMsg{} }
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			m.status = "Refreshing..."
+			return m, m.load("")
+		case "T":
+			m.askTask()
+			return m, textinput.Blink
+		case "u":
+			return m, m.prompt(attachmentsUpload, "Local file to attach", "")
+		case "S":
+			return m, m.prompt(attachmentsFromS3, "S3 object to attach, e.g. s3://bucket/designs/logo.fig", "s3://"+defaultS3Bucket+"/")
+		}
+
+		a, ok := m.selected()
+		if !ok {
+			break
+		}
+		switch msg.String() {
+		case "enter", "d":
+			return m, m.prompt(attachmentsDownload, "Save as", a.Title)
+		case "s":
+			return m, m.prompt(attachmentsToS3, "Copy to s3://bucket/key, or a folder ending in /", fmt.Sprintf("s3://%s/clickup/%s/%s", defaultS3Bucket, m.taskID, a.Title))
+		case "o":
+			if err := openBrowser(a.URL); err != nil {
+				m.status = fmt.Sprintf("Error: %v", err)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m ClickUpAttachments) View() string {
+	var b strings.Builder
+
+	title := "\nAttachments"
+	if m.taskName != "" {
+		title += " • " + m.taskName
+	} else if m.taskID != "" {
+		title += " • " + m.taskID
+	}
+	b.WriteString(title + "\n\n")
+
+	if m.mode == attachmentsTask {
+		if m.s3Key != "" {
+			fmt.Fprintf(&b, "Attach s3://%s/%s to which task?\n\n", m.s3Bucket, m.s3Key)
+		}
+		b.WriteString(m.input.View() + "\n\n")
+		b.WriteString(helpStyle.Render("enter: ok • esc: cancel"))
+	} else {
+		b.WriteString(m.list.View())
+		b.WriteString("\n\n")
+		if m.mode != attachmentsBrowse {
+			b.WriteString(m.input.View() + "\n\n")
+			b.WriteString(helpStyle.Render("enter: ok • esc: cancel"))
+		} else {
+			if !m.loading && len(m.attachments) == 0 {
+				b.WriteString("No attachments on this task.\n\n")
+			}
+			b.WriteString(helpStyle.Render("d: download • u: upload file • s: copy to S3 • S: attach from S3 • o: open in browser"))
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("T: other task • r: refresh • esc: back"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
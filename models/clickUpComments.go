@@ -0,0 +1,615 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mentionSuggestions is how many members @ autocompletion offers at once.
+const mentionSuggestions = 5
+
+var (
+	commentAuthorStyle   = lipgloss.NewStyle().Bold(true)
+	commentResolvedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+)
+
+type commentsMode int
+
+const (
+	commentsBrowse commentsMode = iota
+	// commentsTarget asks which task, list or view to show.
+	commentsTarget
+	commentsCompose
+	commentsAssign
+)
+
+// commentEntry is one comment in the flattened thread view.
+type commentEntry struct {
+	comment ClickUpComment
+	// thread is the top-level comment a reply belongs to; empty for those.
+	thread string
+}
+
+// ClickUpComments shows the comment threads on a task, list or chat view,
+// oldest first, and lets the user reply, edit, delete, resolve and assign.
+type ClickUpComments struct {
+	mode    commentsMode
+	target  ClickUpCommentTarget
+	threads []ClickUpComment
+	replies map[string][]ClickUpComment
+	entries []commentEntry
+	cursor  int
+	// older is set while the target may have comments before the oldest shown.
+	older   bool
+	members []ClickUpUser
+	view    viewport.Model
+	input   textinput.Model
+	// composer writes new comments, replies and edits; replyTo and editing
+	// say which, with neither meaning a new comment.
+	composer   textarea.Model
+	replyTo    string
+	editing    *ClickUpComment
+	mentions   []ClickUpUser
+	confirming bool
+	loading    bool
+	status     string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+// ClickUpCommentsRequestMsg opens the comments on a target; an empty target
+// asks for one.
+type ClickUpCommentsRequestMsg struct {
+	target ClickUpCommentTarget
+}
+
+// CloseClickUpCommentsMsg returns to the screen the comments were opened from.
+type CloseClickUpCommentsMsg struct{}
+
+type ClickUpCommentsMsg struct {
+	threads []ClickUpComment
+	replies map[string][]ClickUpComment
+	// before is set when threads are a page older than those shown.
+	before  bool
+	members []ClickUpUser
+	// done is the status to show once loaded, e.g. "Reply posted."
+	done string
+	err  error
+}
+
+type ClickUpCommentChangedMsg struct {
+	done string
+	err  error
+}
+
+func InitialClickUpComments(token string, refreshToken string, user User, clickup ClickUpProfile, target ClickUpCommentTarget) ClickUpComments {
+	c := textarea.New()
+	c.Placeholder = "Write a comment; @ mentions a member"
+	c.SetWidth(100)
+	c.SetHeight(5)
+	c.CharLimit = 0
+	c.ShowLineNumbers = false
+
+	m := ClickUpComments{
+		target:       target,
+		replies:      make(map[string][]ClickUpComment),
+		view:         viewport.New(100, 20),
+		input:        newFormInput("", 200, 60),
+		composer:     c,
+		loading:      true,
+		status:       "Loading comments...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+	if target.ID == "" {
+		m.mode = commentsTarget
+		m.loading = false
+		m.status = ""
+		m.input.Placeholder = "task ID, list ID or view ID"
+		m.input.Focus()
+	}
+	return m
+}
+
+func (m ClickUpComments) Init() tea.Cmd {
+	if m.mode == commentsTarget {
+		return tea.Batch(tea.SetWindowTitle("ClickUp Comments"), textinput.Blink)
+	}
+	return tea.Batch(tea.SetWindowTitle("ClickUp Comments"), m.load(false, ""))
+}
+
+// load fetches the newest comments, or with before the page older than those
+// shown, along with their replies. Members are fetched the first time.
+func (m ClickUpComments) load(before bool, done string) tea.Cmd {
+	clickup, target, needMembers := m.clickup, m.target, m.members == nil
+	var oldest *ClickUpComment
+	if before && len(m.threads) > 0 {
+		oldest = &m.threads[len(m.threads)-1]
+	}
+	return func() tea.Msg {
+		threads, err := ListClickUpComments(clickup, target, oldest)
+		if err != nil {
+			return ClickUpCommentsMsg{err: err}
+		}
+		replies := make(map[string][]ClickUpComment)
+		for _, c := range threads {
+			if c.ReplyCount == 0 {
+				continue
+			}
+			if replies[c.ID], err = ListClickUpReplies(clickup, c.ID); err != nil {
+				return ClickUpCommentsMsg{err: err}
+			}
+		}
+		msg := ClickUpCommentsMsg{threads: threads, replies: replies, before: before, done: done}
+		if needMembers {
+			msg.members, msg.err = ClickUpMembers(clickup)
+		}
+		return msg
+	}
+}
+
+// reload refetches the newest comments, keeping the selection where it can.
+func (m *ClickUpComments) reload(done string) tea.Cmd {
+	m.loading = true
+	m.status = "Refreshing..."
+	return m.load(false, done)
+}
+
+func (m ClickUpComments) selected() (commentEntry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return commentEntry{}, false
+	}
+	return m.entries[m.cursor], true
+}
+
+// render lays the threads out oldest first with replies indented under them,
+// and scrolls the viewport to keep the selected comment in sight.
+func (m *ClickUpComments) render() {
+	m.entries = nil
+	for i := len(m.threads) - 1; i >= 0; i-- {
+		c := m.threads[i]
+		m.entries = append(m.entries, commentEntry{comment: c})
+		for _, r := range m.replies[c.ID] {
+			m.entries = append(m.entries, commentEntry{comment: r, thread: c.ID})
+		}
+	}
+	m.cursor = max(0, min(m.cursor, len(m.entries)-1))
+
+	var b strings.Builder
+	top, bottom := 0, 0
+	for i, e := range m.entries {
+		indent := ""
+		if e.thread != "" {
+			indent = "      "
+		}
+		if i == m.cursor {
+			top = strings.Count(b.String(), "\n")
+		}
+
+		c := e.comment
+		header := commentAuthorStyle.Render(c.User.Username) + " " + blurredStyle.Render(c.Date.Local().Format("2006-01-02 15:04"))
+		if c.Assignee != nil {
+			header += " → " + c.Assignee.Username
+		}
+		if c.Resolved {
+			header += " " + commentResolvedStyle.Render("✓ resolved")
+		}
+		marker := "  "
+		if i == m.cursor {
+			marker = focusedStyle.Render("> ")
+		}
+		b.WriteString(indent + marker + header + "\n")
+		for _, line := range strings.Split(strings.TrimRight(c.Text, "\n"), "\n") {
+			b.WriteString(indent + "    " + line + "\n")
+		}
+		if i == m.cursor {
+			bottom = strings.Count(b.String(), "\n")
+		}
+		b.WriteString("\n")
+	}
+	m.view.SetContent(b.String())
+
+	if top < m.view.YOffset {
+		m.view.SetYOffset(top)
+	} else if bottom > m.view.YOffset+m.view.Height {
+		m.view.SetYOffset(bottom - m.view.Height)
+	}
+}
+
+// compose opens the composer for a new comment, a reply or an edit.
+func (m *ClickUpComments) compose(replyTo string, editing *ClickUpComment) tea.Cmd {
+	m.mode = commentsCompose
+	m.replyTo, m.editing = replyTo, editing
+	m.composer.Reset()
+	if editing != nil {
+		m.composer.SetValue(editing.Text)
+	}
+	m.mentions = nil
+	m.status = ""
+	return m.composer.Focus()
+}
+
+// mentionPrefix is the partial @name being typed at the end of the comment.
+func (m ClickUpComments) mentionPrefix() (string, bool) {
+	text := m.composer.Value()
+	i := strings.LastIndex(text, "@")
+	if i < 0 || (i > 0 && !strings.ContainsAny(text[i-1:i], " \n\t(")) {
+		return "", false
+	}
+	prefix := text[i+1:]
+	if strings.ContainsAny(prefix, "\n") || strings.Count(prefix, " ") > 1 {
+		return "", false
+	}
+	return prefix, true
+}
+
+// suggestMentions offers members whose names match the @name being typed.
+func (m *ClickUpComments) suggestMentions() {
+	m.mentions = nil
+	prefix, ok := m.mentionPrefix()
+	if !ok {
+		return
+	}
+	for _, u := range m.members {
+		if _, ok := fuzzyScore(prefix, u.Username); ok && u.Username != "" {
+			m.mentions = append(m.mentions, u)
+		}
+	}
+	slices.SortStableFunc(m.mentions, func(a, b ClickUpUser) int {
+		sa, _ := fuzzyScore(prefix, a.Username)
+		sb, _ := fuzzyScore(prefix, b.Username)
+		return sb - sa
+	})
+	if len(m.mentions) > mentionSuggestions {
+		m.mentions = m.mentions[:mentionSuggestions]
+	}
+}
+
+func (m *ClickUpComments) completeMention() {
+	prefix, ok := m.mentionPrefix()
+	if !ok || len(m.mentions) == 0 {
+		return
+	}
+	text := m.composer.Value()
+	m.composer.SetValue(text[:len(text)-len(prefix)] + m.mentions[0].Username + " ")
+	m.mentions = nil
+}
+
+func (m *ClickUpComments) send() tea.Cmd {
+	text := strings.TrimSpace(m.composer.Value())
+	if text == "" {
+		m.status = "The comment is empty."
+		return nil
+	}
+	clickup, target, members := m.clickup, m.target, m.members
+	m.mode = commentsBrowse
+	m.composer.Blur()
+	m.loading = true
+
+	switch {
+	case m.editing != nil:
+		m.status = "Saving..."
+		updated := *m.editing
+		updated.Text = text
+		return changed("Comment edited.", func() error { return UpdateClickUpComment(clickup, updated) })
+	case m.replyTo != "":
+		m.status = "Replying..."
+		replyTo := m.replyTo
+		return changed("Reply posted.", func() error { return ReplyToClickUpComment(clickup, replyTo, text, members) })
+	default:
+		m.status = "Posting..."
+		return changed("Comment posted.", func() error { return CreateClickUpComment(clickup, target, text, members) })
+	}
+}
+
+func changed(done string, f func() error) tea.Cmd {
+	return func() tea.Msg { return ClickUpCommentChangedMsg{done: done, err: f()} }
+}
+
+func (m ClickUpComments) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpCommentsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if msg.members != nil {
+			m.members = msg.members
+		}
+		// ClickUp sends comments 25 at a time, so a full page may have more before it.
+		m.older = len(msg.threads) == clickUpCommentPageSize
+		first := m.threads == nil
+		if msg.before {
+			for _, c := range msg.threads {
+				if !slices.ContainsFunc(m.threads, func(t ClickUpComment) bool { return t.ID == c.ID }) {
+					m.threads = append(m.threads, c)
+				}
+			}
+			m.cursor = 0
+		} else {
+			m.threads = msg.threads
+		}
+		for id, r := range msg.replies {
+			m.replies[id] = r
+		}
+		m.status = msg.done
+		m.render()
+		if first {
+			// Start at the newest comment, like a chat.
+			m.cursor = len(m.entries) - 1
+			m.render()
+		}
+		if len(m.entries) == 0 {
+			m.status = "No comments yet. c: write the first one."
+		}
+		return m, nil
+
+	case ClickUpCommentChangedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		cmd := m.reload(msg.done)
+		return m, cmd
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case commentsTarget, commentsAssign:
+			switch msg.String() {
+			case "esc":
+				if m.mode == commentsTarget {
+					return m, func() tea.Msg { return CloseClickUpCommentsMsg{} }
+				}
+				m.mode = commentsBrowse
+				m.input.Blur()
+				m.status = ""
+				return m, nil
+			case "enter":
+				return m, m.submitInput()
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+
+		case commentsCompose:
+			switch msg.String() {
+			case "esc":
+				m.mode = commentsBrowse
+				m.composer.Blur()
+				m.status = ""
+				return m, nil
+			case "ctrl+s":
+				return m, m.send()
+			case "tab":
+				m.completeMention()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.composer, cmd = m.composer.Update(msg)
+			m.suggestMentions()
+			return m, cmd
+		}
+
+		if m.confirming {
+			m.confirming = false
+			if msg.String() != "y" && msg.String() != "Y" {
+				m.status = "Cancelled."
+				return m, nil
+			}
+			e, _ := m.selected()
+			clickup := m.clickup
+			m.loading = true
+			m.status = "Deleting..."
+			return m, changed("Comment deleted.", func() error { return DeleteClickUpComment(clickup, e.comment.ID) })
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return CloseClickUpCommentsMsg{} }
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		e, ok := m.selected()
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.render()
+			}
+			return m, nil
+		case "down", "j":
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+				m.render()
+			}
+			return m, nil
+		case "R":
+			cmd := m.reload("")
+			return m, cmd
+		case "p":
+			if !m.older {
+				m.status = "No older comments."
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Loading older comments..."
+			return m, m.load(true, "")
+		case "c":
+			return m, m.compose("", nil)
+		}
+		if !ok {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "r":
+			thread := e.thread
+			if thread == "" {
+				thread = e.comment.ID
+			}
+			return m, m.compose(thread, nil)
+		case "e":
+			c := e.comment
+			return m, m.compose("", &c)
+		case "x":
+			m.confirming = true
+			m.status = fmt.Sprintf("Delete this comment by %s? (y/n)", e.comment.User.Username)
+			return m, nil
+		case " ":
+			updated := e.comment
+			updated.Resolved = !updated.Resolved
+			done := "Comment resolved."
+			if !updated.Resolved {
+				done = "Comment reopened."
+			}
+			clickup := m.clickup
+			m.loading = true
+			m.status = "Saving..."
+			return m, changed(done, func() error { return UpdateClickUpComment(clickup, updated) })
+		case "a":
+			m.mode = commentsAssign
+			m.input.Placeholder = "Assign to: name, email or me"
+			m.input.Reset()
+			if e.comment.Assignee != nil {
+				m.input.SetValue(e.comment.Assignee.Username)
+			}
+			m.status = ""
+			return m, m.input.Focus()
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.view, cmd = m.view.Update(msg)
+	return m, cmd
+}
+
+func (m *ClickUpComments) submitInput() tea.Cmd {
+	text := strings.TrimSpace(m.input.Value())
+	if m.mode == commentsTarget {
+		target, err := parseCommentTarget(text)
+		if err != nil {
+			m.status = err.Error()
+			return nil
+		}
+		m.target = target
+		m.mode = commentsBrowse
+		m.input.Blur()
+		m.loading = true
+		m.status = "Loading comments..."
+		return m.load(false, "")
+	}
+
+	if text == "" {
+		m.mode = commentsBrowse
+		m.input.Blur()
+		return nil
+	}
+	id, err := resolveClickUpMember(m.members, m.clickup, text)
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	e, _ := m.selected()
+	updated := e.comment
+	updated.Assignee = &ClickUpUser{ID: id}
+	clickup := m.clickup
+	m.mode = commentsBrowse
+	m.input.Blur()
+	m.loading = true
+	m.status = "Assigning..."
+	return changed("Comment assigned.", func() error { return UpdateClickUpComment(clickup, updated) })
+}
+
+// taskDraft turns the selected comment into a quick task quoting it.
+func (m ClickUpComments) taskDraft() ClickUpTaskDraft {
+	e, ok := m.selected()
+	if !ok || m.mode != commentsBrowse {
+		return ClickUpTaskDraft{}
+	}
+	c := e.comment
+	description := quoteMarkdown(c.Text) + "\n\n"
+	description += fmt.Sprintf("Comment by %s on %s %s", c.User.Username, m.target.Kind, m.target.ID)
+	if m.target.Kind == "task" {
+		description = fmt.Sprintf("%s: https://app.clickup.com/t/%s", description, m.target.ID)
+	}
+	return ClickUpTaskDraft{Title: draftTitle(c.Text), Description: description}
+}
+
+func (m ClickUpComments) View() string {
+	var b strings.Builder
+
+	title := "Comments"
+	if m.target.ID != "" {
+		name := m.target.Name
+		if name == "" {
+			name = m.target.ID
+		}
+		title = fmt.Sprintf("Comments on %s %s", m.target.Kind, name)
+	}
+	b.WriteString("\n" + breadcrumbStyle.Render(title) + "\n\n")
+
+	switch m.mode {
+	case commentsTarget:
+		b.WriteString(m.input.View() + "\n\n")
+		b.WriteString(helpStyle.Render("enter: open • esc: back"))
+
+	case commentsCompose:
+		b.WriteString(m.view.View() + "\n\n")
+		switch {
+		case m.editing != nil:
+			b.WriteString("Editing comment\n")
+		case m.replyTo != "":
+			b.WriteString("Replying in thread\n")
+		}
+		b.WriteString(m.composer.View() + "\n")
+		for i, u := range m.mentions {
+			line := "  @" + u.Username
+			if i == 0 {
+				line = focusedStyle.Render("> @" + u.Username)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n" + helpStyle.Render("ctrl+s: send • tab: complete @mention • esc: cancel"))
+
+	default:
+		b.WriteString(m.view.View() + "\n\n")
+		if m.mode == commentsAssign {
+			b.WriteString(m.input.View() + "\n\n")
+			b.WriteString(helpStyle.Render("enter: assign • esc: cancel"))
+		} else {
+			b.WriteString(helpStyle.Render("c: comment • r: reply • e: edit • x: delete • space: resolve • a: assign"))
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("↑/↓: select • p: older comments • R: refresh • esc: back"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
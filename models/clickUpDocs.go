@@ -0,0 +1,530 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type docsLevel int
+
+const (
+	docsList docsLevel = iota
+	docsPages
+	docsPage
+)
+
+type docsInput int
+
+const (
+	docsNoInput docsInput = iota
+	docsFilter
+	// docsNewPage asks for the name of a page before its content is written.
+	docsNewPage
+)
+
+// docPageRow is a page in the flattened page tree.
+type docPageRow struct {
+	page  ClickUpDocPage
+	depth int
+}
+
+// ClickUpDocs browses the workspace's Docs and their pages, shows pages as
+// rendered markdown and edits them in $EDITOR.
+type ClickUpDocs struct {
+	level     docsLevel
+	docs      []ClickUpDoc
+	filter    string
+	shown     []ClickUpDoc
+	docList   table.Model
+	doc       ClickUpDoc
+	pages     []docPageRow
+	pageList  table.Model
+	page      ClickUpDocPage
+	view      viewport.Model
+	inputMode docsInput
+	input     textinput.Model
+	// newParent and newName describe the page being written in $EDITOR;
+	// editing is set while the open page is.
+	newParent string
+	newName   string
+	creating  bool
+	editing   bool
+	loading   bool
+	status    string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpDocsMsg struct {
+	docs []ClickUpDoc
+	err  error
+}
+
+type ClickUpDocPagesMsg struct {
+	docID string
+	pages []ClickUpDocPage
+	err   error
+}
+
+type ClickUpDocPageMsg struct {
+	page ClickUpDocPage
+	// done is the status to show once loaded, e.g. "Page saved."
+	done    string
+	created bool
+	err     error
+}
+
+func InitialClickUpDocs(token string, refreshToken string, user User, clickup ClickUpProfile) ClickUpDocs {
+	return ClickUpDocs{
+		docList: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Doc", Width: 60},
+				{Title: "Updated", Width: 16},
+			}),
+			table.WithHeight(20),
+			table.WithFocused(true),
+		),
+		pageList: table.New(
+			table.WithColumns([]table.Column{{Title: "Page", Width: 80}}),
+			table.WithHeight(20),
+			table.WithFocused(true),
+		),
+		view:         viewport.New(100, 25),
+		input:        newFormInput("", 200, 60),
+		loading:      true,
+		status:       "Loading Docs...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+}
+
+func (m ClickUpDocs) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp Docs"), m.loadDocs())
+}
+
+func (m ClickUpDocs) loadDocs() tea.Cmd {
+	clickup := m.clickup
+	return func() tea.Msg {
+		docs, err := ListClickUpDocs(clickup)
+		return ClickUpDocsMsg{docs: docs, err: err}
+	}
+}
+
+func (m ClickUpDocs) loadPages(docID string) tea.Cmd {
+	clickup := m.clickup
+	return func() tea.Msg {
+		pages, err := ListClickUpDocPages(clickup, docID)
+		return ClickUpDocPagesMsg{docID: docID, pages: pages, err: err}
+	}
+}
+
+func (m ClickUpDocs) loadPage(pageID string, done string) tea.Cmd {
+	clickup, docID := m.clickup, m.doc.ID
+	return func() tea.Msg {
+		page, err := GetClickUpDocPage(clickup, docID, pageID)
+		return ClickUpDocPageMsg{page: page, done: done, err: err}
+	}
+}
+
+func (m *ClickUpDocs) showDocs() {
+	m.shown = nil
+	filter := strings.ToLower(m.filter)
+	for _, d := range m.docs {
+		if strings.Contains(strings.ToLower(d.Name), filter) {
+			m.shown = append(m.shown, d)
+		}
+	}
+	rows := make([]table.Row, len(m.shown))
+	for i, d := range m.shown {
+		updated := d.DateUpdated
+		if updated.IsZero() {
+			updated = d.DateCreated
+		}
+		when := ""
+		if !updated.IsZero() {
+			when = updated.Local().Format("2006-01-02 15:04")
+		}
+		rows[i] = table.Row{d.Name, when}
+	}
+	m.docList.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.docList.Cursor(); c < 0 || c >= len(rows) {
+		m.docList.SetCursor(0)
+	}
+}
+
+// flattenPages lists a page tree depth first with each page's depth.
+func flattenPages(pages []ClickUpDocPage, depth int, rows []docPageRow) []docPageRow {
+	for _, p := range pages {
+		rows = append(rows, docPageRow{page: p, depth: depth})
+		rows = flattenPages(p.Pages, depth+1, rows)
+	}
+	return rows
+}
+
+func (m *ClickUpDocs) showPages(pages []ClickUpDocPage) {
+	m.pages = flattenPages(pages, 0, nil)
+	rows := make([]table.Row, len(m.pages))
+	for i, r := range m.pages {
+		rows[i] = table.Row{strings.Repeat("  ", r.depth) + r.page.Name}
+	}
+	m.pageList.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.pageList.Cursor(); c < 0 || c >= len(rows) {
+		m.pageList.SetCursor(0)
+	}
+}
+
+func (m *ClickUpDocs) showPage(page ClickUpDocPage) {
+	m.page = page
+	content := renderMarkdownText(page.Content)
+	if content == "" {
+		content = "(empty page)"
+	}
+	m.view.SetContent(content)
+	m.view.GotoTop()
+}
+
+func (m ClickUpDocs) selectedPage() (ClickUpDocPage, bool) {
+	i := m.pageList.Cursor()
+	if i < 0 || i >= len(m.pages) {
+		return ClickUpDocPage{}, false
+	}
+	return m.pages[i].page, true
+}
+
+// save writes an edited page back, unless it changed in ClickUp since it was
+// opened; the edit is then kept in a file rather than overwriting theirs.
+func (m ClickUpDocs) save(text string) tea.Cmd {
+	clickup, original := m.clickup, m.page
+	return func() tea.Msg {
+		current, err := GetClickUpDocPage(clickup, original.DocID, original.ID)
+		if err != nil {
+			return ClickUpDocPageMsg{page: original, err: err}
+		}
+		if current.Content != original.Content {
+			kept := "your edit could not be kept"
+			if f, err := os.CreateTemp("", "clickup-page-*.md"); err == nil {
+				_, err = f.WriteString(text)
+				f.Close()
+				if err == nil {
+					kept = "your edit is in " + f.Name()
+				}
+			}
+			return ClickUpDocPageMsg{page: current, done: "The page changed in ClickUp since you opened it, so it wasn't saved; " + kept + "."}
+		}
+		if err := UpdateClickUpDocPage(clickup, original.DocID, original.ID, original.Name, text); err != nil {
+			return ClickUpDocPageMsg{page: original, err: err}
+		}
+		page, err := GetClickUpDocPage(clickup, original.DocID, original.ID)
+		return ClickUpDocPageMsg{page: page, done: "Page saved.", err: err}
+	}
+}
+
+func (m ClickUpDocs) create(text string) tea.Cmd {
+	clickup, docID, parent, name := m.clickup, m.doc.ID, m.newParent, m.newName
+	return func() tea.Msg {
+		created, err := CreateClickUpDocPage(clickup, docID, parent, name, text)
+		if err != nil {
+			return ClickUpDocPageMsg{err: err}
+		}
+		page, err := GetClickUpDocPage(clickup, docID, created.ID)
+		return ClickUpDocPageMsg{page: page, done: "Page created.", created: true, err: err}
+	}
+}
+
+func (m ClickUpDocs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpDocsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.docs = msg.docs
+		m.showDocs()
+		m.status = countOf(len(m.docs), "Doc")
+		return m, nil
+
+	case ClickUpDocPagesMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if msg.docID != m.doc.ID {
+			return m, nil
+		}
+		m.showPages(msg.pages)
+		if m.level == docsPages {
+			m.status = countOf(len(m.pages), "page")
+		}
+		return m, nil
+
+	case ClickUpDocPageMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.showPage(msg.page)
+		m.level = docsPage
+		m.status = msg.done
+		if msg.created {
+			// Show the new page in the tree on the way back.
+			return m, m.loadPages(m.doc.ID)
+		}
+		return m, nil
+
+	case ExternalEditorMsg:
+		creating, editing := m.creating, m.editing
+		m.creating, m.editing = false, false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		switch {
+		case creating:
+			m.loading = true
+			m.status = "Creating " + m.newName + "..."
+			return m, m.create(msg.text)
+		case editing:
+			if msg.text == m.page.Content {
+				m.status = "No changes."
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Saving..."
+			return m, m.save(msg.text)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		if m.inputMode != docsNoInput {
+			switch msg.String() {
+			case "esc":
+				if m.inputMode == docsFilter {
+					m.filter = ""
+					m.showDocs()
+				}
+				m.inputMode = docsNoInput
+				m.input.Blur()
+				return m, nil
+			case "enter":
+				mode := m.inputMode
+				m.inputMode = docsNoInput
+				m.input.Blur()
+				if mode == docsNewPage {
+					name := strings.TrimSpace(m.input.Value())
+					if name == "" {
+						return m, nil
+					}
+					m.newName = name
+					m.creating = true
+					return m, openExternalEditor("", "clickup-page-*.md")
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			if m.inputMode == docsFilter {
+				m.filter = m.input.Value()
+				m.showDocs()
+			}
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			switch m.level {
+			case docsPage:
+				m.level = docsPages
+				m.status = ""
+				return m, nil
+			case docsPages:
+				m.level = docsList
+				m.status = ""
+				return m, nil
+			}
+			return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch m.level {
+		case docsList:
+			switch msg.String() {
+			case "/":
+				m.inputMode = docsFilter
+				m.input.Placeholder = "Filter Docs by name"
+				m.input.SetValue(m.filter)
+				return m, m.input.Focus()
+			case "r":
+				m.loading = true
+				m.status = "Loading Docs..."
+				return m, m.loadDocs()
+			case "enter":
+				i := m.docList.Cursor()
+				if i < 0 || i >= len(m.shown) {
+					return m, nil
+				}
+				m.doc = m.shown[i]
+				m.pages = nil
+				m.pageList.SetRows(nil)
+				m.pageList.GotoTop()
+				m.level = docsPages
+				m.loading = true
+				m.status = "Loading pages..."
+				return m, m.loadPages(m.doc.ID)
+			}
+
+		case docsPages:
+			switch msg.String() {
+			case "r":
+				m.loading = true
+				m.status = "Loading pages..."
+				return m, m.loadPages(m.doc.ID)
+			case "enter":
+				page, ok := m.selectedPage()
+				if !ok {
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Loading " + page.Name + "..."
+				return m, m.loadPage(page.ID, "")
+			case "n", "N":
+				m.newParent = ""
+				placeholder := "Name of the new page"
+				if msg.String() == "N" {
+					page, ok := m.selectedPage()
+					if !ok {
+						return m, nil
+					}
+					m.newParent = page.ID
+					placeholder = "Name of the new page under " + page.Name
+				}
+				m.inputMode = docsNewPage
+				m.input.Placeholder = placeholder
+				m.input.Reset()
+				return m, m.input.Focus()
+			case "o":
+				link := docPageURL(m.clickup, m.doc.ID, "")
+				if page, ok := m.selectedPage(); ok {
+					link = docPageURL(m.clickup, m.doc.ID, page.ID)
+				}
+				if err := openBrowser(link); err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+				}
+				return m, nil
+			}
+
+		case docsPage:
+			switch msg.String() {
+			case "e":
+				m.editing = true
+				return m, openExternalEditor(m.page.Content, "clickup-page-*.md")
+			case "r":
+				m.loading = true
+				m.status = "Reloading..."
+				return m, m.loadPage(m.page.ID, "")
+			case "o":
+				if err := openBrowser(docPageURL(m.clickup, m.doc.ID, m.page.ID)); err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.view, cmd = m.view.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.level {
+	case docsList:
+		m.docList, cmd = m.docList.Update(msg)
+	case docsPages:
+		m.pageList, cmd = m.pageList.Update(msg)
+	}
+	return m, cmd
+}
+
+// taskDraft links a quick task to the page being read.
+func (m ClickUpDocs) taskDraft() ClickUpTaskDraft {
+	if m.level != docsPage {
+		return ClickUpTaskDraft{}
+	}
+	return ClickUpTaskDraft{
+		Title:       "Follow up on " + m.page.Name,
+		Description: fmt.Sprintf("From [%s › %s](%s)", m.doc.Name, m.page.Name, docPageURL(m.clickup, m.doc.ID, m.page.ID)),
+	}
+}
+
+func (m ClickUpDocs) View() string {
+	var b strings.Builder
+
+	crumbs := []string{"Docs"}
+	if m.level >= docsPages {
+		crumbs = append(crumbs, m.doc.Name)
+	}
+	if m.level == docsPage {
+		crumbs = append(crumbs, m.page.Name)
+	}
+	b.WriteString("\n" + breadcrumbStyle.Render(strings.Join(crumbs, " › ")) + "\n\n")
+
+	switch m.level {
+	case docsList:
+		b.WriteString(m.docList.View())
+		b.WriteString("\n\n")
+		if m.inputMode == docsFilter {
+			b.WriteString(m.input.View() + "\n\n")
+			b.WriteString(helpStyle.Render("enter: keep filter • esc: clear"))
+		} else {
+			if m.filter != "" {
+				b.WriteString(fmt.Sprintf("Filter: %s\n\n", m.filter))
+			}
+			b.WriteString(helpStyle.Render("enter: open • /: filter • r: refresh • esc: back"))
+		}
+	case docsPages:
+		b.WriteString(m.pageList.View())
+		b.WriteString("\n\n")
+		if m.inputMode == docsNewPage {
+			b.WriteString(m.input.View() + "\n\n")
+			b.WriteString(helpStyle.Render("enter: write it in $EDITOR • esc: cancel"))
+		} else {
+			if !m.loading && len(m.pages) == 0 {
+				b.WriteString("This Doc has no pages.\n\n")
+			}
+			b.WriteString(helpStyle.Render("enter: read • n: new page • N: new sub-page • o: open in browser • r: refresh • esc: back"))
+		}
+	case docsPage:
+		b.WriteString(m.view.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%3.f%% • e: edit in $EDITOR • o: open in browser • r: reload • esc: back", m.view.ScrollPercent()*100)))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,422 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	goalNameStyle = lipgloss.NewStyle().Bold(true)
+	goalSoonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// goalRow is a line of the dashboard: a goal, or one of its targets.
+type goalRow struct {
+	goal int
+	// target indexes the goal's targets; -1 is the goal itself.
+	target int
+}
+
+// ClickUpGoals is a dashboard of the workspace's goals with a progress bar
+// for each target, where targets can be brought up to date.
+type ClickUpGoals struct {
+	goals     []ClickUpGoal
+	rows      []goalRow
+	cursor    int
+	completed bool
+	view      viewport.Model
+	bar       progress.Model
+	// updating is set while the form for the selected target is shown.
+	updating   bool
+	inputs     []textinput.Model
+	focusIndex int
+	loading    bool
+	status     string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpGoalsMsg struct {
+	goals []ClickUpGoal
+	err   error
+}
+
+// ClickUpGoalMsg brings one goal up to date after a target was updated.
+type ClickUpGoalMsg struct {
+	goal ClickUpGoal
+	done string
+	err  error
+}
+
+func InitialClickUpGoals(token string, refreshToken string, user User, clickup ClickUpProfile) ClickUpGoals {
+	return ClickUpGoals{
+		view: viewport.New(120, 25),
+		bar:  progress.New(progress.WithDefaultGradient(), progress.WithWidth(24), progress.WithoutPercentage()),
+		inputs: []textinput.Model{
+			newFormInput("New value, or +N to add to it", 32, 30),
+			newFormInput("Note (optional)", 200, 60),
+		},
+		loading:      true,
+		status:       "Loading goals...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+}
+
+func (m ClickUpGoals) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp Goals"), m.load())
+}
+
+func (m ClickUpGoals) load() tea.Cmd {
+	clickup, completed := m.clickup, m.completed
+	return func() tea.Msg {
+		goals, err := ListClickUpGoals(clickup, completed)
+		return ClickUpGoalsMsg{goals: goals, err: err}
+	}
+}
+
+func (m ClickUpGoals) selected() (ClickUpGoal, *ClickUpKeyResult, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return ClickUpGoal{}, nil, false
+	}
+	row := m.rows[m.cursor]
+	goal := m.goals[row.goal]
+	if row.target < 0 {
+		return goal, nil, true
+	}
+	return goal, &goal.KeyResults[row.target], true
+}
+
+// dueText says when a goal is due, relative to today for the coming week.
+func dueText(due time.Time, now time.Time) string {
+	if due.IsZero() {
+		return ""
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day := due.Local()
+	days := int(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location()).Sub(today).Hours() / 24)
+	switch {
+	case days < 0:
+		return statusWarningStyle.Render(fmt.Sprintf("overdue since %s", day.Format("Jan 2")))
+	case days == 0:
+		return goalSoonStyle.Render("due today")
+	case days < 7:
+		return goalSoonStyle.Render(fmt.Sprintf("due %s (in %s)", day.Format("Mon Jan 2"), countOf(days, "day")))
+	}
+	return "due " + day.Format("Jan 2, 2006")
+}
+
+func usernames(users []ClickUpUser) string {
+	names := make([]string, len(users))
+	for i, u := range users {
+		names[i] = u.Username
+	}
+	return strings.Join(names, ", ")
+}
+
+// summary is the dashboard's one-line weekly glance.
+func (m ClickUpGoals) summary(now time.Time) string {
+	soon, overdue, total := 0, 0, 0.0
+	for _, g := range m.goals {
+		total += float64(g.PercentCompleted)
+		switch {
+		case g.DueDate.IsZero() || g.PercentCompleted >= 100:
+		case g.DueDate.Before(now):
+			overdue++
+		case g.DueDate.Before(now.AddDate(0, 0, 7)):
+			soon++
+		}
+	}
+	parts := []string{countOf(len(m.goals), "goal")}
+	if len(m.goals) > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%% done on average", total/float64(len(m.goals))))
+	}
+	if soon > 0 {
+		parts = append(parts, fmt.Sprintf("%d due this week", soon))
+	}
+	if overdue > 0 {
+		parts = append(parts, fmt.Sprintf("%d overdue", overdue))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// render lays the goals out with their targets under them, and scrolls the
+// viewport to keep the selected line in sight.
+func (m *ClickUpGoals) render() {
+	m.rows = nil
+	count := 0
+	for _, g := range m.goals {
+		count += 1 + len(g.KeyResults)
+	}
+	m.cursor = max(0, min(m.cursor, count-1))
+
+	now := time.Now()
+	var b strings.Builder
+	line := 0
+	selected := 0
+	for gi, g := range m.goals {
+		m.rows = append(m.rows, goalRow{goal: gi, target: -1})
+		if len(m.rows)-1 == m.cursor {
+			selected = line
+		}
+		marker := "  "
+		if len(m.rows)-1 == m.cursor {
+			marker = focusedStyle.Render("> ")
+		}
+		details := []string{}
+		if due := dueText(g.DueDate.Time, now); due != "" {
+			details = append(details, due)
+		}
+		if owners := usernames(g.Owners); owners != "" {
+			details = append(details, owners)
+		}
+		fmt.Fprintf(&b, "%s%s %s %3.0f%%  %s\n", marker, goalNameStyle.Render(fmt.Sprintf("%-40.40s", g.Name)),
+			m.bar.ViewAs(float64(g.PercentCompleted)/100), float64(g.PercentCompleted), strings.Join(details, " • "))
+		line++
+
+		for ti, k := range g.KeyResults {
+			m.rows = append(m.rows, goalRow{goal: gi, target: ti})
+			marker := "    "
+			if len(m.rows)-1 == m.cursor {
+				marker = "  " + focusedStyle.Render("> ")
+				selected = line
+			}
+			owners := ""
+			if len(k.Owners) > 0 {
+				owners = " • " + usernames(k.Owners)
+			}
+			fmt.Fprintf(&b, "%s%-38.38s %s %3.0f%%  %s%s\n", marker, k.Name, m.bar.ViewAs(k.progress()), k.progress()*100, k.state(), owners)
+			line++
+		}
+		b.WriteString("\n")
+		line++
+	}
+	m.view.SetContent(b.String())
+
+	if selected < m.view.YOffset {
+		m.view.SetYOffset(selected)
+	} else if selected >= m.view.YOffset+m.view.Height {
+		m.view.SetYOffset(selected - m.view.Height + 1)
+	}
+}
+
+// startUpdate opens the form for the selected target, or for a true/false
+// target simply flips it.
+func (m *ClickUpGoals) startUpdate() tea.Cmd {
+	goal, k, ok := m.selected()
+	if !ok || k == nil {
+		return nil
+	}
+	switch k.Type {
+	case targetAutomatic:
+		m.status = "Task-based targets move as their tasks are closed."
+		return nil
+	case targetBoolean:
+		value := 1.0
+		if k.progress() == 1 {
+			value = 0
+		}
+		m.loading = true
+		m.status = "Updating " + k.Name + "..."
+		return m.update(goal.ID, k.ID, value, "")
+	}
+	m.updating = true
+	m.status = ""
+	m.inputs[0].SetValue(strconv.FormatFloat(float64(k.StepsCurrent), 'f', -1, 64))
+	m.inputs[1].Reset()
+	m.focusIndex = 0
+	return focusInput(m.inputs, m.focusIndex)
+}
+
+// parseTargetValue reads a plain value or +N added to current, ignoring
+// currency signs, percent signs and thousands separators.
+func parseTargetValue(s string, current float64) (float64, error) {
+	s = strings.NewReplacer(",", "", "$", "", "€", "", "£", "", "%", "", " ", "").Replace(s)
+	add := strings.HasPrefix(s, "+")
+	v, err := strconv.ParseFloat(strings.TrimPrefix(s, "+"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a number", s)
+	}
+	if add {
+		v += current
+	}
+	return v, nil
+}
+
+func (m *ClickUpGoals) submit() tea.Cmd {
+	goal, k, ok := m.selected()
+	if !ok || k == nil {
+		m.updating = false
+		return nil
+	}
+	value, err := parseTargetValue(m.inputs[0].Value(), float64(k.StepsCurrent))
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	m.updating = false
+	m.loading = true
+	m.status = "Updating " + k.Name + "..."
+	return m.update(goal.ID, k.ID, value, strings.TrimSpace(m.inputs[1].Value()))
+}
+
+func (m ClickUpGoals) update(goalID string, keyResultID string, value float64, note string) tea.Cmd {
+	clickup := m.clickup
+	return func() tea.Msg {
+		if err := UpdateClickUpKeyResult(clickup, keyResultID, value, note); err != nil {
+			return ClickUpGoalMsg{err: err}
+		}
+		goal, err := GetClickUpGoal(clickup, goalID)
+		return ClickUpGoalMsg{goal: goal, done: "Target updated.", err: err}
+	}
+}
+
+func (m ClickUpGoals) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpGoalsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.goals = msg.goals
+		m.render()
+		m.status = ""
+		return m, nil
+
+	case ClickUpGoalMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		for i := range m.goals {
+			if m.goals[i].ID == msg.goal.ID {
+				m.goals[i] = msg.goal
+			}
+		}
+		m.render()
+		m.status = msg.done
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		if m.updating {
+			switch msg.String() {
+			case "esc":
+				m.updating = false
+				return m, nil
+			case "enter":
+				return m, m.submit()
+			case "tab", "shift+tab":
+				m.focusIndex = (m.focusIndex + 1) % len(m.inputs)
+				return m, focusInput(m.inputs, m.focusIndex)
+			}
+			var cmd tea.Cmd
+			m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.render()
+			}
+			return m, nil
+		case "down", "j":
+			if m.cursor < len(m.rows)-1 {
+				m.cursor++
+				m.render()
+			}
+			return m, nil
+		case "enter", "u":
+			return m, m.startUpdate()
+		case "c":
+			m.completed = !m.completed
+			m.loading = true
+			m.status = "Loading goals..."
+			return m, m.load()
+		case "r":
+			m.loading = true
+			m.status = "Refreshing..."
+			return m, m.load()
+		case "o":
+			goal, _, ok := m.selected()
+			if !ok {
+				return m, nil
+			}
+			if err := openBrowser(goal.PrettyURL); err != nil {
+				m.status = fmt.Sprintf("Error: %v", err)
+			}
+			return m, nil
+		}
+	}
+
+	return m, nil
+}
+
+func (m ClickUpGoals) View() string {
+	var b strings.Builder
+
+	title := "\nGoals"
+	if m.completed {
+		title += " (including completed)"
+	}
+	b.WriteString(title + "\n")
+	if !m.loading || m.goals != nil {
+		b.WriteString(blurredStyle.Render(m.summary(time.Now())) + "\n")
+	}
+	b.WriteString("\n")
+
+	if !m.loading && len(m.goals) == 0 {
+		b.WriteString("No goals in this workspace.\n\n")
+	} else {
+		b.WriteString(m.view.View() + "\n")
+	}
+
+	if m.updating {
+		if _, k, ok := m.selected(); ok && k != nil {
+			fmt.Fprintf(&b, "%s: now %s, from %s to %s\n\n", k.Name, k.amount(k.StepsCurrent), k.amount(k.StepsStart), k.amount(k.StepsEnd))
+		}
+		for i := range m.inputs {
+			b.WriteString(m.inputs[i].View() + "\n")
+		}
+		b.WriteString("\n" + helpStyle.Render("enter: save • tab: next field • esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("↑/↓: move • enter: update target • c: show/hide completed • o: open in browser • r: refresh • esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
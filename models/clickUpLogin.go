@@ -0,0 +1,350 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clickUpOAuthTimeout is how long the redirect listener waits for the browser.
+const clickUpOAuthTimeout = 5 * time.Minute
+
+type clickUpLoginMode int
+
+const (
+	clickUpChooseMethod clickUpLoginMode = iota
+	clickUpEnterToken
+	clickUpWaitForBrowser
+	clickUpPickWorkspace
+)
+
+var clickUpLoginMethods = []string{"Personal API token", "OAuth (sign in with the browser)"}
+
+type ClickUpLogin struct {
+	mode         clickUpLoginMode
+	cursor       int
+	input        textinput.Model
+	oauthURL     string
+	attempt      *clickUpAttempt
+	profile      ClickUpProfile
+	workspaces   []ClickUpWorkspace
+	list         table.Model
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+// clickUpAttempt tags one token check or OAuth sign-in, so a result that
+// arrives after esc, or after the user has started over, is ignored.
+type clickUpAttempt struct {
+	cancel context.CancelFunc
+}
+
+// stop cancels an OAuth wait; a token check has nothing to cancel.
+func (a *clickUpAttempt) stop() {
+	if a != nil && a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// ClickUpSignedInMsg carries a checked credential and its workspaces.
+type ClickUpSignedInMsg struct {
+	attempt    *clickUpAttempt
+	profile    ClickUpProfile
+	workspaces []ClickUpWorkspace
+	err        error
+}
+
+// ClickUpReadyMsg opens the ClickUp menu once a workspace is chosen.
+type ClickUpReadyMsg struct {
+	profile ClickUpProfile
+}
+
+// ShowClickUpLoginMsg returns to sign-in, or straight to the workspace picker
+// when profile holds a token.
+type ShowClickUpLoginMsg struct {
+	profile ClickUpProfile
+}
+
+// InitialClickUpLogin starts at the sign-in choice, or at the workspace picker
+// when profile already holds a token.
+func InitialClickUpLogin(token string, refreshToken string, user User, profile ClickUpProfile) ClickUpLogin {
+	input := newFormInput("pk_...", 128, 60)
+	input.EchoMode = textinput.EchoPassword
+
+	m := ClickUpLogin{
+		input:   input,
+		profile: profile,
+		list: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Workspace", Width: 40},
+				{Title: "ID", Width: 15},
+				{Title: "Members", Width: 10},
+			}),
+			table.WithHeight(10),
+			table.WithFocused(true),
+		),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+	if profile.Token != "" {
+		m.mode = clickUpPickWorkspace
+		m.loading = true
+		m.attempt = &clickUpAttempt{}
+		m.status = "Loading workspaces..."
+	}
+	return m
+}
+
+func (m ClickUpLogin) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(tea.SetWindowTitle("ClickUp"), checkClickUpToken(m.attempt, m.profile))
+	}
+	return tea.SetWindowTitle("ClickUp")
+}
+
+// checkClickUpToken looks up the token's user, which fails for a bad token,
+// and lists the workspaces it can access.
+func checkClickUpToken(attempt *clickUpAttempt, profile ClickUpProfile) tea.Cmd {
+	return func() tea.Msg {
+		return signInToClickUp(attempt, profile)
+	}
+}
+
+func signInToClickUp(attempt *clickUpAttempt, profile ClickUpProfile) ClickUpSignedInMsg {
+	user, err := GetClickUpUser(profile)
+	if err != nil {
+		return ClickUpSignedInMsg{attempt: attempt, err: err}
+	}
+	profile.UserID = user.ID
+	profile.Username = user.Username
+
+	workspaces, err := ListClickUpWorkspaces(profile)
+	return ClickUpSignedInMsg{attempt: attempt, profile: profile, workspaces: workspaces, err: err}
+}
+
+func waitForClickUpOAuth(ctx context.Context, attempt *clickUpAttempt, o *ClickUpOAuth) tea.Cmd {
+	return func() tea.Msg {
+		profile, err := o.Wait(ctx)
+		if err != nil {
+			return ClickUpSignedInMsg{attempt: attempt, err: err}
+		}
+		return signInToClickUp(attempt, profile)
+	}
+}
+
+func (m *ClickUpLogin) startOAuth() tea.Cmd {
+	o, err := StartClickUpOAuth()
+	if err != nil {
+		m.status = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clickUpOAuthTimeout)
+	m.attempt = &clickUpAttempt{cancel: cancel}
+	m.oauthURL = o.URL
+	m.mode = clickUpWaitForBrowser
+	m.loading = true
+	m.status = "Waiting for the browser..."
+	if err := openBrowser(o.URL); err != nil {
+		if clipboard.WriteAll(o.URL) == nil {
+			m.status = "Couldn't open a browser; the sign-in link is on the clipboard."
+		} else {
+			m.status = "Couldn't open a browser; open the link below."
+		}
+	}
+	return waitForClickUpOAuth(ctx, m.attempt, o)
+}
+
+func (m *ClickUpLogin) showWorkspaces() {
+	rows := make([]table.Row, len(m.workspaces))
+	cursor := 0
+	for i, w := range m.workspaces {
+		rows[i] = table.Row{w.Name, w.ID, strconv.Itoa(len(w.Members))}
+		if w.ID == m.profile.WorkspaceID {
+			cursor = i
+		}
+	}
+	m.list.SetRows(rows)
+	m.list.SetCursor(cursor)
+}
+
+func (m ClickUpLogin) back() (tea.Model, tea.Cmd) {
+	m.attempt.stop()
+	// With a workspace already chosen, backing out of the picker keeps it.
+	if m.profile.WorkspaceID != "" {
+		profile := m.profile
+		return m, func() tea.Msg { return ClickUpReadyMsg{profile: profile} }
+	}
+	return m, func() tea.Msg {
+		return MainMenuMsg{selected: -1, token: m.token, refreshToken: m.refreshToken, user: m.user}
+	}
+}
+
+func (m ClickUpLogin) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpSignedInMsg:
+		if msg.attempt != m.attempt {
+			// A cancelled or superseded sign-in.
+			return m, nil
+		}
+		m.attempt.stop()
+		m.attempt = nil
+		m.loading = false
+		if msg.err != nil {
+			m.profile = ClickUpProfile{}
+			m.mode = clickUpChooseMethod
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		msg.profile.WorkspaceID = m.profile.WorkspaceID
+		msg.profile.WorkspaceName = m.profile.WorkspaceName
+		m.profile = msg.profile
+		m.workspaces = msg.workspaces
+		m.mode = clickUpPickWorkspace
+		m.status = ""
+		if len(m.workspaces) == 0 {
+			m.status = "This account doesn't belong to any workspace."
+		}
+		m.showWorkspaces()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.attempt.stop()
+			return m, tea.Quit
+		case "esc":
+			if m.mode == clickUpEnterToken || m.mode == clickUpWaitForBrowser {
+				m.attempt.stop()
+				m.attempt = nil
+				m.mode = clickUpChooseMethod
+				m.loading = false
+				m.status = ""
+				m.input.Blur()
+				return m, nil
+			}
+			return m.back()
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch m.mode {
+		case clickUpChooseMethod:
+			switch msg.String() {
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case "down", "j":
+				if m.cursor < len(clickUpLoginMethods)-1 {
+					m.cursor++
+				}
+			case "enter", " ":
+				m.status = ""
+				if m.cursor == 1 {
+					return m, m.startOAuth()
+				}
+				m.mode = clickUpEnterToken
+				m.input.SetValue("")
+				return m, m.input.Focus()
+			}
+			return m, nil
+
+		case clickUpEnterToken:
+			if msg.String() == "enter" {
+				token := strings.TrimSpace(m.input.Value())
+				if !looksLikePersonalToken(token) {
+					m.status = "Personal tokens start with pk_. Find yours under Settings › Apps in ClickUp."
+					return m, nil
+				}
+				m.input.Blur()
+				m.loading = true
+				m.attempt = &clickUpAttempt{}
+				m.status = "Checking the token..."
+				return m, checkClickUpToken(m.attempt, ClickUpProfile{Token: token})
+			}
+
+		case clickUpPickWorkspace:
+			if msg.String() == "enter" {
+				if len(m.workspaces) == 0 {
+					return m, nil
+				}
+				w := m.workspaces[m.list.Cursor()]
+				m.profile.WorkspaceID = w.ID
+				m.profile.WorkspaceName = w.Name
+				if err := SaveClickUpProfile(m.user, m.profile); err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+					return m, nil
+				}
+				profile := m.profile
+				return m, func() tea.Msg { return ClickUpReadyMsg{profile: profile} }
+			}
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m ClickUpLogin) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nClickUp\n\n")
+
+	switch m.mode {
+	case clickUpChooseMethod:
+		b.WriteString("Sign in to ClickUp:\n\n")
+		for i, method := range clickUpLoginMethods {
+			cursor := " "
+			if m.cursor == i {
+				cursor = ">"
+			}
+			fmt.Fprintf(&b, "%s %s\n", cursor, method)
+		}
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("enter: choose • esc: back"))
+
+	case clickUpEnterToken:
+		b.WriteString("Personal API token:\n\n")
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: sign in • esc: back"))
+
+	case clickUpWaitForBrowser:
+		b.WriteString("Authorize the app in your browser. If it didn't open, visit:\n\n")
+		b.WriteString(m.oauthURL)
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc: cancel"))
+
+	case clickUpPickWorkspace:
+		if m.profile.Username != "" {
+			fmt.Fprintf(&b, "Signed in as %s. Choose a workspace:\n\n", m.profile.Username)
+		}
+		b.WriteString(m.list.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: use workspace • esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
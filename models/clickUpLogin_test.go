@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checkToken submits token on the token screen and returns the attempt it starts.
+func checkToken(t *testing.T, m ClickUpLogin, token string) ClickUpLogin {
+	t.Helper()
+	m.mode = clickUpEnterToken
+	m.input.SetValue(token)
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(ClickUpLogin)
+	if cmd == nil || !m.loading || m.attempt == nil {
+		t.Fatalf("enter didn't start a token check: %+v", m)
+	}
+	return m
+}
+
+func TestClickUpLoginIgnoresSupersededAttempts(t *testing.T) {
+	m := InitialClickUpLogin("", "", User{}, ClickUpProfile{})
+
+	m = checkToken(t, m, "pk_first")
+	first := m.attempt
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(ClickUpLogin)
+	if m.loading || m.attempt != nil {
+		t.Fatalf("esc left the check running: %+v", m)
+	}
+
+	m = checkToken(t, m, "pk_second")
+	second := m.attempt
+
+	next, _ = m.Update(ClickUpSignedInMsg{attempt: first, err: context.Canceled})
+	m = next.(ClickUpLogin)
+	if !m.loading || m.attempt != second || m.status != "Checking the token..." {
+		t.Fatalf("the cancelled attempt's result was taken: status %q", m.status)
+	}
+
+	profile := ClickUpProfile{Token: "pk_second", Username: "ada"}
+	next, _ = m.Update(ClickUpSignedInMsg{attempt: second, profile: profile, workspaces: []ClickUpWorkspace{{ID: "9001", Name: "Acme"}}})
+	m = next.(ClickUpLogin)
+	if m.loading || m.mode != clickUpPickWorkspace || m.profile.Token != "pk_second" {
+		t.Errorf("the current attempt's result wasn't taken: mode %d, profile %+v", m.mode, m.profile)
+	}
+}
@@ -0,0 +1,265 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var breadcrumbStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+// ClickUpNavigator drills down Workspace → Space → Folder → List → Task.
+// Children load when a node is first opened and stay cached until refreshed.
+type ClickUpNavigator struct {
+	// path is the breadcrumb; path[0] is the workspace.
+	path []ClickUpNode
+	// cursors remembers the selection at each level of path.
+	cursors      []int
+	cache        map[string][]ClickUpNode
+	list         table.Model
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpChildrenMsg struct {
+	parent ClickUpNode
+	nodes  []ClickUpNode
+	// more is set when nodes are the next page of parent's tasks.
+	more bool
+	err  error
+}
+
+// ShowClickUpMenuMsg returns to the ClickUp menu.
+type ShowClickUpMenuMsg struct{}
+
+func InitialClickUpNavigator(token string, refreshToken string, user User, clickup ClickUpProfile) ClickUpNavigator {
+	root := ClickUpNode{Kind: clickUpWorkspaceNode, ID: clickup.WorkspaceID, Name: clickup.WorkspaceName}
+	return ClickUpNavigator{
+		path:    []ClickUpNode{root},
+		cursors: []int{0},
+		cache:   make(map[string][]ClickUpNode),
+		list: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Name", Width: 50},
+				{Title: "Type", Width: 10},
+				{Title: "Details", Width: 40},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		loading:      true,
+		status:       "Loading...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+}
+
+func (m ClickUpNavigator) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp"), m.loadChildren(m.current(), false))
+}
+
+func (m ClickUpNavigator) current() ClickUpNode {
+	return m.path[len(m.path)-1]
+}
+
+func (m ClickUpNavigator) loadChildren(node ClickUpNode, more bool) tea.Cmd {
+	clickup, parent := m.clickup, m.current()
+	if !more {
+		parent = node
+	}
+	return func() tea.Msg {
+		nodes, err := ClickUpChildren(clickup, node)
+		return ClickUpChildrenMsg{parent: parent, nodes: nodes, more: more, err: err}
+	}
+}
+
+func (m *ClickUpNavigator) showChildren() {
+	nodes := m.cache[m.current().key()]
+	rows := make([]table.Row, len(nodes))
+	for i, n := range nodes {
+		rows[i] = table.Row{n.Name, n.Kind.String(), n.Detail}
+	}
+	m.list.SetRows(rows)
+	m.list.SetCursor(min(m.cursors[len(m.cursors)-1], max(len(rows)-1, 0)))
+}
+
+// open descends into node, loading its children unless they are cached.
+func (m *ClickUpNavigator) open(node ClickUpNode) tea.Cmd {
+	m.cursors[len(m.cursors)-1] = m.list.Cursor()
+	m.path = append(m.path, node)
+	m.cursors = append(m.cursors, 0)
+	m.status = ""
+
+	if _, ok := m.cache[node.key()]; ok {
+		m.showChildren()
+		return nil
+	}
+	m.list.SetRows(nil)
+	m.loading = true
+	m.status = "Loading..."
+	return m.loadChildren(node, false)
+}
+
+// openShared shows what was shared with the user, starting from the workspace.
+func (m *ClickUpNavigator) openShared() tea.Cmd {
+	m.path = m.path[:1]
+	m.cursors = m.cursors[:1]
+	return m.open(ClickUpNode{Kind: clickUpSharedNode, ID: m.clickup.WorkspaceID, Name: "Shared with me"})
+}
+
+func (m *ClickUpNavigator) up() {
+	m.path = m.path[:len(m.path)-1]
+	m.cursors = m.cursors[:len(m.cursors)-1]
+	m.loading = false
+	m.status = ""
+	m.showChildren()
+}
+
+// taskDraft files a quick task in the list being browsed or selected.
+func (m ClickUpNavigator) taskDraft() ClickUpTaskDraft {
+	node := m.current()
+	if nodes := m.cache[node.key()]; node.Kind != clickUpListNode && len(nodes) > 0 {
+		node = nodes[m.list.Cursor()]
+	}
+	if node.Kind != clickUpListNode {
+		return ClickUpTaskDraft{}
+	}
+	return ClickUpTaskDraft{ListID: node.ID}
+}
+
+func (m ClickUpNavigator) breadcrumb() string {
+	names := make([]string, len(m.path))
+	for i, n := range m.path {
+		names[i] = n.Name
+	}
+	return breadcrumbStyle.Render(strings.Join(names, " › "))
+}
+
+func (m ClickUpNavigator) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpChildrenMsg:
+		if msg.parent.key() == m.current().key() {
+			m.loading = false
+			m.status = ""
+		}
+		if msg.err != nil {
+			if msg.parent.key() == m.current().key() {
+				m.status = fmt.Sprintf("Error: %v", msg.err)
+			}
+			return m, nil
+		}
+
+		key := msg.parent.key()
+		if msg.more {
+			// Replace the more node with the page it stood for.
+			nodes := m.cache[key]
+			m.cache[key] = append(nodes[:len(nodes)-1:len(nodes)-1], msg.nodes...)
+		} else {
+			m.cache[key] = msg.nodes
+		}
+		if key == m.current().key() {
+			// A first load keeps the 0 open pushed; the table still holds the
+			// parent's cursor until showChildren runs.
+			if msg.more {
+				m.cursors[len(m.cursors)-1] = m.list.Cursor()
+			}
+			m.showChildren()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace", "left", "h":
+			if len(m.path) == 1 {
+				return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+			}
+			m.up()
+			return m, nil
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "r":
+			delete(m.cache, m.current().key())
+			m.cursors[len(m.cursors)-1] = m.list.Cursor()
+			m.loading = true
+			m.status = "Refreshing..."
+			return m, m.loadChildren(m.current(), false)
+		case "t":
+			// Tasks of the selected list, or of the list being browsed.
+			node := m.current()
+			if nodes := m.cache[node.key()]; node.Kind != clickUpListNode && len(nodes) > 0 {
+				node = nodes[m.list.Cursor()]
+			}
+			if node.Kind != clickUpListNode {
+				m.status = "Select a list to see its tasks."
+				return m, nil
+			}
+			return m, func() tea.Msg { return ClickUpTasksRequestMsg{listID: node.ID, listName: node.Name} }
+		case "c":
+			// Comments on the selected list or task, or on the list being browsed.
+			node := m.current()
+			if nodes := m.cache[node.key()]; len(nodes) > 0 && (node.Kind != clickUpListNode || nodes[m.list.Cursor()].leaf()) {
+				node = nodes[m.list.Cursor()]
+			}
+			kind := map[clickUpNodeKind]string{clickUpListNode: "list", clickUpTaskNode: "task"}[node.Kind]
+			if kind == "" {
+				m.status = "Select a list or task to see its comments."
+				return m, nil
+			}
+			target := ClickUpCommentTarget{Kind: kind, ID: node.ID, Name: strings.TrimPrefix(node.Name, "↳ ")}
+			return m, func() tea.Msg { return ClickUpCommentsRequestMsg{target: target} }
+		case "enter", "right", "l":
+			nodes := m.cache[m.current().key()]
+			if len(nodes) == 0 {
+				return m, nil
+			}
+			node := nodes[m.list.Cursor()]
+			switch {
+			case node.Kind == clickUpMoreNode:
+				m.loading = true
+				m.status = "Loading more tasks..."
+				return m, m.loadChildren(node, true)
+			case node.leaf():
+				return m, func() tea.Msg { return ClickUpTaskRequestMsg{taskID: node.ID, fromNavigator: true} }
+			}
+			return m, m.open(node)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m ClickUpNavigator) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n" + m.breadcrumb() + "\n\n")
+	b.WriteString(m.list.View())
+	b.WriteString("\n\n")
+	if !m.loading && len(m.cache[m.current().key()]) == 0 {
+		b.WriteString("Nothing here.\n\n")
+	}
+	b.WriteString(helpStyle.Render("enter: open • t: task list • c: comments • esc: up • r: refresh • q: quit"))
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,1141 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type peopleTab int
+
+const (
+	tabMembers peopleTab = iota
+	tabGuests
+	tabGroups
+	tabRoles
+	// tabAccess shows who can get into a space, folder, list or task.
+	tabAccess
+)
+
+var peopleTabNames = []string{"Members", "Guests", "User Groups", "Roles", "Privacy & Access"}
+
+type peopleMode int
+
+const (
+	peopleBrowse peopleMode = iota
+	// peopleInvite invites a guest, or edits the selected one.
+	peopleInvite
+	// peopleGuest shows what is shared with one guest.
+	peopleGuest
+	// peopleShare shares a location with a guest.
+	peopleShare
+	// peopleGroup creates a user group, or edits the selected one.
+	peopleGroup
+)
+
+// Fields of the guest form; the permissions checklist sits before submit.
+const (
+	inviteEmail = iota
+	inviteLocation
+	inviteLevel
+	invitePerms
+	inviteSubmit
+)
+
+const (
+	shareGuest = iota
+	shareLocation
+	shareLevel
+	shareSubmit
+)
+
+const (
+	groupName = iota
+	groupHandle
+	groupMembers
+	groupSubmit
+)
+
+var guestPermissionNames = []string{"Can edit tags", "Can see time spent", "Can see time estimates", "Can create views"}
+
+// ClickUpPeople administers the workspace's members, guests, user groups and
+// roles, and who has access to its spaces, folders, lists and tasks.
+type ClickUpPeople struct {
+	tab   peopleTab
+	mode  peopleMode
+	table table.Model
+
+	people    []ClickUpMember
+	groups    []ClickUpGroup
+	groupsErr error
+	roles     []ClickUpCustomRole
+	rolesErr  error
+
+	// guest is the one peopleGuest shows; shareCursor picks among its shares.
+	guest       *ClickUpGuest
+	shareCursor int
+
+	accessInput  textinput.Model
+	access       *ClickUpAccess
+	accessCursor int
+
+	inviteInputs []textinput.Model
+	perms        [4]bool
+	permCursor   int
+	// editingGuest is the guest the invite form changes; 0 invites one.
+	editingGuest int
+	shareInputs  []textinput.Model
+	groupInputs  []textinput.Model
+	// editingGroup is the group the group form changes; nil creates one.
+	editingGroup *ClickUpGroup
+	focusIndex   int
+
+	// confirm runs once the question in status is answered with y.
+	confirm tea.Cmd
+	loading bool
+	status  string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpPeopleMsg struct {
+	people    []ClickUpMember
+	groups    []ClickUpGroup
+	groupsErr error
+	roles     []ClickUpCustomRole
+	rolesErr  error
+	done      string
+	err       error
+}
+
+type ClickUpGuestMsg struct {
+	guest ClickUpGuest
+	err   error
+}
+
+type ClickUpAccessMsg struct {
+	access ClickUpAccess
+	done   string
+	err    error
+}
+
+type ClickUpPeopleChangedMsg struct {
+	done string
+	err  error
+}
+
+func InitialClickUpPeople(token string, refreshToken string, user User, clickup ClickUpProfile, tab peopleTab) ClickUpPeople {
+	level := newFormInput("Permission level: read, comment, edit or create", 10, 50)
+	level.SetValue("read")
+	shareLevel := newFormInput("Permission level: read, comment, edit or create", 10, 50)
+	shareLevel.SetValue("read")
+	accessInput := newFormInput("space ID, folder ID, list ID or task ID", 64, 50)
+
+	m := ClickUpPeople{
+		tab:         tab,
+		table:       table.New(table.WithHeight(15), table.WithFocused(true)),
+		accessInput: accessInput,
+		inviteInputs: []textinput.Model{
+			newFormInput("Guest's email", 200, 50),
+			newFormInput("Share folder ID, list ID or task ID (optional)", 64, 50),
+			level,
+		},
+		shareInputs: []textinput.Model{
+			newFormInput("Guest's email or user ID", 200, 50),
+			newFormInput("folder ID, list ID or task ID", 64, 50),
+			shareLevel,
+		},
+		groupInputs: []textinput.Model{
+			newFormInput("Group name", 100, 50),
+			newFormInput("Handle for @mentions (optional)", 100, 50),
+			newFormInput("Members: usernames, emails or IDs, comma separated", 1000, 70),
+		},
+		loading:      true,
+		status:       "Loading people...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+	if tab == tabAccess {
+		m.accessInput.Focus()
+	}
+	m.showTab()
+	return m
+}
+
+func (m ClickUpPeople) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp People & Access"), m.load(""), textinput.Blink)
+}
+
+// load fetches people, groups and roles. Groups and custom roles depend on
+// the plan, so failing to get them doesn't fail the rest.
+func (m ClickUpPeople) load(done string) tea.Cmd {
+	clickup := m.clickup
+	return func() tea.Msg {
+		msg := ClickUpPeopleMsg{done: done}
+		if msg.people, msg.err = ListClickUpPeople(clickup); msg.err != nil {
+			return msg
+		}
+		msg.groups, msg.groupsErr = ListClickUpGroups(clickup)
+		msg.roles, msg.rolesErr = ListClickUpCustomRoles(clickup)
+		return msg
+	}
+}
+
+func (m ClickUpPeople) loadGuest(guestID int) tea.Cmd {
+	clickup := m.clickup
+	return func() tea.Msg {
+		guest, err := GetClickUpGuest(clickup, guestID)
+		return ClickUpGuestMsg{guest: guest, err: err}
+	}
+}
+
+func (m ClickUpPeople) loadAccess(location string, done string) tea.Cmd {
+	clickup, people := m.clickup, m.people
+	return func() tea.Msg {
+		access, err := GetClickUpAccess(clickup, location, people)
+		return ClickUpAccessMsg{access: access, done: done, err: err}
+	}
+}
+
+func peopleChanged(done string, f func() error) tea.Cmd {
+	return func() tea.Msg { return ClickUpPeopleChangedMsg{done: done, err: f()} }
+}
+
+func (m ClickUpPeople) members() []ClickUpMember {
+	var out []ClickUpMember
+	for _, p := range m.people {
+		if p.Role != roleGuest {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (m ClickUpPeople) guests() []ClickUpMember {
+	var out []ClickUpMember
+	for _, p := range m.people {
+		if p.Role == roleGuest {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (m ClickUpPeople) users() []ClickUpUser {
+	users := make([]ClickUpUser, len(m.people))
+	for i, p := range m.people {
+		users[i] = p.ClickUpUser
+	}
+	return users
+}
+
+func shortDate(t clickUpTime) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func userNames(users []ClickUpUser) string {
+	names := make([]string, len(users))
+	for i, u := range users {
+		names[i] = u.Username
+		if names[i] == "" {
+			names[i] = u.Email
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// showTab fills the table for the current tab.
+func (m *ClickUpPeople) showTab() {
+	var columns []table.Column
+	var rows []table.Row
+	switch m.tab {
+	case tabMembers:
+		columns = []table.Column{{Title: "Name", Width: 24}, {Title: "Email", Width: 34}, {Title: "Role", Width: 14}, {Title: "Last active", Width: 12}, {Title: "Joined", Width: 12}}
+		for _, p := range m.members() {
+			joined := shortDate(p.DateJoined)
+			if p.pending() {
+				joined = "invited"
+			}
+			rows = append(rows, table.Row{p.Username, p.Email, p.role(), shortDate(p.LastActive), joined})
+		}
+	case tabGuests:
+		columns = []table.Column{{Title: "Name", Width: 24}, {Title: "Email", Width: 34}, {Title: "Status", Width: 10}, {Title: "Invited", Width: 12}, {Title: "Last active", Width: 12}}
+		for _, p := range m.guests() {
+			status := "joined"
+			if p.pending() {
+				status = "pending"
+			}
+			rows = append(rows, table.Row{p.Username, p.Email, status, shortDate(p.DateInvited), shortDate(p.LastActive)})
+		}
+	case tabGroups:
+		columns = []table.Column{{Title: "Group", Width: 24}, {Title: "Handle", Width: 16}, {Title: "Members", Width: 8}, {Title: "Who", Width: 50}}
+		for _, g := range m.groups {
+			rows = append(rows, table.Row{g.Name, g.Handle, strconv.Itoa(len(g.Members)), userNames(g.Members)})
+		}
+	case tabRoles:
+		columns = []table.Column{{Title: "Role", Width: 24}, {Title: "Based on", Width: 12}, {Title: "Members", Width: 8}, {Title: "Who", Width: 50}}
+		for _, role := range []int{roleOwner, roleAdmin, roleMember, roleGuest} {
+			var who []ClickUpUser
+			for _, p := range m.people {
+				if p.Role == role && p.CustomRole == nil {
+					who = append(who, p.ClickUpUser)
+				}
+			}
+			rows = append(rows, table.Row{roleName(role), "built-in", strconv.Itoa(len(who)), userNames(who)})
+		}
+		for _, r := range m.roles {
+			rows = append(rows, table.Row{r.Name, roleName(r.InheritedRole), strconv.Itoa(len(r.Members)), userNames(r.Members)})
+		}
+	}
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.table.Cursor(); c < 0 || c >= len(rows) {
+		m.table.SetCursor(0)
+	}
+}
+
+func (m *ClickUpPeople) switchTab(tab peopleTab) tea.Cmd {
+	m.tab = tab
+	m.confirm = nil
+	m.status = ""
+	m.showTab()
+	if tab == tabAccess && m.access == nil {
+		return m.accessInput.Focus()
+	}
+	m.accessInput.Blur()
+	return nil
+}
+
+func (m ClickUpPeople) selectedGuest() (ClickUpMember, bool) {
+	guests := m.guests()
+	i := m.table.Cursor()
+	if m.tab != tabGuests || i < 0 || i >= len(guests) {
+		return ClickUpMember{}, false
+	}
+	return guests[i], true
+}
+
+func (m ClickUpPeople) selectedGroup() (ClickUpGroup, bool) {
+	i := m.table.Cursor()
+	if m.tab != tabGroups || i < 0 || i >= len(m.groups) {
+		return ClickUpGroup{}, false
+	}
+	return m.groups[i], true
+}
+
+// findGuest resolves a guest by email, username or user ID.
+func (m ClickUpPeople) findGuest(s string) (ClickUpMember, error) {
+	for _, g := range m.guests() {
+		if strconv.Itoa(g.ID) == s || strings.EqualFold(g.Email, s) || strings.EqualFold(g.Username, s) {
+			return g, nil
+		}
+	}
+	return ClickUpMember{}, fmt.Errorf("no guest %q; invite them from the Guests tab first", s)
+}
+
+func validLevel(level string) (string, error) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if level == "" {
+		return "read", nil
+	}
+	if !slices.Contains(guestPermissionLevels, level) {
+		return "", fmt.Errorf("permission level must be read, comment, edit or create")
+	}
+	return level, nil
+}
+
+// openInvite shows the guest form, filled from g when editing them.
+func (m *ClickUpPeople) openInvite(g *ClickUpGuest) tea.Cmd {
+	m.mode = peopleInvite
+	m.editingGuest = 0
+	m.perms = [4]bool{}
+	m.permCursor = 0
+	for i := range m.inviteInputs {
+		m.inviteInputs[i].Reset()
+	}
+	m.inviteInputs[inviteLevel].SetValue("read")
+	if g != nil {
+		m.editingGuest = g.User.ID
+		m.inviteInputs[inviteEmail].SetValue(g.User.Email)
+		m.perms = [4]bool{g.CanEditTags, g.CanSeeTimeSpent, g.CanSeeTimeEstimated, g.CanCreateViews}
+	}
+	m.status = ""
+	m.focusIndex = inviteEmail
+	if g != nil {
+		m.focusIndex = inviteLocation
+	}
+	return focusInput(m.inviteInputs, m.focusIndex)
+}
+
+func (m *ClickUpPeople) submitInvite() tea.Cmd {
+	email := strings.TrimSpace(m.inviteInputs[inviteEmail].Value())
+	location := strings.TrimSpace(m.inviteInputs[inviteLocation].Value())
+	level, err := validLevel(m.inviteInputs[inviteLevel].Value())
+	if err == nil && location != "" {
+		_, _, err = parseAccessLocation(location)
+	}
+	switch {
+	case email == "" || !strings.Contains(email, "@"):
+		m.status = "Enter the guest's email."
+		return nil
+	case err != nil:
+		m.status = err.Error()
+		return nil
+	}
+
+	perms := ClickUpGuestPermissions{
+		CanEditTags:         m.perms[0],
+		CanSeeTimeSpent:     m.perms[1],
+		CanSeeTimeEstimated: m.perms[2],
+		CanCreateViews:      m.perms[3],
+	}
+	clickup, guestID := m.clickup, m.editingGuest
+	m.loading = true
+	if guestID != 0 {
+		m.mode = peopleGuest
+		m.status = "Saving guest..."
+		return peopleChanged("Guest saved.", func() error {
+			if err := EditClickUpGuest(clickup, guestID, perms); err != nil {
+				return err
+			}
+			if location == "" {
+				return nil
+			}
+			return ShareWithClickUpGuest(clickup, guestID, location, level)
+		})
+	}
+
+	m.mode = peopleBrowse
+	m.status = "Inviting " + email + "..."
+	done := "Invited " + email + "."
+	if location != "" {
+		done = fmt.Sprintf("Invited %s with %s access to %s.", email, level, location)
+	}
+	return peopleChanged(done, func() error {
+		id, err := InviteClickUpGuest(clickup, email, perms)
+		if err != nil || location == "" {
+			return err
+		}
+		if err := ShareWithClickUpGuest(clickup, id, location, level); err != nil {
+			return fmt.Errorf("invited %s, but sharing failed: %w", email, err)
+		}
+		return nil
+	})
+}
+
+func (m *ClickUpPeople) updateInvite(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch key {
+	case "esc":
+		m.mode = peopleBrowse
+		if m.editingGuest != 0 {
+			m.mode = peopleGuest
+		}
+		m.status = ""
+		return nil
+	case "tab", "shift+tab":
+		m.focusIndex = nextFocus(m.focusIndex, inviteSubmit, key)
+		return focusInput(m.inviteInputs, m.focusIndex)
+	case "enter":
+		if m.focusIndex == inviteSubmit {
+			return m.submitInvite()
+		}
+		m.focusIndex = nextFocus(m.focusIndex, inviteSubmit, key)
+		return focusInput(m.inviteInputs, m.focusIndex)
+	}
+
+	switch m.focusIndex {
+	case invitePerms:
+		switch key {
+		case "up", "k":
+			if m.permCursor == 0 {
+				m.focusIndex = nextFocus(m.focusIndex, inviteSubmit, "up")
+				return focusInput(m.inviteInputs, m.focusIndex)
+			}
+			m.permCursor--
+		case "down", "j":
+			if m.permCursor == len(m.perms)-1 {
+				m.focusIndex = nextFocus(m.focusIndex, inviteSubmit, "down")
+				return focusInput(m.inviteInputs, m.focusIndex)
+			}
+			m.permCursor++
+		case " ":
+			m.perms[m.permCursor] = !m.perms[m.permCursor]
+		}
+		return nil
+	case inviteSubmit:
+		if key == "up" || key == "down" {
+			m.focusIndex = nextFocus(m.focusIndex, inviteSubmit, key)
+			return focusInput(m.inviteInputs, m.focusIndex)
+		}
+		return nil
+	}
+
+	if key == "up" || key == "down" {
+		m.focusIndex = nextFocus(m.focusIndex, inviteSubmit, key)
+		return focusInput(m.inviteInputs, m.focusIndex)
+	}
+	// A guest's email can't be changed once invited.
+	if m.focusIndex == inviteEmail && m.editingGuest != 0 {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.inviteInputs[m.focusIndex], cmd = m.inviteInputs[m.focusIndex].Update(msg)
+	return cmd
+}
+
+// openShare shows the share form with whichever of guest and location is known.
+func (m *ClickUpPeople) openShare(guest string, location string) tea.Cmd {
+	m.mode = peopleShare
+	m.shareInputs[shareGuest].SetValue(guest)
+	m.shareInputs[shareLocation].SetValue(location)
+	m.shareInputs[shareLevel].SetValue("read")
+	m.status = ""
+	m.focusIndex = shareGuest
+	if guest != "" {
+		m.focusIndex = shareLocation
+	}
+	if guest != "" && location != "" {
+		m.focusIndex = shareLevel
+	}
+	return focusInput(m.shareInputs, m.focusIndex)
+}
+
+// shareReturn is where the share form goes back to.
+func (m ClickUpPeople) shareReturn() peopleMode {
+	if m.guest != nil && m.tab == tabGuests {
+		return peopleGuest
+	}
+	return peopleBrowse
+}
+
+func (m *ClickUpPeople) submitShare() tea.Cmd {
+	location := strings.TrimSpace(m.shareInputs[shareLocation].Value())
+	guest, err := m.findGuest(strings.TrimSpace(m.shareInputs[shareGuest].Value()))
+	if err == nil {
+		_, _, err = parseAccessLocation(location)
+	}
+	var level string
+	if err == nil {
+		level, err = validLevel(m.shareInputs[shareLevel].Value())
+	}
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+
+	clickup := m.clickup
+	m.mode = m.shareReturn()
+	m.loading = true
+	m.status = "Sharing..."
+	done := fmt.Sprintf("Gave %s %s access to %s.", guest.Email, level, location)
+	return peopleChanged(done, func() error { return ShareWithClickUpGuest(clickup, guest.ID, location, level) })
+}
+
+func (m *ClickUpPeople) updateShare(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch key {
+	case "esc":
+		m.mode = m.shareReturn()
+		m.status = ""
+		return nil
+	case "tab", "shift+tab", "up", "down":
+		m.focusIndex = nextFocus(m.focusIndex, shareSubmit, key)
+		return focusInput(m.shareInputs, m.focusIndex)
+	case "enter":
+		if m.focusIndex == shareSubmit {
+			return m.submitShare()
+		}
+		m.focusIndex = nextFocus(m.focusIndex, shareSubmit, key)
+		return focusInput(m.shareInputs, m.focusIndex)
+	}
+	if m.focusIndex == shareSubmit {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.shareInputs[m.focusIndex], cmd = m.shareInputs[m.focusIndex].Update(msg)
+	return cmd
+}
+
+// openGroup shows the group form, filled from g when editing it.
+func (m *ClickUpPeople) openGroup(g *ClickUpGroup) tea.Cmd {
+	m.mode = peopleGroup
+	m.editingGroup = g
+	for i := range m.groupInputs {
+		m.groupInputs[i].Reset()
+	}
+	if g != nil {
+		m.groupInputs[groupName].SetValue(g.Name)
+		m.groupInputs[groupHandle].SetValue(g.Handle)
+		m.groupInputs[groupMembers].SetValue(userNames(g.Members))
+	}
+	m.status = ""
+	m.focusIndex = groupName
+	return focusInput(m.groupInputs, m.focusIndex)
+}
+
+func (m *ClickUpPeople) submitGroup() tea.Cmd {
+	name := strings.TrimSpace(m.groupInputs[groupName].Value())
+	handle := strings.TrimSpace(m.groupInputs[groupHandle].Value())
+	if name == "" {
+		m.status = "Name the group."
+		return nil
+	}
+	var members []int
+	for _, s := range strings.Split(m.groupInputs[groupMembers].Value(), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := resolveClickUpMember(m.users(), m.clickup, s)
+		if err != nil {
+			m.status = err.Error()
+			return nil
+		}
+		if !slices.Contains(members, id) {
+			members = append(members, id)
+		}
+	}
+	if len(members) == 0 {
+		m.status = "Add at least one member."
+		return nil
+	}
+
+	clickup := m.clickup
+	m.mode = peopleBrowse
+	m.loading = true
+	if m.editingGroup != nil {
+		g := *m.editingGroup
+		m.status = "Saving group..."
+		return peopleChanged("Group saved.", func() error { return UpdateClickUpGroup(clickup, g, name, handle, members) })
+	}
+	m.status = "Creating group..."
+	return peopleChanged("Group created.", func() error { return CreateClickUpGroup(clickup, name, handle, members) })
+}
+
+func (m *ClickUpPeople) updateGroup(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch key {
+	case "esc":
+		m.mode = peopleBrowse
+		m.status = ""
+		return nil
+	case "tab", "shift+tab", "up", "down":
+		m.focusIndex = nextFocus(m.focusIndex, groupSubmit, key)
+		return focusInput(m.groupInputs, m.focusIndex)
+	case "enter":
+		if m.focusIndex == groupSubmit {
+			return m.submitGroup()
+		}
+		m.focusIndex = nextFocus(m.focusIndex, groupSubmit, key)
+		return focusInput(m.groupInputs, m.focusIndex)
+	}
+	if m.focusIndex == groupSubmit {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.groupInputs[m.focusIndex], cmd = m.groupInputs[m.focusIndex].Update(msg)
+	return cmd
+}
+
+// updateGuest handles keys on a guest's page.
+func (m *ClickUpPeople) updateGuest(msg tea.KeyMsg) tea.Cmd {
+	g := *m.guest
+	shares := g.shares()
+	switch msg.String() {
+	case "esc":
+		m.mode = peopleBrowse
+		m.guest = nil
+		m.status = ""
+	case "up", "k":
+		m.shareCursor = max(m.shareCursor-1, 0)
+	case "down", "j":
+		m.shareCursor = max(min(m.shareCursor+1, len(shares)-1), 0)
+	case "e":
+		return m.openInvite(&g)
+	case "a":
+		return m.openShare(g.User.Email, "")
+	case "x":
+		if m.shareCursor >= len(shares) {
+			return nil
+		}
+		s := shares[m.shareCursor]
+		clickup := m.clickup
+		m.status = fmt.Sprintf("Stop sharing %s %s with %s? (y/n)", s.Kind, shareName(s), g.User.Email)
+		m.confirm = peopleChanged("Stopped sharing "+s.location()+".", func() error {
+			return UnshareWithClickUpGuest(clickup, g.User.ID, s.location())
+		})
+	}
+	return nil
+}
+
+func shareName(s ClickUpGuestShare) string {
+	if s.Name == "" {
+		return s.ID
+	}
+	return s.Name
+}
+
+// updateAccess handles keys on the Privacy & Access tab.
+func (m *ClickUpPeople) updateAccess(msg tea.KeyMsg) tea.Cmd {
+	if m.accessInput.Focused() {
+		switch msg.String() {
+		case "esc":
+			if m.access == nil {
+				return func() tea.Msg { return ShowClickUpMenuMsg{} }
+			}
+			m.accessInput.Blur()
+			return nil
+		case "enter":
+			location := strings.TrimSpace(m.accessInput.Value())
+			if _, _, err := parseAccessLocation(location); err != nil {
+				m.status = err.Error()
+				return nil
+			}
+			m.accessInput.Blur()
+			m.loading = true
+			m.status = "Checking access to " + location + "..."
+			return m.loadAccess(location, "")
+		}
+		var cmd tea.Cmd
+		m.accessInput, cmd = m.accessInput.Update(msg)
+		return cmd
+	}
+
+	switch msg.String() {
+	case "/":
+		return m.accessInput.Focus()
+	}
+	if m.access == nil {
+		return nil
+	}
+	a := *m.access
+	switch msg.String() {
+	case "up", "k":
+		m.accessCursor = max(m.accessCursor-1, 0)
+	case "down", "j":
+		m.accessCursor = max(min(m.accessCursor+1, len(a.Guests)-1), 0)
+	case "a":
+		if a.Kind == "space" {
+			m.status = "Guests can't be given a whole space; share its folders or lists."
+			return nil
+		}
+		return m.openShare("", a.location())
+	case "x":
+		if m.accessCursor >= len(a.Guests) {
+			return nil
+		}
+		g := a.Guests[m.accessCursor].Guest
+		clickup := m.clickup
+		m.status = fmt.Sprintf("Take %s's access to %s away? (y/n)", g.Email, a.location())
+		m.confirm = peopleChanged(g.Email+" no longer has access.", func() error {
+			return UnshareWithClickUpGuest(clickup, g.ID, a.location())
+		})
+	}
+	return nil
+}
+
+func (m ClickUpPeople) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpPeopleMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.people, m.groups, m.groupsErr, m.roles, m.rolesErr = msg.people, msg.groups, msg.groupsErr, msg.roles, msg.rolesErr
+		m.showTab()
+		m.status = msg.done
+		if m.status == "" {
+			m.status = fmt.Sprintf("%s • %s", countOf(len(m.members()), "member"), countOf(len(m.guests()), "guest"))
+		}
+		return m, nil
+
+	case ClickUpGuestMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if m.mode != peopleBrowse && m.mode != peopleGuest {
+			return m, nil
+		}
+		m.guest = &msg.guest
+		m.mode = peopleGuest
+		m.shareCursor = max(min(m.shareCursor, len(msg.guest.shares())-1), 0)
+		return m, nil
+
+	case ClickUpAccessMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.access = &msg.access
+		m.accessCursor = max(min(m.accessCursor, len(msg.access.Guests)-1), 0)
+		m.status = msg.done
+		if m.status == "" {
+			m.status = fmt.Sprintf("%s • %s", countOf(len(msg.access.Members), "member"), countOf(len(msg.access.Guests), "guest"))
+		}
+		return m, nil
+
+	case ClickUpPeopleChangedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.loading = true
+		cmds := []tea.Cmd{m.load(msg.done)}
+		if m.mode == peopleGuest && m.guest != nil {
+			cmds = append(cmds, m.loadGuest(m.guest.User.ID))
+		}
+		if m.tab == tabAccess && m.access != nil {
+			cmds = append(cmds, m.loadAccess(m.access.location(), msg.done))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case peopleInvite:
+			return m, m.updateInvite(msg)
+		case peopleShare:
+			return m, m.updateShare(msg)
+		case peopleGroup:
+			return m, m.updateGroup(msg)
+		}
+
+		if m.confirm != nil {
+			confirm := m.confirm
+			m.confirm = nil
+			if msg.String() != "y" && msg.String() != "Y" {
+				m.status = "Cancelled."
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Saving..."
+			return m, confirm
+		}
+		if m.loading {
+			if msg.String() == "esc" && m.mode == peopleBrowse {
+				return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+			}
+			return m, nil
+		}
+
+		if m.mode == peopleGuest {
+			return m, m.updateGuest(msg)
+		}
+
+		switch s := msg.String(); s {
+		case "tab":
+			return m, m.switchTab((m.tab + 1) % peopleTab(len(peopleTabNames)))
+		case "shift+tab":
+			return m, m.switchTab((m.tab + peopleTab(len(peopleTabNames)) - 1) % peopleTab(len(peopleTabNames)))
+		case "1", "2", "3", "4", "5":
+			if !m.accessInput.Focused() {
+				return m, m.switchTab(peopleTab(s[0] - '1'))
+			}
+		}
+
+		if m.tab == tabAccess {
+			if !m.accessInput.Focused() {
+				switch msg.String() {
+				case "q":
+					return m, tea.Quit
+				case "esc":
+					return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+				case "r":
+					if m.access != nil {
+						m.loading = true
+						m.status = "Refreshing..."
+						return m, m.loadAccess(m.access.location(), "")
+					}
+				}
+			}
+			return m, m.updateAccess(msg)
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+		case "r":
+			m.loading = true
+			m.status = "Refreshing..."
+			return m, m.load("")
+		}
+
+		switch m.tab {
+		case tabGuests:
+			if msg.String() == "n" {
+				return m, m.openInvite(nil)
+			}
+			g, ok := m.selectedGuest()
+			if !ok {
+				break
+			}
+			switch msg.String() {
+			case "enter":
+				m.loading = true
+				m.shareCursor = 0
+				m.status = "Loading " + g.Email + "..."
+				return m, m.loadGuest(g.ID)
+			case "a":
+				return m, m.openShare(g.Email, "")
+			case "x":
+				clickup := m.clickup
+				m.status = fmt.Sprintf("Remove guest %s from the workspace? (y/n)", g.Email)
+				m.confirm = peopleChanged("Removed "+g.Email+".", func() error { return RemoveClickUpGuest(clickup, g.ID) })
+				return m, nil
+			}
+
+		case tabGroups:
+			if msg.String() == "n" {
+				return m, m.openGroup(nil)
+			}
+			g, ok := m.selectedGroup()
+			if !ok {
+				break
+			}
+			switch msg.String() {
+			case "enter", "e":
+				return m, m.openGroup(&g)
+			case "x":
+				clickup := m.clickup
+				m.status = fmt.Sprintf("Delete the group %s? (y/n)", g.Name)
+				m.confirm = peopleChanged("Group deleted.", func() error { return DeleteClickUpGroup(clickup, g.ID) })
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m ClickUpPeople) tabsView() string {
+	tabs := make([]string, len(peopleTabNames))
+	for i, name := range peopleTabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if peopleTab(i) == m.tab {
+			tabs[i] = focusedStyle.Render("[" + label + "]")
+		} else {
+			tabs[i] = blurredStyle.Render(" " + label + " ")
+		}
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (m ClickUpPeople) inviteView() string {
+	var b strings.Builder
+
+	if m.editingGuest != 0 {
+		b.WriteString("Edit guest " + m.inviteInputs[inviteEmail].Value() + "\n\n")
+	} else {
+		b.WriteString("Invite a guest\n\n")
+	}
+	for i := range m.inviteInputs {
+		b.WriteString(m.inviteInputs[i].View() + "\n")
+	}
+	b.WriteString("\n")
+	for i, name := range guestPermissionNames {
+		box := "[ ]"
+		if m.perms[i] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", box, name)
+		if m.focusIndex == invitePerms && i == m.permCursor {
+			line = focusedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	button := &blurredButton
+	if m.focusIndex == inviteSubmit {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+	b.WriteString(helpStyle.Render("tab: next field • space: toggle permission • esc: cancel"))
+	return b.String()
+}
+
+func formView(title string, inputs []textinput.Model, submitFocused bool, help string) string {
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	for i := range inputs {
+		b.WriteString(inputs[i].View() + "\n")
+	}
+	button := &blurredButton
+	if submitFocused {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+	b.WriteString(helpStyle.Render(help))
+	return b.String()
+}
+
+func (m ClickUpPeople) guestView() string {
+	var b strings.Builder
+	g := m.guest
+
+	if g.User.Username != "" {
+		fmt.Fprintf(&b, "%s <%s>\n", g.User.Username, g.User.Email)
+	} else {
+		b.WriteString(g.User.Email + "\n")
+	}
+	invited := "Invited " + shortDate(g.User.DateInvited)
+	if g.InvitedBy.Username != "" {
+		invited += " by " + g.InvitedBy.Username
+	}
+	if g.User.pending() {
+		invited += " • hasn't joined yet"
+	}
+	b.WriteString(blurredStyle.Render(invited) + "\n\n")
+
+	perms := []bool{g.CanEditTags, g.CanSeeTimeSpent, g.CanSeeTimeEstimated, g.CanCreateViews}
+	for i, name := range guestPermissionNames {
+		mark := "✗"
+		if perms[i] {
+			mark = commentResolvedStyle.Render("✓")
+		}
+		fmt.Fprintf(&b, "%s %s\n", mark, name)
+	}
+
+	shares := g.shares()
+	fmt.Fprintf(&b, "\nShared with them: %s\n", countOf(len(shares), "item"))
+	for i, s := range shares {
+		line := fmt.Sprintf("%-7s %-40s %s", s.Kind, shareName(s), s.PermissionLevel)
+		if i == m.shareCursor {
+			line = focusedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n" + helpStyle.Render("a: share more • x: stop sharing • e: edit permissions • esc: back"))
+	return b.String()
+}
+
+func (m ClickUpPeople) accessView() string {
+	var b strings.Builder
+
+	b.WriteString(m.accessInput.View() + "\n\n")
+	if a := m.access; a != nil {
+		title := fmt.Sprintf("%s %s", a.Kind, a.Name)
+		if a.Kind == "space" {
+			if a.Private {
+				title += " • private"
+			} else {
+				title += " • open to all members"
+			}
+		}
+		b.WriteString(breadcrumbStyle.Render(title) + "\n\n")
+
+		switch {
+		case a.Kind == "folder":
+			b.WriteString(blurredStyle.Render("ClickUp doesn't list a folder's members; open one of its lists to see them.") + "\n")
+		case len(a.Members) == 0 && a.Kind == "space" && !a.Private:
+			b.WriteString("Every workspace member.\n")
+		default:
+			fmt.Fprintf(&b, "Members: %s\n", userNames(a.Members))
+		}
+
+		if a.Kind != "space" {
+			fmt.Fprintf(&b, "\nGuests: %s\n", countOf(len(a.Guests), "guest"))
+			for i, g := range a.Guests {
+				line := fmt.Sprintf("%-34s %s", g.Guest.Email, g.Level)
+				if i == m.accessCursor {
+					line = focusedStyle.Render("> ") + line
+				} else {
+					line = "  " + line
+				}
+				b.WriteString(line + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if m.accessInput.Focused() {
+		b.WriteString(helpStyle.Render("enter: check access • tab: next tab • esc: back"))
+	} else {
+		b.WriteString(helpStyle.Render("/: other location • a: share with a guest • x: take a guest's access • r: refresh • tab: next tab • esc: back"))
+	}
+	return b.String()
+}
+
+func (m ClickUpPeople) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nPeople & Access\n\n")
+
+	switch m.mode {
+	case peopleInvite:
+		b.WriteString(m.inviteView())
+	case peopleShare:
+		b.WriteString(formView("Share with a guest", m.shareInputs, m.focusIndex == shareSubmit, "tab: next field • esc: cancel"))
+	case peopleGroup:
+		title := "New user group"
+		if m.editingGroup != nil {
+			title = "Edit user group " + m.editingGroup.Name
+		}
+		b.WriteString(formView(title, m.groupInputs, m.focusIndex == groupSubmit, "tab: next field • esc: cancel"))
+	case peopleGuest:
+		b.WriteString(m.guestView())
+	default:
+		b.WriteString(m.tabsView() + "\n\n")
+		if m.tab == tabAccess {
+			b.WriteString(m.accessView())
+			break
+		}
+		b.WriteString(m.table.View() + "\n\n")
+		switch m.tab {
+		case tabGuests:
+			if !m.loading && len(m.guests()) == 0 {
+				b.WriteString("No guests in this workspace.\n\n")
+			}
+			b.WriteString(helpStyle.Render("n: invite • enter: shared items • a: share • x: remove • r: refresh • tab: next tab • esc: back"))
+		case tabGroups:
+			if m.groupsErr != nil {
+				b.WriteString(statusWarningStyle.Render(fmt.Sprintf("User groups unavailable: %v", m.groupsErr)) + "\n\n")
+			}
+			b.WriteString(helpStyle.Render("n: new • e: edit • x: delete • r: refresh • tab: next tab • esc: back"))
+		case tabRoles:
+			if m.rolesErr != nil {
+				b.WriteString(blurredStyle.Render("Custom roles need an Enterprise workspace.") + "\n\n")
+			}
+			b.WriteString(helpStyle.Render("r: refresh • tab: next tab • esc: back"))
+		default:
+			b.WriteString(helpStyle.Render("r: refresh • tab: next tab • esc: back"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
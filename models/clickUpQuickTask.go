@@ -0,0 +1,432 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickTaskKey opens the quick task form from any screen once logged in. It
+// must be a key no text input binds; ctrl+n moves down a line in a textarea.
+const quickTaskKey = "ctrl+o"
+
+// quickTaskMatches is how many matching lists the picker shows at once.
+const quickTaskMatches = 6
+
+// Focus positions in the quick task form; the submit button follows them.
+const (
+	quickList = iota
+	quickTitle
+	quickDescription
+	quickAssignee
+	quickDue
+	quickFields
+)
+
+// ClickUpTaskDraft prefills the quick task form from what is on screen.
+type ClickUpTaskDraft struct {
+	Title       string
+	Description string
+	// ListID, when set, is picked in place of the last list used.
+	ListID string
+}
+
+// draftTitle makes a task title from the first line of some text.
+func draftTitle(text string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if r := []rune(title); len(r) > 80 {
+		return string(r[:79]) + "…"
+	}
+	return title
+}
+
+// quoteMarkdown quotes text as a markdown block quote.
+func quoteMarkdown(text string) string {
+	return "> " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n> ")
+}
+
+// ClickUpQuickTask is a compact form for creating a task without leaving
+// the current screen.
+type ClickUpQuickTask struct {
+	focusIndex int
+	// inputs are the list search, title, assignee and due date.
+	inputs      []textinput.Model
+	description textarea.Model
+	lists       []ClickUpList
+	members     []ClickUpUser
+	// matches indexes lists that fit the search, best first; pick is the
+	// selected one.
+	matches   []int
+	pick      int
+	preferred string
+	lastList  string
+	created   *ClickUpTask
+	loading   bool
+	status    string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpQuickTaskDataMsg struct {
+	lists   []ClickUpList
+	members []ClickUpUser
+	err     error
+}
+
+type ClickUpTaskCreatedMsg struct {
+	task ClickUpTask
+	err  error
+}
+
+// CloseQuickTaskMsg returns to the screen the quick task form was opened from.
+type CloseQuickTaskMsg struct {
+	created bool
+}
+
+func InitialClickUpQuickTask(token string, refreshToken string, user User, clickup ClickUpProfile, draft ClickUpTaskDraft) ClickUpQuickTask {
+	inputs := []textinput.Model{
+		newFormInput("List (type to search)", 100, 60),
+		newFormInput("Title", 200, 60),
+		newFormInput("Assignee: name, email or me (optional)", 64, 60),
+		newFormInput("Due: tomorrow, next friday, in 3 days, 2024-05-31 (optional)", 64, 60),
+	}
+	inputs[1].SetValue(draft.Title)
+
+	d := textarea.New()
+	d.Placeholder = "Description (markdown, optional)"
+	d.SetWidth(80)
+	d.SetHeight(6)
+	d.CharLimit = 0
+	d.ShowLineNumbers = false
+	d.SetValue(draft.Description)
+
+	m := ClickUpQuickTask{
+		inputs:       inputs,
+		description:  d,
+		preferred:    draft.ListID,
+		loading:      true,
+		status:       "Loading lists...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+	m.focus(quickList)
+	if clickup.WorkspaceID == "" {
+		m.loading = false
+		m.status = "Sign in to ClickUp first (main menu › ClickUp)."
+	}
+	return m
+}
+
+// useLists reuses what an earlier form loaded for the same workspace.
+func (m *ClickUpQuickTask) useLists(lists []ClickUpList, members []ClickUpUser, lastList string) {
+	m.lists, m.members, m.lastList = lists, members, lastList
+	m.loading = false
+	m.status = ""
+	m.filterLists()
+}
+
+func (m ClickUpQuickTask) Init() tea.Cmd {
+	cmds := []tea.Cmd{tea.SetWindowTitle("New ClickUp Task"), textinput.Blink}
+	if m.loading {
+		clickup := m.clickup
+		cmds = append(cmds, func() tea.Msg {
+			lists, err := ListAllClickUpLists(clickup)
+			if err != nil {
+				return ClickUpQuickTaskDataMsg{err: err}
+			}
+			members, err := ClickUpMembers(clickup)
+			return ClickUpQuickTaskDataMsg{lists: lists, members: members, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// focus moves focus to a position in the form, which may be the submit button.
+func (m *ClickUpQuickTask) focus(i int) tea.Cmd {
+	m.focusIndex = i
+	m.description.Blur()
+
+	input := len(m.inputs)
+	switch i {
+	case quickList, quickTitle:
+		input = i
+	case quickAssignee, quickDue:
+		input = i - 1
+	}
+	cmd := focusInput(m.inputs, input)
+	if i == quickDescription {
+		return m.description.Focus()
+	}
+	return cmd
+}
+
+// fuzzyScore matches pattern against s as a case-insensitive subsequence,
+// scoring runs of adjacent letters and word starts higher.
+func fuzzyScore(pattern string, s string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score, j, last := 0, 0, -2
+	runes := []rune(strings.ToLower(s))
+	for i, r := range runes {
+		if j == len(p) {
+			break
+		}
+		if r != p[j] {
+			continue
+		}
+		switch {
+		case i == last+1:
+			score += 5
+		case i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]):
+			score += 3
+		default:
+			score++
+		}
+		last = i
+		j++
+	}
+	if j < len(p) {
+		return 0, false
+	}
+	// Prefer shorter names when the match is otherwise as good.
+	return score*100 - len(runes), true
+}
+
+// filterLists orders the lists matching the search. With no search the list
+// from the current screen, or else the last one used, comes first.
+func (m *ClickUpQuickTask) filterLists() {
+	search := strings.TrimSpace(m.inputs[0].Value())
+	scores := make(map[int]int)
+	m.matches = nil
+	for i, l := range m.lists {
+		if score, ok := fuzzyScore(search, l.path()); ok {
+			scores[i] = score
+			m.matches = append(m.matches, i)
+		}
+	}
+
+	if search != "" {
+		slices.SortStableFunc(m.matches, func(a, b int) int { return scores[b] - scores[a] })
+	} else {
+		for _, id := range []string{m.lastList, m.preferred} {
+			if k := slices.IndexFunc(m.matches, func(i int) bool { return m.lists[i].ID == id }); k > 0 {
+				i := m.matches[k]
+				m.matches = slices.Insert(slices.Delete(m.matches, k, k+1), 0, i)
+			}
+		}
+	}
+	m.pick = 0
+}
+
+func (m ClickUpQuickTask) selectedList() (ClickUpList, bool) {
+	if len(m.matches) == 0 {
+		return ClickUpList{}, false
+	}
+	return m.lists[m.matches[m.pick]], true
+}
+
+func (m *ClickUpQuickTask) submit() tea.Cmd {
+	list, ok := m.selectedList()
+	if !ok {
+		m.status = "Pick a list for the task."
+		return m.focus(quickList)
+	}
+	task := ClickUpNewTask{
+		Name:                strings.TrimSpace(m.inputs[1].Value()),
+		MarkdownDescription: strings.TrimSpace(m.description.Value()),
+	}
+	if task.Name == "" {
+		m.status = "The task needs a title."
+		return m.focus(quickTitle)
+	}
+	if name := strings.TrimSpace(m.inputs[2].Value()); name != "" {
+		id, err := resolveClickUpMember(m.members, m.clickup, name)
+		if err != nil {
+			m.status = err.Error()
+			return m.focus(quickAssignee)
+		}
+		task.Assignees = []int{id}
+	}
+	if due := strings.TrimSpace(m.inputs[3].Value()); due != "" {
+		d, err := parseNaturalDate(due, time.Now())
+		if err != nil {
+			m.status = err.Error()
+			return m.focus(quickDue)
+		}
+		task.DueDate = d.UnixMilli()
+	}
+
+	m.loading = true
+	m.status = "Creating task..."
+	m.lastList = list.ID
+	clickup := m.clickup
+	return func() tea.Msg {
+		created, err := CreateClickUpTask(clickup, list.ID, task)
+		return ClickUpTaskCreatedMsg{task: created, err: err}
+	}
+}
+
+func (m ClickUpQuickTask) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpQuickTaskDataMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.useLists(msg.lists, msg.members, m.lastList)
+		if len(m.lists) == 0 {
+			m.status = "This workspace has no lists."
+		}
+		return m, nil
+
+	case ClickUpTaskCreatedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.created = &msg.task
+		m.status = ""
+		return m, m.focus(quickFields)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			created := m.created != nil
+			return m, func() tea.Msg { return CloseQuickTaskMsg{created: created} }
+		}
+		if m.loading {
+			return m, nil
+		}
+		if m.created != nil {
+			switch msg.String() {
+			case "o":
+				if err := openBrowser(m.created.URL); err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+				}
+			case "enter":
+				return m, func() tea.Msg { return CloseQuickTaskMsg{created: true} }
+			}
+			return m, nil
+		}
+		if m.clickup.WorkspaceID == "" {
+			return m, nil
+		}
+
+		s := msg.String()
+		switch {
+		case m.focusIndex == quickList && (s == "up" || s == "down"):
+			if s == "up" && m.pick > 0 {
+				m.pick--
+			} else if s == "down" && m.pick < len(m.matches)-1 {
+				m.pick++
+			}
+			return m, nil
+		case s == "enter" && m.focusIndex == quickFields:
+			return m, m.submit()
+		case s == "tab" || s == "shift+tab",
+			m.focusIndex != quickDescription && (s == "enter" || s == "up" || s == "down"):
+			return m, m.focus(nextFocus(m.focusIndex, quickFields, s))
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focusIndex {
+	case quickDescription:
+		m.description, cmd = m.description.Update(msg)
+	case quickList:
+		search := m.inputs[0].Value()
+		m.inputs[0], cmd = m.inputs[0].Update(msg)
+		if m.inputs[0].Value() != search {
+			m.filterLists()
+		}
+	case quickTitle, quickAssignee, quickDue:
+		i := m.focusIndex
+		if i > quickDescription {
+			i--
+		}
+		m.inputs[i], cmd = m.inputs[i].Update(msg)
+	}
+	return m, cmd
+}
+
+func (m ClickUpQuickTask) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nNew ClickUp Task\n\n")
+
+	if m.created != nil {
+		fmt.Fprintf(&b, "Created %q", m.created.Name)
+		if list, ok := m.selectedList(); ok {
+			b.WriteString(" in " + list.path())
+		}
+		b.WriteString("\n" + m.created.URL + "\n\n")
+		b.WriteString(helpStyle.Render("o: open in browser • enter/esc: back"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(m.inputs[0].View() + "\n")
+	if m.focusIndex == quickList {
+		start := max(0, min(m.pick-quickTaskMatches/2, len(m.matches)-quickTaskMatches))
+		for k := start; k < min(start+quickTaskMatches, len(m.matches)); k++ {
+			line := "    " + m.lists[m.matches[k]].path()
+			if k == m.pick {
+				line = focusedStyle.Render("  > " + m.lists[m.matches[k]].path())
+			}
+			b.WriteString(line + "\n")
+		}
+		if !m.loading && len(m.lists) > 0 && len(m.matches) == 0 {
+			b.WriteString("    no matching list\n")
+		}
+	} else if list, ok := m.selectedList(); ok {
+		b.WriteString(blurredStyle.Render("    "+list.path()) + "\n")
+	}
+
+	b.WriteString(m.inputs[1].View() + "\n\n")
+	b.WriteString(m.description.View() + "\n\n")
+	b.WriteString(m.inputs[2].View() + "\n")
+	b.WriteString(m.inputs[3].View())
+	if due := strings.TrimSpace(m.inputs[3].Value()); due != "" {
+		if d, err := parseNaturalDate(due, time.Now()); err == nil {
+			b.WriteString(blurredStyle.Render("  → " + d.Format("Mon 2006-01-02")))
+		}
+	}
+	b.WriteString("\n")
+
+	button := &blurredButton
+	if m.focusIndex == quickFields {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+
+	if m.focusIndex == quickList {
+		b.WriteString(helpStyle.Render("↑/↓: pick list • tab/enter: next • esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("tab: next field • enter on Submit: create • esc: cancel"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		score   int
+		ok      bool
+	}{
+		{"", "Engineering", 0, true},
+		// A match at the start, then two adjacent runes: 3 + 5 + 5.
+		{"eng", "Engineering", 1289, true},
+		{"ENG", "engineering", 1289, true},
+		// Both runes start a word: 3 + 3.
+		{"sb", "Sprints / Backlog", 583, true},
+		// Mid-word runes score least, however close they are.
+		{"gne", "Engineering", 689, true},
+		{"xyz", "Engineering", 0, false},
+		{"engineerings", "Engineering", 0, false},
+	}
+	for _, tt := range tests {
+		score, ok := fuzzyScore(tt.pattern, tt.s)
+		if score != tt.score || ok != tt.ok {
+			t.Errorf("fuzzyScore(%q, %q) = %d, %v, want %d, %v", tt.pattern, tt.s, score, ok, tt.score, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyScoreOrder(t *testing.T) {
+	tests := []struct {
+		pattern string
+		better  string
+		worse   string
+	}{
+		{"back", "Backlog", "Engineering / Backlog"},
+		{"back", "Engineering / Backlog", "Feedback"},
+		{"sp", "Sprints", "Dispatch"},
+	}
+	for _, tt := range tests {
+		better, _ := fuzzyScore(tt.pattern, tt.better)
+		worse, _ := fuzzyScore(tt.pattern, tt.worse)
+		if better <= worse {
+			t.Errorf("%q: %q scored %d, not above %q at %d", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
@@ -0,0 +1,871 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// taskDetailHeight is how many lines of the task show at once.
+	taskDetailHeight = 30
+	// descriptionPreviewLines is how much of the description shows until it is expanded.
+	descriptionPreviewLines = 12
+)
+
+var detailLabelStyle = lipgloss.NewStyle().Width(14).Foreground(lipgloss.Color("241"))
+
+type taskRowKind int
+
+const (
+	taskRowName taskRowKind = iota
+	taskRowStatus
+	taskRowPriority
+	taskRowAssignees
+	taskRowStart
+	taskRowDue
+	taskRowTags
+	taskRowField
+	taskRowDescription
+	taskRowSubtask
+	taskRowChecklist
+	taskRowChecklistItem
+	taskRowDependency
+	taskRowLink
+)
+
+// taskRow is a selectable line of the detail screen. index picks the custom
+// field, subtask, checklist, dependency or link; item picks a checklist item.
+type taskRow struct {
+	kind  taskRowKind
+	index int
+	item  int
+}
+
+type taskDetailMode int
+
+const (
+	taskBrowse taskDetailMode = iota
+	taskInput
+	taskChoose
+)
+
+type ClickUpTaskDetail struct {
+	taskID        string
+	fromNavigator bool
+	// parents are the tasks above an open subtask, for esc to return to.
+	parents     []string
+	task        ClickUpTask
+	statuses    []ClickUpStatus
+	members     []ClickUpUser
+	related     map[string]string
+	description string
+	expanded    bool
+	rows        []taskRow
+	cursor      int
+	mode        taskDetailMode
+	// editing is the row being changed; for new checklists and
+	// relationships it is the row the cursor was on.
+	editing      taskRow
+	addingWhat   string
+	input        textinput.Model
+	choices      []string
+	choice       int
+	changed      bool
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+// ClickUpTaskRequestMsg opens a task from the task list or the navigator.
+type ClickUpTaskRequestMsg struct {
+	taskID        string
+	fromNavigator bool
+}
+
+// ShowClickUpTasksMsg returns to the task list, reloading it after an edit.
+type ShowClickUpTasksMsg struct {
+	reload bool
+}
+
+type ClickUpTaskDetailMsg struct {
+	task        ClickUpTask
+	statuses    []ClickUpStatus
+	members     []ClickUpUser
+	related     map[string]string
+	description string
+	err         error
+}
+
+type ClickUpTaskEditedMsg struct {
+	err error
+}
+
+func InitialClickUpTaskDetail(token string, refreshToken string, user User, clickup ClickUpProfile, taskID string, fromNavigator bool) ClickUpTaskDetail {
+	return ClickUpTaskDetail{
+		taskID:        taskID,
+		fromNavigator: fromNavigator,
+		input:         newFormInput("", 1024, 70),
+		loading:       true,
+		status:        "Loading...",
+		token:         token,
+		refreshToken:  refreshToken,
+		user:          user,
+		clickup:       clickup,
+	}
+}
+
+func (m ClickUpTaskDetail) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp Task"), m.load())
+}
+
+// load fetches the task with everything the screen shows about it: the
+// list's statuses, the names of related tasks and the rendered description.
+func (m ClickUpTaskDetail) load() tea.Cmd {
+	clickup, taskID, members := m.clickup, m.taskID, m.members
+	return func() tea.Msg {
+		task, err := GetClickUpTaskDetail(clickup, taskID)
+		if err != nil {
+			return ClickUpTaskDetailMsg{err: err}
+		}
+		statuses, err := ListClickUpStatuses(clickup, task.List.ID)
+		if err != nil {
+			return ClickUpTaskDetailMsg{err: err}
+		}
+		if members == nil {
+			if members, err = ClickUpMembers(clickup); err != nil {
+				return ClickUpTaskDetailMsg{err: err}
+			}
+		}
+
+		related := make(map[string]string)
+		for _, id := range relatedTaskIDs(task) {
+			if t, err := GetClickUpTask(clickup, id); err == nil {
+				related[id] = t.Name
+			}
+		}
+
+		return ClickUpTaskDetailMsg{
+			task:        task,
+			statuses:    statuses,
+			members:     members,
+			related:     related,
+			description: renderMarkdown(task),
+		}
+	}
+}
+
+func relatedTaskIDs(t ClickUpTask) []string {
+	var ids []string
+	for _, d := range t.Dependencies {
+		ids = append(ids, d.TaskID, d.DependsOn)
+	}
+	for _, l := range t.LinkedTasks {
+		ids = append(ids, l.TaskID, l.LinkID)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	return slices.DeleteFunc(ids, func(id string) bool { return id == t.ID })
+}
+
+// renderMarkdown renders the task description for the terminal.
+func renderMarkdown(t ClickUpTask) string {
+	text := t.MarkdownDescription
+	if text == "" {
+		text = t.TextContent
+	}
+	return renderMarkdownText(text)
+}
+
+// renderMarkdownText renders markdown for the terminal, falling back to the
+// text itself when it can't.
+func renderMarkdownText(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	r, err := glamour.NewTermRenderer(glamour.WithStandardStyle("dark"), glamour.WithWordWrap(100))
+	if err != nil {
+		return text
+	}
+	out, err := r.Render(text)
+	if err != nil {
+		return text
+	}
+	return strings.Trim(out, "\n")
+}
+
+func (m *ClickUpTaskDetail) buildRows() {
+	rows := []taskRow{
+		{kind: taskRowName}, {kind: taskRowStatus}, {kind: taskRowPriority}, {kind: taskRowAssignees},
+		{kind: taskRowStart}, {kind: taskRowDue}, {kind: taskRowTags},
+	}
+	for i := range m.task.CustomFields {
+		rows = append(rows, taskRow{kind: taskRowField, index: i})
+	}
+	rows = append(rows, taskRow{kind: taskRowDescription})
+	for i := range m.task.Subtasks {
+		rows = append(rows, taskRow{kind: taskRowSubtask, index: i})
+	}
+	for i, c := range m.task.Checklists {
+		rows = append(rows, taskRow{kind: taskRowChecklist, index: i})
+		for j := range c.Items {
+			rows = append(rows, taskRow{kind: taskRowChecklistItem, index: i, item: j})
+		}
+	}
+	for i := range m.task.Dependencies {
+		rows = append(rows, taskRow{kind: taskRowDependency, index: i})
+	}
+	for i := range m.task.LinkedTasks {
+		rows = append(rows, taskRow{kind: taskRowLink, index: i})
+	}
+	m.rows = rows
+	m.cursor = min(m.cursor, len(rows)-1)
+}
+
+// edited runs a change and reports back so the task can be reloaded.
+func edited(change func() error) tea.Cmd {
+	return func() tea.Msg { return ClickUpTaskEditedMsg{err: change()} }
+}
+
+func formatDate(t clickUpTime) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// dateChange reads a date typed for start_date or due_date; blank clears it.
+func dateChange(property string, text string) (map[string]any, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return map[string]any{property: nil}, nil
+	}
+	d, err := parseNaturalDate(text, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{property: d.UnixMilli(), property + "_time": false}, nil
+}
+
+func (m *ClickUpTaskDetail) startInput(prompt string, value string) tea.Cmd {
+	m.mode = taskInput
+	m.editing = m.rows[m.cursor]
+	m.input.Placeholder = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.status = ""
+	return m.input.Focus()
+}
+
+func (m *ClickUpTaskDetail) startChoice(choices []string, current string) {
+	m.mode = taskChoose
+	m.editing = m.rows[m.cursor]
+	m.choices = choices
+	m.choice = max(slices.Index(choices, current), 0)
+	m.status = ""
+}
+
+// edit starts changing the row under the cursor.
+func (m *ClickUpTaskDetail) edit() tea.Cmd {
+	t := m.task
+	row := m.rows[m.cursor]
+
+	switch row.kind {
+	case taskRowName:
+		return m.startInput("Task name", t.Name)
+	case taskRowStatus:
+		var names []string
+		for _, s := range m.statuses {
+			names = append(names, s.Status)
+		}
+		m.startChoice(names, t.Status.Status)
+	case taskRowPriority:
+		current := "none"
+		if t.Priority != nil {
+			current = t.Priority.Priority
+		}
+		m.startChoice(append(slices.Clone(clickUpPriorities[1:]), "none"), current)
+	case taskRowAssignees:
+		return m.startInput("Assignees, e.g. me, alex", strings.Join(assigneeNames(t), ", "))
+	case taskRowStart:
+		return m.startInput("Start date, e.g. tomorrow or 2024-05-31 (blank: none)", formatDate(t.StartDate))
+	case taskRowDue:
+		return m.startInput("Due date, e.g. next friday or 2024-05-31 (blank: none)", formatDate(t.DueDate))
+	case taskRowTags:
+		var tags []string
+		for _, tag := range t.Tags {
+			tags = append(tags, tag.Name)
+		}
+		return m.startInput("Tags, e.g. bug, backend", strings.Join(tags, ", "))
+	case taskRowField:
+		f := t.CustomFields[row.index]
+		switch f.Type {
+		case "checkbox":
+			clickup, checked := m.clickup, fieldText(f) == "true"
+			return edited(func() error { return SetClickUpField(clickup, t.ID, f.ID, !checked) })
+		case "drop_down":
+			names := []string{"(none)"}
+			for _, o := range f.TypeConfig.Options {
+				names = append(names, o.Name)
+			}
+			m.startChoice(names, fieldText(f))
+		default:
+			return m.startInput(f.Name, fieldText(f))
+		}
+	case taskRowDescription:
+		text := t.MarkdownDescription
+		if text == "" {
+			text = t.TextContent
+		}
+		m.editing = row
+		return openExternalEditor(text, "clickup-task-*.md")
+	case taskRowSubtask:
+		m.parents = append(m.parents, t.ID)
+		return m.open(t.Subtasks[row.index].ID)
+	case taskRowChecklist:
+		m.addingWhat = "item"
+		return m.startInput("New checklist item", "")
+	case taskRowChecklistItem:
+		return m.toggleItem(row)
+	}
+	return nil
+}
+
+func (m *ClickUpTaskDetail) toggleItem(row taskRow) tea.Cmd {
+	c := m.task.Checklists[row.index]
+	item := c.Items[row.item]
+	clickup := m.clickup
+	return edited(func() error { return ResolveClickUpChecklistItem(clickup, c.ID, item.ID, !item.Resolved) })
+}
+
+// open shows another task, e.g. a subtask, in the same screen.
+func (m *ClickUpTaskDetail) open(taskID string) tea.Cmd {
+	m.taskID = taskID
+	m.cursor = 0
+	m.expanded = false
+	m.loading = true
+	m.status = "Loading..."
+	return m.load()
+}
+
+// submitInput applies what was typed for the row being edited.
+func (m *ClickUpTaskDetail) submitInput() tea.Cmd {
+	clickup, t, text := m.clickup, m.task, strings.TrimSpace(m.input.Value())
+
+	if m.addingWhat != "" {
+		what := m.addingWhat
+		m.addingWhat = ""
+		switch what {
+		case "item":
+			if text == "" {
+				return nil
+			}
+			c := t.Checklists[m.editing.index]
+			return edited(func() error { return AddClickUpChecklistItem(clickup, c.ID, text) })
+		case "checklist":
+			if text == "" {
+				return nil
+			}
+			return edited(func() error { return CreateClickUpChecklist(clickup, t.ID, text) })
+		case "relationship":
+			verb, other, ok := strings.Cut(text, " ")
+			other = strings.TrimSpace(other)
+			if !ok || other == "" {
+				m.status = "Relationships look like: waiting abc123, blocking abc123 or link abc123"
+				return nil
+			}
+			switch verb {
+			case "waiting":
+				return edited(func() error { return AddClickUpDependency(clickup, t.ID, other, false) })
+			case "blocking":
+				return edited(func() error { return AddClickUpDependency(clickup, t.ID, other, true) })
+			case "link":
+				return edited(func() error { return LinkClickUpTasks(clickup, t.ID, other) })
+			}
+			m.status = "Relationships start with waiting, blocking or link."
+			return nil
+		}
+	}
+
+	switch m.editing.kind {
+	case taskRowName:
+		if text == "" {
+			m.status = "Tasks need a name."
+			return nil
+		}
+		return edited(func() error { return UpdateClickUpTask(clickup, t.ID, map[string]any{"name": text}) })
+
+	case taskRowAssignees:
+		var want []int
+		for _, name := range splitList(text) {
+			id, err := resolveClickUpMember(m.members, clickup, name)
+			if err != nil {
+				m.status = err.Error()
+				return nil
+			}
+			want = append(want, id)
+		}
+		add, rem := []int{}, []int{}
+		for _, id := range want {
+			if !slices.ContainsFunc(t.Assignees, func(u ClickUpUser) bool { return u.ID == id }) {
+				add = append(add, id)
+			}
+		}
+		for _, u := range t.Assignees {
+			if !slices.Contains(want, u.ID) {
+				rem = append(rem, u.ID)
+			}
+		}
+		changes := map[string]any{"assignees": map[string][]int{"add": add, "rem": rem}}
+		return edited(func() error { return UpdateClickUpTask(clickup, t.ID, changes) })
+
+	case taskRowStart, taskRowDue:
+		property := "start_date"
+		if m.editing.kind == taskRowDue {
+			property = "due_date"
+		}
+		changes, err := dateChange(property, text)
+		if err != nil {
+			m.status = err.Error()
+			return nil
+		}
+		return edited(func() error { return UpdateClickUpTask(clickup, t.ID, changes) })
+
+	case taskRowTags:
+		want := splitList(text)
+		return edited(func() error {
+			for _, tag := range t.Tags {
+				if !slices.Contains(want, tag.Name) {
+					if err := RemoveClickUpTag(clickup, t.ID, tag.Name); err != nil {
+						return err
+					}
+				}
+			}
+			for _, name := range want {
+				if !slices.ContainsFunc(t.Tags, func(tag ClickUpTag) bool { return tag.Name == name }) {
+					if err := AddClickUpTag(clickup, t.ID, name); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+
+	case taskRowField:
+		f := t.CustomFields[m.editing.index]
+		value, err := fieldValue(f, text)
+		if err != nil {
+			m.status = err.Error()
+			return nil
+		}
+		return edited(func() error { return SetClickUpField(clickup, t.ID, f.ID, value) })
+	}
+	return nil
+}
+
+// submitChoice applies the status, priority or drop-down option picked.
+func (m *ClickUpTaskDetail) submitChoice() tea.Cmd {
+	clickup, t, picked := m.clickup, m.task, m.choices[m.choice]
+
+	switch m.editing.kind {
+	case taskRowStatus:
+		return edited(func() error { return UpdateClickUpTask(clickup, t.ID, map[string]any{"status": picked}) })
+	case taskRowPriority:
+		var priority any
+		if p := slices.Index(clickUpPriorities, picked); p > 0 {
+			priority = p
+		}
+		return edited(func() error { return UpdateClickUpTask(clickup, t.ID, map[string]any{"priority": priority}) })
+	case taskRowField:
+		f := t.CustomFields[m.editing.index]
+		var value any
+		if m.choice > 0 {
+			value = f.TypeConfig.Options[m.choice-1].ID
+		}
+		return edited(func() error { return SetClickUpField(clickup, t.ID, f.ID, value) })
+	}
+	return nil
+}
+
+// remove deletes the checklist item or relationship under the cursor.
+func (m *ClickUpTaskDetail) remove() tea.Cmd {
+	clickup, t, row := m.clickup, m.task, m.rows[m.cursor]
+
+	switch row.kind {
+	case taskRowChecklistItem:
+		c := t.Checklists[row.index]
+		item := c.Items[row.item]
+		return edited(func() error { return DeleteClickUpChecklistItem(clickup, c.ID, item.ID) })
+	case taskRowDependency:
+		dep := t.Dependencies[row.index]
+		return edited(func() error { return RemoveClickUpDependency(clickup, dep) })
+	case taskRowLink:
+		other := t.LinkedTasks[row.index].LinkID
+		return edited(func() error { return UnlinkClickUpTasks(clickup, t.ID, other) })
+	}
+	return nil
+}
+
+func (m ClickUpTaskDetail) back() tea.Cmd {
+	if m.fromNavigator {
+		return func() tea.Msg { return ShowClickUpNavigatorMsg{} }
+	}
+	reload := m.changed
+	return func() tea.Msg { return ShowClickUpTasksMsg{reload: reload} }
+}
+
+func (m ClickUpTaskDetail) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpTaskDetailMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.task = msg.task
+		m.statuses = msg.statuses
+		m.members = msg.members
+		m.related = msg.related
+		m.description = msg.description
+		m.buildRows()
+		if m.status == "Loading..." {
+			m.status = ""
+		}
+		return m, nil
+
+	case ClickUpTaskEditedMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.changed = true
+		m.status = "Saved."
+		return m, m.load()
+
+	case ExternalEditorMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if msg.text == m.task.MarkdownDescription {
+			return m, nil
+		}
+		clickup, id, text := m.clickup, m.task.ID, msg.text
+		m.loading = true
+		m.status = "Saving..."
+		return m, edited(func() error {
+			return UpdateClickUpTask(clickup, id, map[string]any{"markdown_content": text})
+		})
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			if msg.String() == "esc" {
+				return m, m.back()
+			}
+			return m, nil
+		}
+
+		switch m.mode {
+		case taskInput:
+			switch msg.String() {
+			case "esc":
+				m.mode = taskBrowse
+				m.addingWhat = ""
+				m.input.Blur()
+				return m, nil
+			case "enter":
+				m.mode = taskBrowse
+				m.input.Blur()
+				cmd := m.submitInput()
+				if cmd != nil {
+					m.loading = true
+					m.status = "Saving..."
+				}
+				return m, cmd
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+
+		case taskChoose:
+			switch msg.String() {
+			case "esc":
+				m.mode = taskBrowse
+			case "up", "k", "left", "h":
+				if m.choice > 0 {
+					m.choice--
+				}
+			case "down", "j", "right", "l":
+				if m.choice < len(m.choices)-1 {
+					m.choice++
+				}
+			case "enter":
+				m.mode = taskBrowse
+				m.loading = true
+				m.status = "Saving..."
+				return m, m.submitChoice()
+			}
+			return m, nil
+		}
+
+		if len(m.rows) == 0 && msg.String() != "esc" && msg.String() != "q" && msg.String() != "r" {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			if len(m.parents) > 0 {
+				parent := m.parents[len(m.parents)-1]
+				m.parents = m.parents[:len(m.parents)-1]
+				return m, m.open(parent)
+			}
+			return m, m.back()
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.rows)-1 {
+				m.cursor++
+			}
+		case "r":
+			m.loading = true
+			m.status = "Loading..."
+			return m, m.load()
+		case "v":
+			m.expanded = !m.expanded
+		case "o":
+			if err := openBrowser(m.task.URL); err != nil {
+				m.status = fmt.Sprintf("Error: %v", err)
+			}
+		case "enter", "e", " ":
+			row := m.rows[m.cursor]
+			cmd := m.edit()
+			// Toggles write straight away; everything else opens an editor first.
+			if row.kind == taskRowChecklistItem || (row.kind == taskRowField && m.task.CustomFields[row.index].Type == "checkbox") {
+				m.loading = true
+				m.status = "Saving..."
+			}
+			return m, cmd
+		case "x":
+			if cmd := m.remove(); cmd != nil {
+				m.loading = true
+				m.status = "Removing..."
+				return m, cmd
+			}
+		case "C":
+			m.addingWhat = "checklist"
+			return m, m.startInput("New checklist name", "")
+		case "L":
+			m.addingWhat = "relationship"
+			return m, m.startInput("waiting TASK_ID, blocking TASK_ID or link TASK_ID", "")
+		case "c":
+			target := ClickUpCommentTarget{Kind: "task", ID: m.task.ID, Name: m.task.Name}
+			return m, func() tea.Msg { return ClickUpCommentsRequestMsg{target: target} }
+		case "A":
+			request := ClickUpAttachmentsRequestMsg{taskID: m.task.ID, taskName: m.task.Name}
+			return m, func() tea.Msg { return request }
+		case "t":
+			if m.task.ID == "" {
+				return m, nil
+			}
+			toggle := ClickUpTimerToggleMsg{clickup: m.clickup, taskID: m.task.ID}
+			return m, func() tea.Msg { return toggle }
+		}
+	}
+
+	return m, nil
+}
+
+// relatedName shows a related task by name when it could be looked up.
+func (m ClickUpTaskDetail) relatedName(id string) string {
+	if name, ok := m.related[id]; ok {
+		return fmt.Sprintf("%s (%s)", name, id)
+	}
+	return id
+}
+
+// rowText renders a selectable row.
+func (m ClickUpTaskDetail) rowText(row taskRow) string {
+	t := m.task
+	field := func(label string, value string) string {
+		if value == "" {
+			value = "—"
+		}
+		return detailLabelStyle.Render(label) + value
+	}
+
+	switch row.kind {
+	case taskRowName:
+		return field("Name", t.Name)
+	case taskRowStatus:
+		return field("Status", lipgloss.NewStyle().Foreground(lipgloss.Color(t.Status.Color)).Render(t.Status.Status))
+	case taskRowPriority:
+		if t.Priority == nil {
+			return field("Priority", "")
+		}
+		return field("Priority", lipgloss.NewStyle().Foreground(lipgloss.Color(t.Priority.Color)).Render(t.Priority.Priority))
+	case taskRowAssignees:
+		return field("Assignees", strings.Join(assigneeNames(t), ", "))
+	case taskRowStart:
+		return field("Start", formatDate(t.StartDate))
+	case taskRowDue:
+		return field("Due", formatDate(t.DueDate))
+	case taskRowTags:
+		var tags []string
+		for _, tag := range t.Tags {
+			tags = append(tags, tag.Name)
+		}
+		return field("Tags", strings.Join(tags, ", "))
+	case taskRowField:
+		f := t.CustomFields[row.index]
+		return field(f.Name, fieldText(f))
+	case taskRowDescription:
+		return detailLabelStyle.Render("Description")
+	case taskRowSubtask:
+		s := t.Subtasks[row.index]
+		return fmt.Sprintf("  %s [%s]", s.Name, s.Status.Status)
+	case taskRowChecklist:
+		c := t.Checklists[row.index]
+		done := 0
+		for _, item := range c.Items {
+			if item.Resolved {
+				done++
+			}
+		}
+		return fmt.Sprintf("  %s (%d/%d)", c.Name, done, len(c.Items))
+	case taskRowChecklistItem:
+		item := t.Checklists[row.index].Items[row.item]
+		box := "☐"
+		if item.Resolved {
+			box = "☑"
+		}
+		return fmt.Sprintf("    %s %s", box, item.Name)
+	case taskRowDependency:
+		d := t.Dependencies[row.index]
+		if d.TaskID == t.ID {
+			return "  waiting on " + m.relatedName(d.DependsOn)
+		}
+		return "  blocking " + m.relatedName(d.TaskID)
+	case taskRowLink:
+		l := t.LinkedTasks[row.index]
+		other := l.LinkID
+		if other == t.ID {
+			other = l.TaskID
+		}
+		return "  linked to " + m.relatedName(other)
+	}
+	return ""
+}
+
+// body renders the whole task and the line the cursor is on.
+func (m ClickUpTaskDetail) body() ([]string, int) {
+	var lines []string
+	cursorLine := 0
+
+	headings := map[taskRowKind]string{
+		taskRowSubtask:    "Subtasks",
+		taskRowChecklist:  "Checklists",
+		taskRowDependency: "Relationships",
+		taskRowLink:       "Relationships",
+	}
+	heading := ""
+
+	for i, row := range m.rows {
+		if h, ok := headings[row.kind]; ok && h != heading {
+			lines = append(lines, "", detailLabelStyle.Render(h))
+			heading = h
+		}
+
+		marker := "  "
+		if i == m.cursor {
+			marker = focusedStyle.Render("> ")
+			cursorLine = len(lines)
+		}
+		lines = append(lines, marker+m.rowText(row))
+
+		if row.kind == taskRowDescription {
+			desc := strings.Split(m.description, "\n")
+			if m.description == "" {
+				desc = []string{"  (none)"}
+			} else if !m.expanded && len(desc) > descriptionPreviewLines {
+				desc = append(desc[:descriptionPreviewLines], fmt.Sprintf("  … %d more lines (v: expand)", len(desc)-descriptionPreviewLines))
+			}
+			lines = append(lines, desc...)
+		}
+	}
+	return lines, cursorLine
+}
+
+// taskDraft makes a quick task a follow-up of the one being shown.
+func (m ClickUpTaskDetail) taskDraft() ClickUpTaskDraft {
+	if m.task.ID == "" {
+		return ClickUpTaskDraft{}
+	}
+	return ClickUpTaskDraft{
+		Description: fmt.Sprintf("Follow-up of [%s](%s)", m.task.Name, m.task.URL),
+		ListID:      m.task.List.ID,
+	}
+}
+
+func (m ClickUpTaskDetail) View() string {
+	var b strings.Builder
+
+	crumb := m.task.List.Name
+	if m.task.Name != "" {
+		crumb += " › " + m.task.Name
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", breadcrumbStyle.Render(crumb))
+
+	if m.task.ID != "" {
+		lines, cursorLine := m.body()
+		// Scroll so the cursor stays in view.
+		start := max(0, min(cursorLine-taskDetailHeight/2, len(lines)-taskDetailHeight))
+		end := min(len(lines), start+taskDetailHeight)
+		b.WriteString(strings.Join(lines[start:end], "\n"))
+		b.WriteString("\n\n")
+	}
+
+	switch m.mode {
+	case taskInput:
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: save • esc: cancel"))
+	case taskChoose:
+		for i, c := range m.choices {
+			cursor := " "
+			if i == m.choice {
+				cursor = ">"
+			}
+			fmt.Fprintf(&b, "%s %s\n", cursor, c)
+		}
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("enter: choose • esc: cancel"))
+	default:
+		b.WriteString(helpStyle.Render("enter: edit / open / toggle • x: remove • C: new checklist • L: add relationship"))
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render("c: comments • A: attachments • t: start/stop timer • v: expand description • o: open in browser • r: reload • esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,489 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	clickUpTaskGroupings = []string{"none", "status", "assignee"}
+	clickUpTaskSorts     = []string{"due_date", "created", "updated"}
+	clickUpTaskSortNames = []string{"due date", "created", "updated"}
+)
+
+type ClickUpTasks struct {
+	// listID scopes the tasks to one list; empty means the whole workspace.
+	listID        string
+	listName      string
+	fromNavigator bool
+	members       []ClickUpUser
+	fields        []ClickUpCustomField
+	editing       bool
+	focusIndex    int
+	inputs        []textinput.Model
+	filter        ClickUpTaskFilter
+	tasks         []ClickUpTask
+	lastPage      bool
+	// rowTasks maps table rows to tasks; group headings map to -1.
+	rowTasks     []int
+	group        int
+	sort         int
+	table        table.Model
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+// ClickUpTasksRequestMsg opens the task list for a list from the navigator.
+type ClickUpTasksRequestMsg struct {
+	listID   string
+	listName string
+}
+
+// ShowClickUpNavigatorMsg returns to the hierarchy navigator where it was left.
+type ShowClickUpNavigatorMsg struct{}
+
+type ClickUpTaskContextMsg struct {
+	members []ClickUpUser
+	fields  []ClickUpCustomField
+	err     error
+}
+
+type ClickUpTasksMsg struct {
+	tasks []ClickUpTask
+	last  bool
+	page  int
+	err   error
+}
+
+func InitialClickUpTasks(token string, refreshToken string, user User, clickup ClickUpProfile, listID string, listName string, fromNavigator bool) ClickUpTasks {
+	m := ClickUpTasks{
+		listID:        listID,
+		listName:      listName,
+		fromNavigator: fromNavigator,
+		inputs:        make([]textinput.Model, 6),
+		filter:        ClickUpTaskFilter{ListID: listID, OrderBy: clickUpTaskSorts[0]},
+		table:         table.New(table.WithHeight(15), table.WithFocused(true)),
+		loading:       true,
+		status:        "Loading...",
+		token:         token,
+		refreshToken:  refreshToken,
+		user:          user,
+		clickup:       clickup,
+	}
+
+	m.inputs[0] = newFormInput("Assignees, e.g. me, alex", 256, 50)
+	m.inputs[1] = newFormInput("Statuses, e.g. to do, in progress", 256, 50)
+	m.inputs[2] = newFormInput("Priorities: urgent, high, normal, low", 64, 50)
+	m.inputs[3] = newFormInput("Due: today, this week, next week, overdue, before/after 2024-05-31", 64, 70)
+	m.inputs[4] = newFormInput("Tags, e.g. bug, backend", 256, 50)
+	m.inputs[5] = newFormInput("Custom fields, e.g. Estimate > 3, Sprint is set", 256, 50)
+
+	columns := []table.Column{
+		{Title: "Task", Width: 40},
+		{Title: "Status", Width: 14},
+		{Title: "Priority", Width: 8},
+		{Title: "Assignees", Width: 20},
+		{Title: "Due", Width: 12},
+	}
+	if listID == "" {
+		columns = append(columns, table.Column{Title: "List", Width: 20})
+	}
+	m.table.SetColumns(columns)
+
+	return m
+}
+
+func (m ClickUpTasks) Init() tea.Cmd {
+	clickup, listID := m.clickup, m.listID
+	return tea.Batch(tea.SetWindowTitle("ClickUp Tasks"), func() tea.Msg {
+		members, err := ClickUpMembers(clickup)
+		if err != nil {
+			return ClickUpTaskContextMsg{err: err}
+		}
+		fields, err := ListClickUpFields(clickup, listID)
+		return ClickUpTaskContextMsg{members: members, fields: fields, err: err}
+	})
+}
+
+func (m ClickUpTasks) fetch(page int) tea.Cmd {
+	clickup, filter := m.clickup, m.filter
+	filter.Page = page
+	return func() tea.Msg {
+		tasks, last, err := ListClickUpTasks(clickup, filter)
+		return ClickUpTasksMsg{tasks: tasks, last: last, page: page, err: err}
+	}
+}
+
+// buildFilter reads the filter form, keeping paging and sort settings.
+func (m ClickUpTasks) buildFilter() (ClickUpTaskFilter, error) {
+	f := ClickUpTaskFilter{
+		ListID:        m.listID,
+		IncludeClosed: m.filter.IncludeClosed,
+		OrderBy:       m.filter.OrderBy,
+		Reverse:       m.filter.Reverse,
+	}
+
+	for _, name := range splitList(m.inputs[0].Value()) {
+		id, err := resolveClickUpMember(m.members, m.clickup, name)
+		if err != nil {
+			return f, err
+		}
+		f.Assignees = append(f.Assignees, id)
+	}
+	for _, s := range splitList(m.inputs[1].Value()) {
+		f.Statuses = append(f.Statuses, strings.ToLower(s))
+	}
+	for _, name := range splitList(m.inputs[2].Value()) {
+		p := slices.Index(clickUpPriorities, strings.ToLower(name))
+		if p <= 0 {
+			return f, fmt.Errorf("priorities are urgent, high, normal or low")
+		}
+		f.Priorities = append(f.Priorities, p)
+	}
+	var err error
+	if f.DueAfter, f.DueBefore, err = parseDueFilter(m.inputs[3].Value(), time.Now()); err != nil {
+		return f, err
+	}
+	f.Tags = splitList(m.inputs[4].Value())
+	for _, clause := range splitList(m.inputs[5].Value()) {
+		ff, err := parseFieldFilter(clause, m.fields)
+		if err != nil {
+			return f, err
+		}
+		f.CustomFields = append(f.CustomFields, ff)
+	}
+	return f, nil
+}
+
+// apply runs the filter form from the first page.
+func (m *ClickUpTasks) apply() tea.Cmd {
+	f, err := m.buildFilter()
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	m.filter = f
+	m.editing = false
+	focusInput(m.inputs, len(m.inputs))
+	m.loading = true
+	m.status = "Loading..."
+	return m.fetch(0)
+}
+
+func assigneeNames(t ClickUpTask) []string {
+	names := make([]string, len(t.Assignees))
+	for i, a := range t.Assignees {
+		names[i] = a.Username
+	}
+	return names
+}
+
+func (m ClickUpTasks) taskRow(t ClickUpTask) table.Row {
+	name := t.Name
+	if t.Parent != "" {
+		name = "↳ " + name
+	}
+	priority := ""
+	if t.Priority != nil {
+		priority = t.Priority.Priority
+	}
+	due := ""
+	if !t.DueDate.IsZero() {
+		due = t.DueDate.Local().Format("Jan 02")
+		if t.DueDate.Before(time.Now()) && t.Status.Type != "closed" {
+			due += " !"
+		}
+	}
+	row := table.Row{name, t.Status.Status, priority, strings.Join(assigneeNames(t), ", "), due}
+	if m.listID == "" {
+		row = append(row, t.List.Name)
+	}
+	return row
+}
+
+// groupKeys returns the groups a task falls under for the current grouping.
+func (m ClickUpTasks) groupKeys(t ClickUpTask) []string {
+	switch clickUpTaskGroupings[m.group] {
+	case "status":
+		return []string{t.Status.Status}
+	case "assignee":
+		if names := assigneeNames(t); len(names) > 0 {
+			return names
+		}
+		return []string{"Unassigned"}
+	}
+	return nil
+}
+
+// showTasks fills the table, with a heading row before each group. Groups
+// keep the order in which they first appear on the page.
+func (m *ClickUpTasks) showTasks() {
+	var rows []table.Row
+	m.rowTasks = nil
+
+	if m.group == 0 {
+		for i, t := range m.tasks {
+			rows = append(rows, m.taskRow(t))
+			m.rowTasks = append(m.rowTasks, i)
+		}
+	} else {
+		var order []string
+		groups := make(map[string][]int)
+		for i, t := range m.tasks {
+			for _, k := range m.groupKeys(t) {
+				if _, ok := groups[k]; !ok {
+					order = append(order, k)
+				}
+				groups[k] = append(groups[k], i)
+			}
+		}
+		width := len(m.table.Columns())
+		for _, k := range order {
+			heading := make(table.Row, width)
+			heading[0] = fmt.Sprintf("▾ %s (%d)", strings.ToUpper(k), len(groups[k]))
+			rows = append(rows, heading)
+			m.rowTasks = append(m.rowTasks, -1)
+			for _, i := range groups[k] {
+				rows = append(rows, m.taskRow(m.tasks[i]))
+				m.rowTasks = append(m.rowTasks, i)
+			}
+		}
+	}
+
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+}
+
+// selected returns the task under the cursor, if the cursor isn't on a heading.
+func (m ClickUpTasks) selected() (ClickUpTask, bool) {
+	c := m.table.Cursor()
+	if c < 0 || c >= len(m.rowTasks) || m.rowTasks[c] < 0 {
+		return ClickUpTask{}, false
+	}
+	return m.tasks[m.rowTasks[c]], true
+}
+
+// reload fetches the current page again, e.g. after a task was edited.
+func (m *ClickUpTasks) reload() tea.Cmd {
+	m.loading = true
+	m.status = "Reloading..."
+	return m.fetch(m.filter.Page)
+}
+
+func (m ClickUpTasks) back() tea.Cmd {
+	if m.fromNavigator {
+		return func() tea.Msg { return ShowClickUpNavigatorMsg{} }
+	}
+	return func() tea.Msg { return ShowClickUpMenuMsg{} }
+}
+
+func (m ClickUpTasks) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpTaskContextMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.members = msg.members
+		m.fields = msg.fields
+		return m, m.fetch(0)
+
+	case ClickUpTasksMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.tasks = msg.tasks
+		m.lastPage = msg.last
+		m.filter.Page = msg.page
+		m.status = ""
+		m.showTasks()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			if msg.String() == "esc" {
+				return m, m.back()
+			}
+			return m, nil
+		}
+
+		if m.editing {
+			switch msg.String() {
+			case "esc":
+				m.editing = false
+				m.status = ""
+				focusInput(m.inputs, len(m.inputs))
+				return m, nil
+			case "tab", "shift+tab", "enter", "up", "down":
+				s := msg.String()
+				if s == "enter" && m.focusIndex == len(m.inputs) {
+					return m, m.apply()
+				}
+				m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+				return m, focusInput(m.inputs, m.focusIndex)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := range m.inputs {
+				m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, m.back()
+		case "f":
+			m.editing = true
+			m.focusIndex = 0
+			m.status = ""
+			return m, focusInput(m.inputs, m.focusIndex)
+		case "w":
+			// My open tasks due this week.
+			for i := range m.inputs {
+				m.inputs[i].SetValue("")
+			}
+			m.inputs[0].SetValue("me")
+			m.inputs[3].SetValue("this week")
+			m.filter.IncludeClosed = false
+			return m, m.apply()
+		case "x":
+			for i := range m.inputs {
+				m.inputs[i].SetValue("")
+			}
+			return m, m.apply()
+		case "g":
+			m.group = (m.group + 1) % len(clickUpTaskGroupings)
+			m.showTasks()
+			return m, nil
+		case "s", "R", "c":
+			switch msg.String() {
+			case "s":
+				m.sort = (m.sort + 1) % len(clickUpTaskSorts)
+				m.filter.OrderBy = clickUpTaskSorts[m.sort]
+			case "R":
+				m.filter.Reverse = !m.filter.Reverse
+			case "c":
+				m.filter.IncludeClosed = !m.filter.IncludeClosed
+			}
+			m.loading = true
+			m.status = "Loading..."
+			return m, m.fetch(0)
+		case "n":
+			if m.lastPage {
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Loading..."
+			return m, m.fetch(m.filter.Page + 1)
+		case "p":
+			if m.filter.Page == 0 {
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Loading..."
+			return m, m.fetch(m.filter.Page - 1)
+		case "enter":
+			if t, ok := m.selected(); ok {
+				return m, func() tea.Msg { return ClickUpTaskRequestMsg{taskID: t.ID} }
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// filterSummary describes the active filters in one line.
+func (m ClickUpTasks) filterSummary() string {
+	labels := []string{"assignee", "status", "priority", "due", "tags", "fields"}
+	var parts []string
+	for i, in := range m.inputs {
+		if v := strings.TrimSpace(in.Value()); v != "" {
+			parts = append(parts, labels[i]+": "+v)
+		}
+	}
+	if m.filter.IncludeClosed {
+		parts = append(parts, "including closed")
+	}
+	if len(parts) == 0 {
+		return "No filters"
+	}
+	return strings.Join(parts, " • ")
+}
+
+// taskDraft files a quick task in the list being shown.
+func (m ClickUpTasks) taskDraft() ClickUpTaskDraft {
+	if m.listID != "" {
+		return ClickUpTaskDraft{ListID: m.listID}
+	}
+	if t, ok := m.selected(); ok {
+		return ClickUpTaskDraft{ListID: t.List.ID}
+	}
+	return ClickUpTaskDraft{}
+}
+
+func (m ClickUpTasks) View() string {
+	var b strings.Builder
+
+	scope := m.clickup.WorkspaceName
+	if m.listID != "" {
+		scope = m.listName
+	}
+	fmt.Fprintf(&b, "\nTasks in %s\n\n", breadcrumbStyle.Render(scope))
+
+	if m.editing {
+		for i := range m.inputs {
+			b.WriteString(m.inputs[i].View())
+			b.WriteRune('\n')
+		}
+		button := &blurredButton
+		if m.focusIndex == len(m.inputs) {
+			button = &focusedButton
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", *button)
+		b.WriteString(helpStyle.Render("Comma-separate several values • esc: cancel"))
+	} else {
+		sorting := clickUpTaskSortNames[m.sort]
+		if m.filter.Reverse {
+			sorting += " (reversed)"
+		}
+		fmt.Fprintf(&b, "%s\nSorted by %s • grouped by %s • page %d\n\n",
+			m.filterSummary(), sorting, clickUpTaskGroupings[m.group], m.filter.Page+1)
+		b.WriteString(m.table.View())
+		b.WriteString("\n\n")
+		if !m.loading && len(m.tasks) == 0 {
+			b.WriteString("No tasks match.\n\n")
+		}
+		b.WriteString(helpStyle.Render("w: my tasks due this week • f: filters • x: clear filters • c: toggle closed"))
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render("enter: open task • g: group • s: sort • R: reverse • n: next page • p: previous page • esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,687 @@
+package models
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var timerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+
+type timeMode int
+
+const (
+	timeBrowse timeMode = iota
+	// timeForm adds or edits an entry by hand.
+	timeForm
+	// timeStart asks which task to start a timer on.
+	timeStart
+	timeExport
+)
+
+// Fields of the manual entry form.
+const (
+	timeTask = iota
+	timeDate
+	timeStartAt
+	timeDuration
+	timeDescription
+	timeBillable
+)
+
+// ClickUpTimeTracking lists the user's time entries for a week, either one by
+// one or as a timesheet by task and day, and edits them.
+type ClickUpTimeTracking struct {
+	mode       timeMode
+	week       time.Time
+	entries    []ClickUpTimeEntry
+	timesheet  bool
+	list       table.Model
+	sheet      table.Model
+	focusIndex int
+	inputs     []textinput.Model
+	// editing is the entry the form changes; empty adds a new one.
+	editing    string
+	input      textinput.Model
+	confirming bool
+	loading    bool
+	status     string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpTimeEntriesMsg struct {
+	entries []ClickUpTimeEntry
+	err     error
+}
+
+type ClickUpTimeChangedMsg struct {
+	done string
+	err  error
+}
+
+// ClickUpTimerToggleMsg starts a timer on a task, or stops the running one
+// when it is on that task or no task is given. It can come from any screen.
+type ClickUpTimerToggleMsg struct {
+	clickup     ClickUpProfile
+	taskID      string
+	description string
+	billable    bool
+}
+
+// ClickUpTimerMsg reports the running timer, nil when none runs, after it was
+// checked, started or stopped.
+type ClickUpTimerMsg struct {
+	clickup ClickUpProfile
+	timer   *ClickUpTimeEntry
+	err     error
+}
+
+// ClickUpTimerTickMsg redraws the running timer in the status bar each second.
+type ClickUpTimerTickMsg struct {
+	gen int
+}
+
+func InitialClickUpTimeTracking(token string, refreshToken string, user User, clickup ClickUpProfile) ClickUpTimeTracking {
+	inputs := []textinput.Model{
+		newFormInput("Task ID", 32, 30),
+		newFormInput("Date, e.g. today or 2024-05-30", 32, 40),
+		newFormInput("Start time, e.g. 09:30", 5, 20),
+		newFormInput("Duration: 1h30m, 90m, 1:30 or 1.5", 16, 30),
+		newFormInput("Description", 200, 60),
+		newFormInput("Billable? (y/n)", 3, 20),
+	}
+
+	return ClickUpTimeTracking{
+		week: startOfWeek(time.Now()),
+		list: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Day", Width: 10},
+				{Title: "Start", Width: 6},
+				{Title: "Time", Width: 8},
+				{Title: "Task", Width: 35},
+				{Title: "Description", Width: 35},
+				{Title: "$", Width: 2},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		sheet:        table.New(table.WithHeight(15)),
+		inputs:       inputs,
+		input:        newFormInput("", 200, 60),
+		loading:      true,
+		status:       "Loading time entries...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+}
+
+func (m ClickUpTimeTracking) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp Time Tracking"), m.load())
+}
+
+func (m ClickUpTimeTracking) load() tea.Cmd {
+	clickup, week := m.clickup, m.week
+	return func() tea.Msg {
+		entries, err := ListClickUpTimeEntries(clickup, week, week.AddDate(0, 0, 7))
+		return ClickUpTimeEntriesMsg{entries: entries, err: err}
+	}
+}
+
+func (m *ClickUpTimeTracking) reload(status string) tea.Cmd {
+	m.loading = true
+	m.status = status
+	return m.load()
+}
+
+// checkClickUpTimer looks up the running timer for the status bar. A failed
+// check leaves the status bar as it was.
+func checkClickUpTimer(clickup ClickUpProfile) tea.Cmd {
+	if clickup.WorkspaceID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		timer, err := GetClickUpTimer(clickup)
+		if err != nil {
+			return nil
+		}
+		return ClickUpTimerMsg{clickup: clickup, timer: timer}
+	}
+}
+
+// toggleClickUpTimer carries out a ClickUpTimerToggleMsg given what is running.
+func toggleClickUpTimer(msg ClickUpTimerToggleMsg, running *ClickUpTimeEntry) tea.Cmd {
+	clickup := msg.clickup
+	if running != nil && (msg.taskID == "" || msg.taskID == running.taskID()) {
+		return func() tea.Msg {
+			if err := StopClickUpTimer(clickup); err != nil {
+				return ClickUpTimerMsg{clickup: clickup, timer: running, err: err}
+			}
+			return ClickUpTimerMsg{clickup: clickup}
+		}
+	}
+	if msg.taskID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		timer, err := StartClickUpTimer(clickup, msg.taskID, msg.description, msg.billable)
+		if err != nil {
+			return ClickUpTimerMsg{clickup: clickup, timer: running, err: err}
+		}
+		return ClickUpTimerMsg{clickup: clickup, timer: timer}
+	}
+}
+
+func tickClickUpTimer(gen int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return ClickUpTimerTickMsg{gen: gen} })
+}
+
+// days are the seven days of the week shown.
+func (m ClickUpTimeTracking) days() []time.Time {
+	days := make([]time.Time, 7)
+	for i := range days {
+		days[i] = m.week.AddDate(0, 0, i)
+	}
+	return days
+}
+
+// timesheetRow is one task's time on each day of the week.
+type timesheetRow struct {
+	taskID   string
+	task     string
+	days     [7]time.Duration
+	total    time.Duration
+	billable time.Duration
+}
+
+// timesheetRows adds the week's entries up by task and day, in task name order.
+func (m ClickUpTimeTracking) timesheetRows() []timesheetRow {
+	now := time.Now()
+	rows := make(map[string]*timesheetRow)
+	for _, e := range m.entries {
+		r, ok := rows[e.taskID()]
+		if !ok {
+			r = &timesheetRow{taskID: e.taskID(), task: e.taskName()}
+			rows[e.taskID()] = r
+		}
+		// Count calendar days so a daylight saving change doesn't shift entries.
+		y, mo, dd := e.Start.Local().Date()
+		day := int(math.Round(time.Date(y, mo, dd, 0, 0, 0, 0, time.Local).Sub(m.week).Hours() / 24))
+		if day < 0 || day > 6 {
+			continue
+		}
+		d := e.elapsed(now)
+		r.days[day] += d
+		r.total += d
+		if e.Billable {
+			r.billable += d
+		}
+	}
+
+	sheet := make([]timesheetRow, 0, len(rows))
+	for _, r := range rows {
+		sheet = append(sheet, *r)
+	}
+	slices.SortFunc(sheet, func(a, b timesheetRow) int { return strings.Compare(a.task, b.task) })
+	return sheet
+}
+
+func (m *ClickUpTimeTracking) showEntries() {
+	now := time.Now()
+	slices.SortFunc(m.entries, func(a, b ClickUpTimeEntry) int { return a.Start.Compare(b.Start.Time) })
+
+	rows := make([]table.Row, len(m.entries))
+	for i, e := range m.entries {
+		spent := formatHours(e.elapsed(now))
+		if e.running() {
+			spent = "▶ " + spent
+		}
+		billable := ""
+		if e.Billable {
+			billable = "$"
+		}
+		start := e.Start.Local()
+		rows[i] = table.Row{start.Format("Mon 01/02"), start.Format("15:04"), spent, e.taskName(), e.Description, billable}
+	}
+	m.list.SetRows(rows)
+	m.list.SetCursor(min(m.list.Cursor(), max(len(rows)-1, 0)))
+
+	columns := []table.Column{{Title: "Task", Width: 30}}
+	for _, d := range m.days() {
+		columns = append(columns, table.Column{Title: d.Format("Mon 02"), Width: 7})
+	}
+	columns = append(columns, table.Column{Title: "Total", Width: 7}, table.Column{Title: "Billable", Width: 8})
+
+	var totals timesheetRow
+	var sheetRows []table.Row
+	for _, r := range m.timesheetRows() {
+		row := table.Row{r.task}
+		for i, d := range r.days {
+			row = append(row, hoursOrBlank(d))
+			totals.days[i] += d
+		}
+		totals.total += r.total
+		totals.billable += r.billable
+		sheetRows = append(sheetRows, append(row, formatHours(r.total), hoursOrBlank(r.billable)))
+	}
+	row := table.Row{"Total"}
+	for _, d := range totals.days {
+		row = append(row, hoursOrBlank(d))
+	}
+	sheetRows = append(sheetRows, append(row, formatHours(totals.total), hoursOrBlank(totals.billable)))
+
+	// Clear rows before changing columns so no row is wider than the columns.
+	m.sheet.SetRows(nil)
+	m.sheet.SetColumns(columns)
+	m.sheet.SetRows(sheetRows)
+}
+
+func hoursOrBlank(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return formatHours(d)
+}
+
+// exportTimesheet writes the week's timesheet as CSV with decimal hours.
+func (m ClickUpTimeTracking) exportTimesheet(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	header := []string{"Task ID", "Task"}
+	for _, d := range m.days() {
+		header = append(header, d.Format("Mon 2006-01-02"))
+	}
+	w.Write(append(header, "Total hours", "Billable hours"))
+
+	hours := func(d time.Duration) string { return fmt.Sprintf("%.2f", d.Hours()) }
+	for _, r := range m.timesheetRows() {
+		record := []string{r.taskID, r.task}
+		for _, d := range r.days {
+			record = append(record, hours(d))
+		}
+		w.Write(append(record, hours(r.total), hours(r.billable)))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func (m ClickUpTimeTracking) selected() (ClickUpTimeEntry, bool) {
+	i := m.list.Cursor()
+	if m.timesheet || i < 0 || i >= len(m.entries) {
+		return ClickUpTimeEntry{}, false
+	}
+	return m.entries[i], true
+}
+
+// openForm shows the manual entry form, filled from e when editing it.
+func (m *ClickUpTimeTracking) openForm(e *ClickUpTimeEntry) tea.Cmd {
+	m.mode = timeForm
+	m.editing = ""
+	now := time.Now()
+	values := []string{"", now.Format("2006-01-02"), now.Add(-time.Hour).Format("15:04"), "1h", "", "n"}
+	if e != nil {
+		m.editing = e.ID
+		billable := "n"
+		if e.Billable {
+			billable = "y"
+		}
+		start := e.Start.Local()
+		values = []string{e.taskID(), start.Format("2006-01-02"), start.Format("15:04"), formatHours(time.Duration(e.Duration)), e.Description, billable}
+	}
+	for i, v := range values {
+		m.inputs[i].SetValue(v)
+	}
+	m.status = ""
+	m.focusIndex = timeTask
+	return focusInput(m.inputs, m.focusIndex)
+}
+
+func (m *ClickUpTimeTracking) submitForm() tea.Cmd {
+	value := func(i int) string { return strings.TrimSpace(m.inputs[i].Value()) }
+
+	day, err := parseNaturalDate(value(timeDate), time.Now())
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	clock, err := time.Parse("15:04", value(timeStartAt))
+	if err != nil {
+		m.status = "Start times look like 09:30."
+		return nil
+	}
+	duration, err := parseWorkDuration(value(timeDuration))
+	if err != nil || duration <= 0 {
+		m.status = "Durations look like 1h30m, 90m, 1:30 or 1.5"
+		return nil
+	}
+	in := ClickUpTimeEntryInput{
+		TaskID:      value(timeTask),
+		Start:       time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local),
+		Duration:    duration,
+		Description: value(timeDescription),
+		Billable:    strings.HasPrefix(strings.ToLower(value(timeBillable)), "y"),
+	}
+
+	clickup, id := m.clickup, m.editing
+	m.mode = timeBrowse
+	m.loading = true
+	if id == "" {
+		m.status = "Adding entry..."
+		return timeChanged("Entry added.", func() error { return CreateClickUpTimeEntry(clickup, in) })
+	}
+	m.status = "Saving entry..."
+	return timeChanged("Entry saved.", func() error { return UpdateClickUpTimeEntry(clickup, id, in) })
+}
+
+func timeChanged(done string, f func() error) tea.Cmd {
+	return func() tea.Msg { return ClickUpTimeChangedMsg{done: done, err: f()} }
+}
+
+func (m ClickUpTimeTracking) running() (ClickUpTimeEntry, bool) {
+	i := slices.IndexFunc(m.entries, ClickUpTimeEntry.running)
+	if i < 0 {
+		return ClickUpTimeEntry{}, false
+	}
+	return m.entries[i], true
+}
+
+func (m ClickUpTimeTracking) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpTimeEntriesMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.entries = msg.entries
+		m.showEntries()
+		if m.status == "Loading time entries..." {
+			m.status = ""
+		}
+		return m, nil
+
+	case ClickUpTimeChangedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		cmd := m.reload(msg.done)
+		return m, cmd
+
+	case ClickUpTimerMsg:
+		// Started or stopped, the timer changes this week's entries.
+		if msg.err != nil {
+			m.loading = false
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		status := "Timer stopped."
+		if msg.timer != nil {
+			status = "Timer started on " + msg.timer.taskName() + "."
+		}
+		cmd := m.reload(status)
+		return m, cmd
+
+	case ClickUpTimerTickMsg:
+		if _, ok := m.running(); ok && m.mode == timeBrowse {
+			m.showEntries()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case timeForm:
+			switch msg.String() {
+			case "esc":
+				m.mode = timeBrowse
+				m.status = ""
+				return m, nil
+			case "tab", "shift+tab", "enter", "up", "down":
+				s := msg.String()
+				if s == "enter" && m.focusIndex == len(m.inputs) {
+					return m, m.submitForm()
+				}
+				m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+				return m, focusInput(m.inputs, m.focusIndex)
+			}
+			if m.focusIndex == len(m.inputs) {
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+			return m, cmd
+
+		case timeStart, timeExport:
+			switch msg.String() {
+			case "esc":
+				m.mode = timeBrowse
+				m.input.Blur()
+				m.status = ""
+				return m, nil
+			case "enter":
+				return m, m.submitInput()
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		if m.confirming {
+			m.confirming = false
+			if msg.String() != "y" && msg.String() != "Y" {
+				m.status = "Cancelled."
+				return m, nil
+			}
+			e, _ := m.selected()
+			clickup := m.clickup
+			m.loading = true
+			m.status = "Deleting entry..."
+			return m, timeChanged("Entry deleted.", func() error { return DeleteClickUpTimeEntry(clickup, e.ID) })
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "left", "h", "right", "l":
+			if msg.String() == "left" || msg.String() == "h" {
+				m.week = m.week.AddDate(0, 0, -7)
+			} else {
+				m.week = m.week.AddDate(0, 0, 7)
+			}
+			m.list.SetCursor(0)
+			cmd := m.reload("Loading time entries...")
+			return m, cmd
+		case "t":
+			m.timesheet = !m.timesheet
+			if m.timesheet {
+				m.list.Blur()
+				m.sheet.Focus()
+			} else {
+				m.sheet.Blur()
+				m.list.Focus()
+			}
+			return m, nil
+		case "r":
+			cmd := m.reload("Refreshing...")
+			return m, cmd
+		case "s":
+			if running, ok := m.running(); ok {
+				clickup := m.clickup
+				m.loading = true
+				m.status = "Stopping timer on " + running.taskName() + "..."
+				return m, func() tea.Msg { return ClickUpTimerToggleMsg{clickup: clickup} }
+			}
+			m.mode = timeStart
+			m.input.Placeholder = "TASK_ID and an optional description, e.g. 86b1x2 review PR"
+			m.input.Reset()
+			if e, ok := m.selected(); ok && e.taskID() != "" {
+				m.input.SetValue(strings.TrimSpace(e.taskID() + " " + e.Description))
+			}
+			m.status = ""
+			return m, m.input.Focus()
+		case "a":
+			return m, m.openForm(nil)
+		case "E":
+			m.mode = timeExport
+			m.input.Placeholder = "CSV file to write"
+			m.input.SetValue(fmt.Sprintf("timesheet-%s.csv", m.week.Format("2006-01-02")))
+			m.status = ""
+			return m, m.input.Focus()
+		case "e", "enter":
+			e, ok := m.selected()
+			if !ok {
+				return m, nil
+			}
+			if e.running() {
+				m.status = "Stop the timer before editing its entry."
+				return m, nil
+			}
+			return m, m.openForm(&e)
+		case "x":
+			e, ok := m.selected()
+			if !ok {
+				return m, nil
+			}
+			m.confirming = true
+			m.status = fmt.Sprintf("Delete %s on %s? (y/n)", formatHours(e.elapsed(time.Now())), e.taskName())
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.timesheet {
+		m.sheet, cmd = m.sheet.Update(msg)
+	} else {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *ClickUpTimeTracking) submitInput() tea.Cmd {
+	text := strings.TrimSpace(m.input.Value())
+	mode := m.mode
+	m.mode = timeBrowse
+	m.input.Blur()
+	if text == "" {
+		return nil
+	}
+
+	if mode == timeExport {
+		if err := m.exportTimesheet(text); err != nil {
+			m.status = fmt.Sprintf("Error: %v", err)
+			return nil
+		}
+		m.status = "Timesheet written to " + text
+		return nil
+	}
+
+	taskID, description, _ := strings.Cut(text, " ")
+	billable := false
+	if e, ok := m.selected(); ok && e.taskID() == taskID {
+		billable = e.Billable
+	}
+	clickup := m.clickup
+	m.loading = true
+	m.status = "Starting timer..."
+	return func() tea.Msg {
+		return ClickUpTimerToggleMsg{clickup: clickup, taskID: taskID, description: strings.TrimSpace(description), billable: billable}
+	}
+}
+
+func (m ClickUpTimeTracking) View() string {
+	var b strings.Builder
+
+	end := m.week.AddDate(0, 0, 6)
+	fmt.Fprintf(&b, "\nTime Tracking • week of %s – %s\n\n", m.week.Format("Mon Jan 2"), end.Format("Mon Jan 2, 2006"))
+
+	switch m.mode {
+	case timeForm:
+		title := "New time entry"
+		if m.editing != "" {
+			title = "Edit time entry"
+		}
+		b.WriteString(title + "\n\n")
+		for i := range m.inputs {
+			b.WriteString(m.inputs[i].View() + "\n")
+		}
+		button := &blurredButton
+		if m.focusIndex == len(m.inputs) {
+			button = &focusedButton
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", *button)
+		b.WriteString(helpStyle.Render("tab: next field • esc: cancel"))
+
+	default:
+		if m.timesheet {
+			b.WriteString(m.sheet.View())
+		} else {
+			b.WriteString(m.list.View())
+		}
+		b.WriteString("\n\n")
+		switch m.mode {
+		case timeStart, timeExport:
+			b.WriteString(m.input.View() + "\n\n")
+			b.WriteString(helpStyle.Render("enter: ok • esc: cancel"))
+		default:
+			if !m.loading && len(m.entries) == 0 {
+				b.WriteString("No time tracked this week.\n\n")
+			}
+			b.WriteString(helpStyle.Render("s: start/stop timer • a: add entry • e: edit • x: delete • t: timesheet/entries"))
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("←/→: week • E: export CSV • r: refresh • esc: back"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// timerStatus is the status bar's view of the running timer.
+func timerStatus(timer *ClickUpTimeEntry, err error) string {
+	if err != nil {
+		return statusWarningStyle.Render("⏱ timer: " + err.Error())
+	}
+	if timer == nil {
+		return ""
+	}
+	return timerStyle.Render("⏱ "+formatClock(timer.elapsed(time.Now()))) + " " + statusBarStyle.Render(timer.taskName())
+}
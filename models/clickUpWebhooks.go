@@ -0,0 +1,656 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultWebhookAddr = "localhost:4040"
+	// webhookEventRows is how many events the form shows at once.
+	webhookEventRows = 10
+	// webhookLogSize is how many deliveries the log keeps.
+	webhookLogSize = 200
+)
+
+type webhooksMode int
+
+const (
+	webhooksList webhooksMode = iota
+	// webhooksForm creates a webhook, or edits the selected one.
+	webhooksForm
+	// webhooksListen asks where the receiver listens and which secret it checks.
+	webhooksListen
+	// webhooksLog shows what the receiver gets as it arrives.
+	webhooksLog
+)
+
+// Fields of the webhook form; the events checklist sits between the inputs
+// and the submit button.
+const (
+	hookEndpoint = iota
+	hookLocation
+	hookEvents
+	hookSubmit
+)
+
+// ClickUpWebhooks manages the workspace's webhooks and runs a local receiver
+// that logs signed deliveries, for developing integrations against them.
+type ClickUpWebhooks struct {
+	mode     webhooksMode
+	webhooks []ClickUpWebhook
+	list     table.Model
+
+	inputs      []textinput.Model
+	focusIndex  int
+	events      map[string]bool
+	eventCursor int
+	// editing is the webhook the form changes; nil creates one.
+	editing *ClickUpWebhook
+
+	listenInputs []textinput.Model
+	listenFocus  int
+	receiver     *WebhookReceiver
+	deliveries   []ClickUpWebhookDelivery
+	logCursor    int
+	payload      viewport.Model
+
+	confirming bool
+	loading    bool
+	status     string
+
+	token        string
+	refreshToken string
+	user         User
+	clickup      ClickUpProfile
+}
+
+type ClickUpWebhooksMsg struct {
+	webhooks []ClickUpWebhook
+	done     string
+	err      error
+}
+
+type ClickUpWebhookChangedMsg struct {
+	done string
+	err  error
+}
+
+// WebhookDeliveryMsg is a delivery the receiver got.
+type WebhookDeliveryMsg struct {
+	delivery ClickUpWebhookDelivery
+}
+
+// WebhookReceiverStoppedMsg ends waiting on a receiver that was closed.
+type WebhookReceiverStoppedMsg struct{}
+
+func InitialClickUpWebhooks(token string, refreshToken string, user User, clickup ClickUpProfile) ClickUpWebhooks {
+	addr := newFormInput("Listen on host:port", 64, 30)
+	addr.SetValue(defaultWebhookAddr)
+
+	return ClickUpWebhooks{
+		list: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Endpoint", Width: 45},
+				{Title: "Watches", Width: 20},
+				{Title: "Events", Width: 30},
+				{Title: "Health", Width: 20},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		inputs: []textinput.Model{
+			newFormInput("Endpoint URL, e.g. https://example.ngrok.app/clickup", 300, 60),
+			newFormInput("space ID, folder ID, list ID or task ID; blank for the workspace", 64, 60),
+		},
+		events: make(map[string]bool),
+		listenInputs: []textinput.Model{
+			addr,
+			newFormInput("Secret to check signatures with (the listed webhooks' are also accepted)", 200, 60),
+		},
+		payload:      viewport.New(100, 12),
+		loading:      true,
+		status:       "Loading webhooks...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+		clickup:      clickup,
+	}
+}
+
+func (m ClickUpWebhooks) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("ClickUp Webhooks"), m.load(""))
+}
+
+func (m ClickUpWebhooks) load(done string) tea.Cmd {
+	clickup := m.clickup
+	return func() tea.Msg {
+		webhooks, err := ListClickUpWebhooks(clickup)
+		return ClickUpWebhooksMsg{webhooks: webhooks, done: done, err: err}
+	}
+}
+
+func webhookChanged(done string, f func() error) tea.Cmd {
+	return func() tea.Msg { return ClickUpWebhookChangedMsg{done: done, err: f()} }
+}
+
+func waitForWebhook(r *WebhookReceiver) tea.Cmd {
+	return func() tea.Msg {
+		d, ok := r.Next()
+		if !ok {
+			return WebhookReceiverStoppedMsg{}
+		}
+		return WebhookDeliveryMsg{delivery: d}
+	}
+}
+
+func eventsText(events []string) string {
+	if slices.Contains(events, "*") {
+		return "all"
+	}
+	return strings.Join(events, ", ")
+}
+
+func (m *ClickUpWebhooks) showWebhooks() {
+	rows := make([]table.Row, len(m.webhooks))
+	for i, w := range m.webhooks {
+		health := w.Health.Status
+		if w.Health.FailCount > 0 {
+			health += fmt.Sprintf(" (%d failed)", w.Health.FailCount)
+		}
+		rows[i] = table.Row{w.Endpoint, w.location(), eventsText(w.Events), health}
+	}
+	m.list.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.list.Cursor(); c < 0 || c >= len(rows) {
+		m.list.SetCursor(0)
+	}
+}
+
+func (m ClickUpWebhooks) selected() (ClickUpWebhook, bool) {
+	i := m.list.Cursor()
+	if i < 0 || i >= len(m.webhooks) {
+		return ClickUpWebhook{}, false
+	}
+	return m.webhooks[i], true
+}
+
+// openForm shows the webhook form, filled from w when editing it.
+func (m *ClickUpWebhooks) openForm(w *ClickUpWebhook) tea.Cmd {
+	m.mode = webhooksForm
+	m.editing = w
+	m.events = make(map[string]bool)
+	m.eventCursor = 0
+	m.inputs[hookEndpoint].Reset()
+	m.inputs[hookLocation].Reset()
+	if w != nil {
+		m.inputs[hookEndpoint].SetValue(w.Endpoint)
+		m.inputs[hookLocation].SetValue(w.location())
+		for _, e := range w.Events {
+			m.events[e] = true
+		}
+	}
+	m.status = ""
+	m.focusIndex = hookEndpoint
+	return focusInput(m.inputs, m.focusIndex)
+}
+
+// selectedEvents is what the form subscribes to, "*" standing for all.
+func (m ClickUpWebhooks) selectedEvents() []string {
+	if m.events["*"] {
+		return []string{"*"}
+	}
+	var events []string
+	for _, e := range clickUpWebhookEvents {
+		if m.events[e] {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (m *ClickUpWebhooks) submitForm() tea.Cmd {
+	endpoint := strings.TrimSpace(m.inputs[hookEndpoint].Value())
+	events := m.selectedEvents()
+	switch {
+	case endpoint == "":
+		m.status = "Enter the URL ClickUp should deliver to."
+		return nil
+	case len(events) == 0:
+		m.status = "Pick at least one event, or a for all of them."
+		return nil
+	}
+
+	clickup := m.clickup
+	m.mode = webhooksList
+	m.loading = true
+	if m.editing != nil {
+		w := *m.editing
+		status := "active"
+		if w.Health.Status == "suspended" {
+			status = "suspended"
+		}
+		m.status = "Saving webhook..."
+		return webhookChanged("Webhook saved.", func() error {
+			return UpdateClickUpWebhook(clickup, w.ID, endpoint, events, status)
+		})
+	}
+
+	location := m.inputs[hookLocation].Value()
+	if _, _, err := parseWebhookLocation(location); err != nil {
+		m.mode = webhooksForm
+		m.loading = false
+		m.status = err.Error()
+		return nil
+	}
+	m.status = "Creating webhook..."
+	return webhookChanged("Webhook created.", func() error {
+		_, err := CreateClickUpWebhook(clickup, endpoint, events, location)
+		return err
+	})
+}
+
+// moveFocus steps through the form. ClickUp can't move a webhook, so the
+// location is skipped when editing.
+func (m *ClickUpWebhooks) moveFocus(key string) tea.Cmd {
+	m.focusIndex = nextFocus(m.focusIndex, hookSubmit, key)
+	if m.focusIndex == hookLocation && m.editing != nil {
+		m.focusIndex = nextFocus(m.focusIndex, hookSubmit, key)
+	}
+	return focusInput(m.inputs, m.focusIndex)
+}
+
+func (m *ClickUpWebhooks) updateForm(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch key {
+	case "esc":
+		m.mode = webhooksList
+		m.status = ""
+		return nil
+	case "tab", "shift+tab":
+		return m.moveFocus(key)
+	case "enter":
+		if m.focusIndex == hookSubmit {
+			return m.submitForm()
+		}
+		return m.moveFocus(key)
+	}
+
+	switch m.focusIndex {
+	case hookEvents:
+		switch key {
+		case "up", "k":
+			m.eventCursor = max(m.eventCursor-1, 0)
+		case "down", "j":
+			m.eventCursor = min(m.eventCursor+1, len(clickUpWebhookEvents)-1)
+		case " ":
+			e := clickUpWebhookEvents[m.eventCursor]
+			m.events[e] = !m.events[e]
+		case "a":
+			m.events["*"] = !m.events["*"]
+		}
+		return nil
+	case hookSubmit:
+		if key == "up" || key == "down" {
+			return m.moveFocus(key)
+		}
+		return nil
+	}
+
+	if key == "up" || key == "down" {
+		return m.moveFocus(key)
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	return cmd
+}
+
+// startReceiver listens with the typed secret plus those of the listed webhooks.
+func (m *ClickUpWebhooks) startReceiver() tea.Cmd {
+	addr := strings.TrimSpace(m.listenInputs[0].Value())
+	if addr == "" {
+		addr = defaultWebhookAddr
+	}
+	secrets := []string{strings.TrimSpace(m.listenInputs[1].Value())}
+	for _, w := range m.webhooks {
+		secrets = append(secrets, w.Secret)
+	}
+
+	r, err := StartWebhookReceiver(addr, secrets)
+	if err != nil {
+		m.status = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+	m.receiver = r
+	m.deliveries = nil
+	m.logCursor = 0
+	m.payload.SetContent("")
+	m.mode = webhooksLog
+	m.status = ""
+	return waitForWebhook(r)
+}
+
+func (m *ClickUpWebhooks) stopReceiver() {
+	if m.receiver != nil {
+		m.receiver.Close()
+		m.receiver = nil
+	}
+}
+
+func (m *ClickUpWebhooks) showPayload() {
+	if m.logCursor < 0 || m.logCursor >= len(m.deliveries) {
+		m.payload.SetContent("")
+		return
+	}
+	m.payload.SetContent(m.deliveries[m.logCursor].Body)
+	m.payload.GotoTop()
+}
+
+func (m ClickUpWebhooks) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ClickUpWebhooksMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.webhooks = msg.webhooks
+		m.showWebhooks()
+		m.status = msg.done
+		if m.status == "" {
+			m.status = countOf(len(m.webhooks), "webhook")
+		}
+		return m, nil
+
+	case ClickUpWebhookChangedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.loading = true
+		return m, m.load(msg.done)
+
+	case WebhookDeliveryMsg:
+		if m.receiver == nil {
+			return m, nil
+		}
+		// Keep following the newest delivery unless an older one is selected.
+		following := len(m.deliveries) == 0 || m.logCursor == len(m.deliveries)-1
+		m.deliveries = append(m.deliveries, msg.delivery)
+		if len(m.deliveries) > webhookLogSize {
+			m.deliveries = m.deliveries[len(m.deliveries)-webhookLogSize:]
+			m.logCursor = max(m.logCursor-1, 0)
+		}
+		if following {
+			m.logCursor = len(m.deliveries) - 1
+			m.showPayload()
+		}
+		return m, waitForWebhook(m.receiver)
+
+	case WebhookReceiverStoppedMsg:
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.stopReceiver()
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case webhooksForm:
+			return m, m.updateForm(msg)
+
+		case webhooksListen:
+			switch msg.String() {
+			case "esc":
+				m.mode = webhooksList
+				return m, nil
+			case "tab", "shift+tab", "enter", "up", "down":
+				s := msg.String()
+				if s == "enter" && m.listenFocus == len(m.listenInputs) {
+					return m, m.startReceiver()
+				}
+				m.listenFocus = nextFocus(m.listenFocus, len(m.listenInputs), s)
+				return m, focusInput(m.listenInputs, m.listenFocus)
+			}
+			if m.listenFocus == len(m.listenInputs) {
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.listenInputs[m.listenFocus], cmd = m.listenInputs[m.listenFocus].Update(msg)
+			return m, cmd
+
+		case webhooksLog:
+			switch msg.String() {
+			case "esc":
+				m.stopReceiver()
+				m.mode = webhooksList
+				m.status = "Receiver stopped."
+				return m, nil
+			case "up", "k":
+				if m.logCursor > 0 {
+					m.logCursor--
+					m.showPayload()
+				}
+				return m, nil
+			case "down", "j":
+				if m.logCursor < len(m.deliveries)-1 {
+					m.logCursor++
+					m.showPayload()
+				}
+				return m, nil
+			case "c":
+				m.deliveries = nil
+				m.logCursor = 0
+				m.showPayload()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.payload, cmd = m.payload.Update(msg)
+			return m, cmd
+		}
+
+		if m.confirming {
+			m.confirming = false
+			w, ok := m.selected()
+			if !ok || (msg.String() != "y" && msg.String() != "Y") {
+				m.status = "Cancelled."
+				return m, nil
+			}
+			clickup := m.clickup
+			m.loading = true
+			m.status = "Deleting webhook..."
+			return m, webhookChanged("Webhook deleted.", func() error { return DeleteClickUpWebhook(clickup, w.ID) })
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg { return ShowClickUpMenuMsg{} }
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			m.status = "Refreshing..."
+			return m, m.load("")
+		case "n":
+			return m, m.openForm(nil)
+		case "l":
+			m.mode = webhooksListen
+			m.status = ""
+			if w, ok := m.selected(); ok {
+				m.listenInputs[1].SetValue(w.Secret)
+			}
+			m.listenFocus = 0
+			return m, focusInput(m.listenInputs, m.listenFocus)
+		}
+
+		w, ok := m.selected()
+		if !ok {
+			break
+		}
+		switch msg.String() {
+		case "enter", "e":
+			return m, m.openForm(&w)
+		case "s":
+			status, done := "suspended", "Webhook suspended."
+			if w.Health.Status == "suspended" {
+				status, done = "active", "Webhook resumed."
+			}
+			clickup := m.clickup
+			m.loading = true
+			m.status = "Updating webhook..."
+			return m, webhookChanged(done, func() error {
+				return UpdateClickUpWebhook(clickup, w.ID, w.Endpoint, w.Events, status)
+			})
+		case "x":
+			m.confirming = true
+			m.status = fmt.Sprintf("Delete the webhook to %s? (y/n)", w.Endpoint)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m ClickUpWebhooks) formView() string {
+	var b strings.Builder
+
+	title := "New webhook"
+	if m.editing != nil {
+		title = "Edit webhook " + m.editing.ID
+	}
+	b.WriteString(title + "\n\n")
+	b.WriteString(m.inputs[hookEndpoint].View() + "\n")
+	if m.editing != nil {
+		b.WriteString(blurredStyle.Render("  Watches "+m.editing.location()+" (fixed once created)") + "\n\n")
+	} else {
+		b.WriteString(m.inputs[hookLocation].View() + "\n\n")
+	}
+
+	label := blurredStyle.Render("Events")
+	if m.focusIndex == hookEvents {
+		label = focusedStyle.Render("Events")
+	}
+	b.WriteString(label)
+	if m.events["*"] {
+		b.WriteString(" (all events)")
+	} else {
+		fmt.Fprintf(&b, " (%d chosen)", len(m.selectedEvents()))
+	}
+	b.WriteString("\n")
+	first := max(0, min(m.eventCursor-webhookEventRows/2, len(clickUpWebhookEvents)-webhookEventRows))
+	for i := first; i < min(first+webhookEventRows, len(clickUpWebhookEvents)); i++ {
+		e := clickUpWebhookEvents[i]
+		box := "[ ]"
+		if m.events[e] || m.events["*"] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", box, e)
+		if m.focusIndex == hookEvents && i == m.eventCursor {
+			line = focusedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	button := &blurredButton
+	if m.focusIndex == hookSubmit {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+	b.WriteString(helpStyle.Render("tab: next field • ↑/↓ + space: pick events • a: all events • esc: cancel"))
+	return b.String()
+}
+
+func (m ClickUpWebhooks) logView() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Listening on http://%s/ • %s\n", m.receiver.Addr, countOf(len(m.deliveries), "request"))
+	b.WriteString(blurredStyle.Render("ClickUp must reach this address, e.g. through a tunnel. Test requests are signed with the hex HMAC-SHA256 of the body in X-Signature.") + "\n\n")
+
+	if len(m.deliveries) == 0 {
+		b.WriteString("Waiting for deliveries...\n")
+	}
+	first := max(0, min(m.logCursor-4, len(m.deliveries)-8))
+	for i := first; i < min(first+8, len(m.deliveries)); i++ {
+		d := m.deliveries[i]
+		check := commentResolvedStyle.Render("✓ signed")
+		if !d.Verified {
+			check = statusWarningStyle.Render("✗ bad signature")
+		}
+		event := d.Event
+		if event == "" {
+			event = "(not a ClickUp event)"
+		}
+		line := fmt.Sprintf("%s %-15s %-26s", d.Received.Format("15:04:05"), check, event)
+		if d.TaskID != "" {
+			line += " task " + d.TaskID
+		}
+		if d.WebhookID != "" {
+			line += " • webhook " + d.WebhookID
+		}
+		if i == m.logCursor {
+			line = focusedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n" + m.payload.View() + "\n\n")
+	b.WriteString(helpStyle.Render("↑/↓: pick delivery • pgup/pgdown: scroll payload • c: clear • esc: stop receiver"))
+	return b.String()
+}
+
+func (m ClickUpWebhooks) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nWebhooks\n\n")
+
+	switch m.mode {
+	case webhooksForm:
+		b.WriteString(m.formView())
+	case webhooksLog:
+		b.WriteString(m.logView())
+	case webhooksListen:
+		b.WriteString("Start a local receiver\n\n")
+		for i := range m.listenInputs {
+			b.WriteString(m.listenInputs[i].View() + "\n")
+		}
+		button := &blurredButton
+		if m.listenFocus == len(m.listenInputs) {
+			button = &focusedButton
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", *button)
+		b.WriteString(helpStyle.Render("tab: next field • esc: cancel"))
+	default:
+		b.WriteString(m.list.View())
+		b.WriteString("\n\n")
+		if !m.loading && len(m.webhooks) == 0 {
+			b.WriteString("No webhooks in this workspace.\n\n")
+		}
+		b.WriteString(helpStyle.Render("n: new • e: edit • s: suspend/resume • x: delete • l: listen locally • r: refresh • esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,417 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultClickUpAPI is the ClickUp API; CLICKUP_API_URL overrides it, e.g.
+	// to point at a stand-in serving recorded responses.
+	defaultClickUpAPI   = "https://api.clickup.com/api/v2"
+	clickUpAuthorizeURL = "https://app.clickup.com/api"
+	// defaultClickUpRedirect must match the redirect URL registered for the
+	// OAuth app; CLICKUP_REDIRECT_URL overrides it.
+	defaultClickUpRedirect = "http://localhost:4320/callback"
+)
+
+// ClickUpProfile is the ClickUp credential and workspace kept for one
+// crispy-doodle user. Every ClickUp screen works inside its workspace.
+type ClickUpProfile struct {
+	Token string `json:"token"`
+	// OAuth tokens are sent as bearer tokens; personal tokens (pk_...) are sent as is.
+	OAuth         bool   `json:"oauth,omitempty"`
+	UserID        int    `json:"userId,omitempty"`
+	Username      string `json:"username,omitempty"`
+	WorkspaceID   string `json:"workspaceId,omitempty"`
+	WorkspaceName string `json:"workspaceName,omitempty"`
+}
+
+type ClickUpUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Color    string `json:"color"`
+}
+
+type ClickUpWorkspace struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	Members []struct {
+		User ClickUpUser `json:"user"`
+	} `json:"members"`
+}
+
+// clickUpProfiles serialises reads and writes of clickup.json.
+var clickUpProfiles sync.Mutex
+
+func clickUpProfilesPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "clickup.json"), nil
+}
+
+// profileKey names the profile of a crispy-doodle user.
+func profileKey(user User) string {
+	if user.Email != "" {
+		return user.Email
+	}
+	return user.ID
+}
+
+func readClickUpProfiles() (map[string]ClickUpProfile, error) {
+	profiles := make(map[string]ClickUpProfile)
+
+	path, err := clickUpProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading ClickUp profiles: %w", err)
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("decoding ClickUp profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func writeClickUpProfiles(profiles map[string]ClickUpProfile) error {
+	path, err := clickUpProfilesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding ClickUp profiles: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing ClickUp profiles: %w", err)
+	}
+	return nil
+}
+
+// LoadClickUpProfile returns the saved ClickUp profile for user, if any.
+func LoadClickUpProfile(user User) (ClickUpProfile, bool, error) {
+	clickUpProfiles.Lock()
+	defer clickUpProfiles.Unlock()
+
+	profiles, err := readClickUpProfiles()
+	if err != nil {
+		return ClickUpProfile{}, false, err
+	}
+	p, ok := profiles[profileKey(user)]
+	return p, ok, nil
+}
+
+func SaveClickUpProfile(user User, p ClickUpProfile) error {
+	clickUpProfiles.Lock()
+	defer clickUpProfiles.Unlock()
+
+	profiles, err := readClickUpProfiles()
+	if err != nil {
+		return err
+	}
+	profiles[profileKey(user)] = p
+	return writeClickUpProfiles(profiles)
+}
+
+// DeleteClickUpProfile signs user out of ClickUp.
+func DeleteClickUpProfile(user User) error {
+	clickUpProfiles.Lock()
+	defer clickUpProfiles.Unlock()
+
+	profiles, err := readClickUpProfiles()
+	if err != nil {
+		return err
+	}
+	delete(profiles, profileKey(user))
+	return writeClickUpProfiles(profiles)
+}
+
+func clickUpAPI() string {
+	if api := os.Getenv("CLICKUP_API_URL"); api != "" {
+		return strings.TrimSuffix(api, "/")
+	}
+	return defaultClickUpAPI
+}
+
+func (p ClickUpProfile) authorization() string {
+	if p.OAuth {
+		return "Bearer " + p.Token
+	}
+	return p.Token
+}
+
+// doClickUp calls the ClickUp API. path is relative to /api/v2, in is sent as
+// JSON when not nil and the response is decoded into out when not nil.
+func doClickUp(p ClickUpProfile, method string, path string, q url.Values, in any, out any) error {
+	return doClickUpAt(p, clickUpAPI()+path, method, q, in, out)
+}
+
+// doClickUpV3 calls the v3 API, which newer features such as Docs are only in.
+func doClickUpV3(p ClickUpProfile, method string, path string, q url.Values, in any, out any) error {
+	return doClickUpAt(p, strings.TrimSuffix(clickUpAPI(), "/v2")+"/v3"+path, method, q, in, out)
+}
+
+func doClickUpAt(p ClickUpProfile, endpoint string, method string, q url.Values, in any, out any) error {
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return sendClickUp(p, req, out)
+}
+
+// sendClickUp sends a prepared request with the profile's credentials, turns
+// ClickUp's error replies into errors and decodes the reply into out.
+func sendClickUp(p ClickUpProfile, req *http.Request, out any) error {
+	req.Header.Set("Authorization", p.authorization())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Err   string `json:"err"`
+			ECode string `json:"ECODE"`
+		}
+		if json.Unmarshal(data, &e) == nil && e.Err != "" {
+			return &statusError{StatusCode: resp.StatusCode, Body: fmt.Sprintf("%s (%s)", e.Err, e.ECode)}
+		}
+		return &statusError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// GetClickUpUser returns the user the token belongs to, which also checks the token.
+func GetClickUpUser(p ClickUpProfile) (ClickUpUser, error) {
+	var out struct {
+		User ClickUpUser `json:"user"`
+	}
+	err := doClickUp(p, http.MethodGet, "/user", nil, nil, &out)
+	return out.User, err
+}
+
+// ListClickUpWorkspaces returns the workspaces (teams, in the API) the token can access.
+func ListClickUpWorkspaces(p ClickUpProfile) ([]ClickUpWorkspace, error) {
+	var out struct {
+		Teams []ClickUpWorkspace `json:"teams"`
+	}
+	err := doClickUp(p, http.MethodGet, "/team", nil, nil, &out)
+	return out.Teams, err
+}
+
+// clickUpOAuthApp reads the OAuth app credentials from the environment.
+func clickUpOAuthApp() (clientID string, secret string, redirect string, err error) {
+	clientID = os.Getenv("CLICKUP_CLIENT_ID")
+	secret = os.Getenv("CLICKUP_CLIENT_SECRET")
+	if clientID == "" || secret == "" {
+		return "", "", "", fmt.Errorf("set CLICKUP_CLIENT_ID and CLICKUP_CLIENT_SECRET to sign in with OAuth")
+	}
+	redirect = os.Getenv("CLICKUP_REDIRECT_URL")
+	if redirect == "" {
+		redirect = defaultClickUpRedirect
+	}
+	return clientID, secret, redirect, nil
+}
+
+// ClickUpOAuth is a pending OAuth sign-in waiting for ClickUp to redirect
+// the browser back to a local listener.
+type ClickUpOAuth struct {
+	URL      string
+	server   *http.Server
+	codes    chan string
+	errs     chan error
+	clientID string
+	secret   string
+}
+
+// StartClickUpOAuth starts the local redirect listener and returns the URL to
+// open in a browser.
+func StartClickUpOAuth() (*ClickUpOAuth, error) {
+	clientID, secret, redirect, err := clickUpOAuthApp()
+	if err != nil {
+		return nil, err
+	}
+	redirectURL, err := url.Parse(redirect)
+	if err != nil {
+		return nil, fmt.Errorf("parsing redirect URL: %w", err)
+	}
+	// A bare http://localhost:4320 redirects to the root; ServeMux rejects an
+	// empty pattern.
+	if redirectURL.Path == "" {
+		redirectURL.Path = "/"
+	}
+
+	state := make([]byte, 16)
+	if _, err := rand.Read(state); err != nil {
+		return nil, fmt.Errorf("generating state: %w", err)
+	}
+	wantState := hex.EncodeToString(state)
+
+	listener, err := net.Listen("tcp", redirectURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("starting redirect listener: %w", err)
+	}
+
+	o := &ClickUpOAuth{
+		URL: clickUpAuthorizeURL + "?" + url.Values{
+			"client_id":    {clientID},
+			"redirect_uri": {redirect},
+			"state":        {wantState},
+		}.Encode(),
+		codes:    make(chan string, 1),
+		errs:     make(chan error, 1),
+		clientID: clientID,
+		secret:   secret,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirectURL.Path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		code := q.Get("code")
+		switch {
+		case q.Get("state") != wantState:
+			http.Error(w, "Sign-in state doesn't match; start again from the terminal.", http.StatusBadRequest)
+			return
+		case code == "":
+			http.Error(w, "ClickUp didn't return an authorization code.", http.StatusBadRequest)
+			select {
+			case o.errs <- fmt.Errorf("authorization was denied"):
+			default:
+			}
+			return
+		}
+		fmt.Fprintln(w, "Signed in to ClickUp. You can close this tab and return to the terminal.")
+		select {
+		case o.codes <- code:
+		default:
+		}
+	})
+	o.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go o.server.Serve(listener)
+
+	return o, nil
+}
+
+// Wait blocks until the browser is redirected back, then exchanges the code
+// for an access token and stops the listener.
+func (o *ClickUpOAuth) Wait(ctx context.Context) (ClickUpProfile, error) {
+	defer o.Close()
+
+	var code string
+	select {
+	case code = <-o.codes:
+	case err := <-o.errs:
+		return ClickUpProfile{}, err
+	case <-ctx.Done():
+		return ClickUpProfile{}, ctx.Err()
+	}
+
+	q := url.Values{"client_id": {o.clientID}, "client_secret": {o.secret}, "code": {code}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, clickUpAPI()+"/oauth/token?"+q.Encode(), nil)
+	if err != nil {
+		return ClickUpProfile{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ClickUpProfile{}, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ClickUpProfile{}, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ClickUpProfile{}, &statusError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return ClickUpProfile{}, fmt.Errorf("decoding response: %w", err)
+	}
+	return ClickUpProfile{Token: out.AccessToken, OAuth: true}, nil
+}
+
+func (o *ClickUpOAuth) Close() {
+	o.server.Close()
+}
+
+// openBrowser opens link with the desktop's default handler.
+func openBrowser(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// looksLikePersonalToken catches pasting something other than a personal
+// token before it is sent to ClickUp.
+func looksLikePersonalToken(token string) bool {
+	return strings.HasPrefix(token, "pk_")
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ClickUpAttachment is a file attached to a task.
+type ClickUpAttachment struct {
+	ID        string      `json:"id"`
+	Title     string      `json:"title"`
+	Extension string      `json:"extension"`
+	Mimetype  string      `json:"mimetype"`
+	Size      int64       `json:"size"`
+	Date      clickUpTime `json:"date"`
+	User      ClickUpUser `json:"user"`
+	URL       string      `json:"url"`
+	Deleted   bool        `json:"deleted"`
+}
+
+func (a ClickUpAttachment) contentType() string {
+	if a.Mimetype != "" {
+		return a.Mimetype
+	}
+	if t := mime.TypeByExtension(path.Ext(a.Title)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// ListClickUpAttachments returns the task with its attachments, newest first.
+// ClickUp has no endpoint for attachments alone; they come with the task.
+func ListClickUpAttachments(p ClickUpProfile, taskID string) (ClickUpTask, error) {
+	task, err := GetClickUpTaskDetail(p, taskID)
+	if err != nil {
+		return task, err
+	}
+	attachments := task.Attachments[:0]
+	for _, a := range task.Attachments {
+		if !a.Deleted {
+			attachments = append(attachments, a)
+		}
+	}
+	sort.SliceStable(attachments, func(i, j int) bool { return attachments[i].Date.After(attachments[j].Date.Time) })
+	task.Attachments = attachments
+	return task, nil
+}
+
+// UploadClickUpAttachment attaches the contents of r to a task as name,
+// streaming it so large design files aren't held in memory.
+func UploadClickUpAttachment(p ClickUpProfile, taskID string, name string, r io.Reader) error {
+	body, w := io.Pipe()
+	form := multipart.NewWriter(w)
+	go func() {
+		part, err := form.CreateFormFile("attachment", name)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		w.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, clickUpAPI()+"/task/"+taskID+"/attachment", body)
+	if err != nil {
+		body.Close()
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return sendClickUp(p, req, nil)
+}
+
+// openClickUpAttachment starts downloading an attachment. Attachment links
+// are signed, so they are fetched without the ClickUp credentials.
+func openClickUpAttachment(a ClickUpAttachment) (*http.Response, error) {
+	resp, err := http.Get(a.URL)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return resp, nil
+}
+
+// DownloadClickUpAttachment saves an attachment to localPath, returning its size.
+func DownloadClickUpAttachment(a ClickUpAttachment, localPath string) (int64, error) {
+	resp, err := openClickUpAttachment(a)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return 0, fmt.Errorf("creating directory: %w", err)
+	}
+	f, err := os.Create(localPath)
+	if err != nil {
+		return 0, fmt.Errorf("creating %s: %w", localPath, err)
+	}
+	n, err := io.Copy(f, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(localPath)
+		return 0, fmt.Errorf("writing %s: %w", localPath, err)
+	}
+	return n, nil
+}
+
+// UploadClickUpAttachmentFile attaches a local file to a task under its own name.
+func UploadClickUpAttachmentFile(p ClickUpProfile, taskID string, localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", localPath, err)
+	}
+	defer f.Close()
+	return UploadClickUpAttachment(p, taskID, filepath.Base(localPath), f)
+}
+
+// CopyClickUpAttachmentToS3 streams an attachment into S3 through the
+// crispy-doodle S3 endpoints.
+func CopyClickUpAttachmentToS3(token string, a ClickUpAttachment, bucket string, key string) error {
+	resp, err := openClickUpAttachment(a)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return PutS3ObjectFrom(token, bucket, key, a.contentType(), resp.Body, resp.ContentLength)
+}
+
+// AttachS3Object streams an S3 object to a task under the object's file name.
+func AttachS3Object(p ClickUpProfile, token string, bucket string, key string, taskID string) error {
+	body, err := OpenS3Object(token, bucket, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return UploadClickUpAttachment(p, taskID, path.Base(key), body)
+}
+
+// parseS3Object reads s3://bucket/key. A key ending in "/" is a folder, and
+// name is put in it.
+func parseS3Object(s string, name string) (string, string, error) {
+	bucket, prefix, err := parseS3Location(s)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.HasSuffix(strings.TrimSpace(s), "/") || prefix == "" {
+		if name == "" {
+			return "", "", fmt.Errorf("expected s3://bucket/key, got %q", s)
+		}
+		return bucket, prefix + name, nil
+	}
+	return bucket, strings.TrimSuffix(prefix, "/"), nil
+}
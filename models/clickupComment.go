@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// clickUpCommentPageSize is how many comments ClickUp returns per page.
+const clickUpCommentPageSize = 25
+
+// ClickUpComment is a comment on a task, list or chat view, or a reply in
+// one's thread.
+type ClickUpComment struct {
+	ID         string       `json:"id"`
+	Text       string       `json:"comment_text"`
+	User       ClickUpUser  `json:"user"`
+	Resolved   bool         `json:"resolved"`
+	Assignee   *ClickUpUser `json:"assignee"`
+	AssignedBy *ClickUpUser `json:"assigned_by"`
+	Date       clickUpTime  `json:"date"`
+	ReplyCount looseCount   `json:"reply_count"`
+}
+
+// ClickUpCommentTarget is what a comment thread hangs off.
+type ClickUpCommentTarget struct {
+	// Kind is task, list or view.
+	Kind string
+	ID   string
+	Name string
+}
+
+func (t ClickUpCommentTarget) path() string {
+	return "/" + t.Kind + "/" + url.PathEscape(t.ID) + "/comment"
+}
+
+// parseCommentTarget reads "task ID", "list ID" or "view ID"; a bare ID is a task.
+func parseCommentTarget(s string) (ClickUpCommentTarget, error) {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 1:
+		return ClickUpCommentTarget{Kind: "task", ID: fields[0]}, nil
+	case len(fields) == 2 && (fields[0] == "task" || fields[0] == "list" || fields[0] == "view"):
+		return ClickUpCommentTarget{Kind: fields[0], ID: fields[1]}, nil
+	}
+	return ClickUpCommentTarget{}, fmt.Errorf("type task ID, list ID or view ID")
+}
+
+// ListClickUpComments returns up to 25 comments, newest first. Passing the
+// oldest comment seen so far returns the ones before it.
+func ListClickUpComments(p ClickUpProfile, target ClickUpCommentTarget, before *ClickUpComment) ([]ClickUpComment, error) {
+	var q url.Values
+	if before != nil {
+		q = url.Values{"start": {fmt.Sprint(before.Date.UnixMilli())}, "start_id": {before.ID}}
+	}
+	var out struct {
+		Comments []ClickUpComment `json:"comments"`
+	}
+	err := doClickUp(p, http.MethodGet, target.path(), q, nil, &out)
+	return out.Comments, err
+}
+
+// ListClickUpReplies returns a comment's thread, oldest first.
+func ListClickUpReplies(p ClickUpProfile, commentID string) ([]ClickUpComment, error) {
+	var out struct {
+		Comments []ClickUpComment `json:"comments"`
+	}
+	if err := doClickUp(p, http.MethodGet, "/comment/"+commentID+"/reply", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out.Comments, func(i, j int) bool { return out.Comments[i].Date.Before(out.Comments[j].Date.Time) })
+	return out.Comments, nil
+}
+
+// commentBody sends text as rich comment parts so @mentions of members
+// become real mentions that notify them.
+func commentBody(text string, members []ClickUpUser) map[string]any {
+	var parts []map[string]any
+	mentioned := false
+	rest := text
+	for {
+		i := strings.Index(rest, "@")
+		if i < 0 {
+			break
+		}
+		u, ok := mentionAt(rest[i+1:], members)
+		if !ok {
+			parts = appendText(parts, rest[:i+1])
+			rest = rest[i+1:]
+			continue
+		}
+		parts = appendText(parts, rest[:i])
+		parts = append(parts, map[string]any{"type": "tag", "user": map[string]any{"id": u.ID}})
+		rest = rest[i+1+len(u.Username):]
+		mentioned = true
+	}
+	if !mentioned {
+		return map[string]any{"comment_text": text}
+	}
+	return map[string]any{"comment": appendText(parts, rest)}
+}
+
+func appendText(parts []map[string]any, text string) []map[string]any {
+	if text == "" {
+		return parts
+	}
+	if n := len(parts); n > 0 && parts[n-1]["type"] == nil {
+		parts[n-1]["text"] = parts[n-1]["text"].(string) + text
+		return parts
+	}
+	return append(parts, map[string]any{"text": text})
+}
+
+// mentionAt finds the member whose name s starts with, preferring the longest
+// so "Jane Doe" wins over "Jane".
+func mentionAt(s string, members []ClickUpUser) (ClickUpUser, bool) {
+	var best ClickUpUser
+	for _, u := range members {
+		if u.Username != "" && len(u.Username) > len(best.Username) && len(s) >= len(u.Username) && strings.EqualFold(s[:len(u.Username)], u.Username) {
+			best = u
+		}
+	}
+	return best, best.Username != ""
+}
+
+func CreateClickUpComment(p ClickUpProfile, target ClickUpCommentTarget, text string, members []ClickUpUser) error {
+	body := commentBody(text, members)
+	body["notify_all"] = false
+	return doClickUp(p, http.MethodPost, target.path(), nil, body, nil)
+}
+
+func ReplyToClickUpComment(p ClickUpProfile, commentID string, text string, members []ClickUpUser) error {
+	body := commentBody(text, members)
+	body["notify_all"] = false
+	return doClickUp(p, http.MethodPost, "/comment/"+commentID+"/reply", nil, body, nil)
+}
+
+// UpdateClickUpComment rewrites a comment. ClickUp wants the text, assignee
+// and resolved state together, so c carries the whole updated comment; an
+// edit's mentions stay plain text as edits don't notify anyone.
+func UpdateClickUpComment(p ClickUpProfile, c ClickUpComment) error {
+	body := map[string]any{"comment_text": c.Text, "resolved": c.Resolved}
+	if c.Assignee != nil {
+		body["assignee"] = c.Assignee.ID
+	}
+	return doClickUp(p, http.MethodPut, "/comment/"+c.ID, nil, body, nil)
+}
+
+func DeleteClickUpComment(p ClickUpProfile, commentID string) error {
+	return doClickUp(p, http.MethodDelete, "/comment/"+commentID, nil, nil, nil)
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// maxClickUpDocPages bounds how many pages of Docs are fetched, in case a
+// cursor never runs out.
+const maxClickUpDocPages = 20
+
+type ClickUpDoc struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	DateCreated clickUpTime `json:"date_created"`
+	DateUpdated clickUpTime `json:"date_updated"`
+	Deleted     bool        `json:"deleted"`
+	Archived    bool        `json:"archived"`
+}
+
+// ClickUpDocPage is a page of a Doc. Listings nest sub-pages in Pages and
+// leave Content empty; GetClickUpDocPage fills in Content as markdown.
+type ClickUpDocPage struct {
+	ID           string           `json:"id"`
+	DocID        string           `json:"doc_id"`
+	ParentPageID string           `json:"parent_page_id"`
+	Name         string           `json:"name"`
+	Content      string           `json:"content"`
+	DateUpdated  clickUpTime      `json:"date_updated"`
+	Pages        []ClickUpDocPage `json:"pages"`
+}
+
+func docsPath(p ClickUpProfile) string {
+	return "/workspaces/" + p.WorkspaceID + "/docs"
+}
+
+// docPageURL links to a page in the ClickUp app, or the Doc without a page.
+func docPageURL(p ClickUpProfile, docID string, pageID string) string {
+	link := "https://app.clickup.com/" + p.WorkspaceID + "/v/dc/" + docID
+	if pageID != "" {
+		link += "/" + pageID
+	}
+	return link
+}
+
+// ListClickUpDocs returns the workspace's Docs that aren't deleted or
+// archived, by name.
+func ListClickUpDocs(p ClickUpProfile) ([]ClickUpDoc, error) {
+	var docs []ClickUpDoc
+	cursor := ""
+	for range maxClickUpDocPages {
+		q := url.Values{"deleted": {"false"}, "archived": {"false"}, "limit": {"100"}}
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+		var out struct {
+			Docs       []ClickUpDoc `json:"docs"`
+			NextCursor string       `json:"next_cursor"`
+		}
+		if err := doClickUpV3(p, http.MethodGet, docsPath(p), q, nil, &out); err != nil {
+			return nil, err
+		}
+		docs = append(docs, out.Docs...)
+		if out.NextCursor == "" || len(out.Docs) == 0 {
+			break
+		}
+		cursor = out.NextCursor
+	}
+	slices.SortFunc(docs, func(a, b ClickUpDoc) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return docs, nil
+}
+
+// ListClickUpDocPages returns a Doc's page tree without content.
+func ListClickUpDocPages(p ClickUpProfile, docID string) ([]ClickUpDocPage, error) {
+	var pages []ClickUpDocPage
+	q := url.Values{"max_page_depth": {"-1"}}
+	err := doClickUpV3(p, http.MethodGet, docsPath(p)+"/"+docID+"/pageListing", q, nil, &pages)
+	return pages, err
+}
+
+func GetClickUpDocPage(p ClickUpProfile, docID string, pageID string) (ClickUpDocPage, error) {
+	var page ClickUpDocPage
+	q := url.Values{"content_format": {"text/md"}}
+	err := doClickUpV3(p, http.MethodGet, docsPath(p)+"/"+docID+"/pages/"+pageID, q, nil, &page)
+	return page, err
+}
+
+// UpdateClickUpDocPage replaces a page's name and markdown content.
+func UpdateClickUpDocPage(p ClickUpProfile, docID string, pageID string, name string, content string) error {
+	in := map[string]any{
+		"name":              name,
+		"content":           content,
+		"content_edit_mode": "replace",
+		"content_format":    "text/md",
+	}
+	return doClickUpV3(p, http.MethodPut, docsPath(p)+"/"+docID+"/pages/"+pageID, nil, in, nil)
+}
+
+// CreateClickUpDocPage adds a page to a Doc, under parentPageID if set.
+func CreateClickUpDocPage(p ClickUpProfile, docID string, parentPageID string, name string, content string) (ClickUpDocPage, error) {
+	in := map[string]any{"name": name, "content": content, "content_format": "text/md"}
+	if parentPageID != "" {
+		in["parent_page_id"] = parentPageID
+	}
+	var page ClickUpDocPage
+	err := doClickUpV3(p, http.MethodPost, docsPath(p)+"/"+docID+"/pages", nil, in, &page)
+	return page, err
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of goal target.
+const (
+	targetNumber     = "number"
+	targetCurrency   = "currency"
+	targetBoolean    = "boolean"
+	targetPercentage = "percentage"
+	// targetAutomatic targets follow the completion of tasks or lists.
+	targetAutomatic = "automatic"
+)
+
+type ClickUpGoal struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	DueDate          clickUpTime        `json:"due_date"`
+	Owners           []ClickUpUser      `json:"owners"`
+	PercentCompleted looseNumber        `json:"percent_completed"`
+	Archived         bool               `json:"archived"`
+	PrettyURL        string             `json:"pretty_url"`
+	KeyResults       []ClickUpKeyResult `json:"key_results"`
+}
+
+// ClickUpKeyResult is a goal's target, which ClickUp's API calls a key result.
+type ClickUpKeyResult struct {
+	ID               string        `json:"id"`
+	GoalID           string        `json:"goal_id"`
+	Name             string        `json:"name"`
+	Type             string        `json:"type"`
+	Unit             string        `json:"unit"`
+	StepsStart       looseNumber   `json:"steps_start"`
+	StepsEnd         looseNumber   `json:"steps_end"`
+	StepsCurrent     looseNumber   `json:"steps_current"`
+	PercentCompleted looseNumber   `json:"percent_completed"`
+	Completed        bool          `json:"completed"`
+	Owners           []ClickUpUser `json:"owners"`
+	TaskIDs          []string      `json:"task_ids"`
+}
+
+// looseNumber decodes numbers ClickUp sends either as numbers or strings.
+type looseNumber float64
+
+func (n *looseNumber) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("number %s: %w", data, err)
+	}
+	*n = looseNumber(f)
+	return nil
+}
+
+// progress is how far along the target is, from 0 to 1.
+func (k ClickUpKeyResult) progress() float64 {
+	var p float64
+	switch k.Type {
+	case targetBoolean:
+		if k.Completed || k.StepsCurrent > 0 {
+			p = 1
+		}
+	case targetAutomatic:
+		p = float64(k.PercentCompleted) / 100
+	default:
+		if k.StepsEnd == k.StepsStart {
+			p = float64(k.PercentCompleted) / 100
+		} else {
+			p = float64(k.StepsCurrent-k.StepsStart) / float64(k.StepsEnd-k.StepsStart)
+		}
+	}
+	return max(0, min(p, 1))
+}
+
+// amount shows a target value in the target's unit.
+func (k ClickUpKeyResult) amount(v looseNumber) string {
+	switch k.Type {
+	case targetCurrency:
+		return strings.TrimSpace(fmt.Sprintf("%s %s", strconv.FormatFloat(float64(v), 'f', 2, 64), k.Unit))
+	case targetPercentage:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64) + "%"
+	}
+	return strings.TrimSpace(strconv.FormatFloat(float64(v), 'f', -1, 64) + " " + k.Unit)
+}
+
+// state describes where the target stands, e.g. "40 / 100 calls".
+func (k ClickUpKeyResult) state() string {
+	switch k.Type {
+	case targetBoolean:
+		if k.progress() == 1 {
+			return "done"
+		}
+		return "not done"
+	case targetAutomatic:
+		return countOf(len(k.TaskIDs), "task")
+	}
+	return k.amount(k.StepsCurrent) + " / " + k.amount(k.StepsEnd)
+}
+
+// ListClickUpGoals returns the workspace's goals with their targets, soonest
+// due first and those without a due date last.
+func ListClickUpGoals(p ClickUpProfile, includeCompleted bool) ([]ClickUpGoal, error) {
+	var out struct {
+		Goals []ClickUpGoal `json:"goals"`
+	}
+	q := url.Values{"include_completed": {strconv.FormatBool(includeCompleted)}}
+	if err := doClickUp(p, http.MethodGet, "/team/"+p.WorkspaceID+"/goal", q, nil, &out); err != nil {
+		return nil, err
+	}
+
+	var goals []ClickUpGoal
+	for _, g := range out.Goals {
+		if g.Archived {
+			continue
+		}
+		// Targets only come with each goal on its own.
+		goal, err := GetClickUpGoal(p, g.ID)
+		if err != nil {
+			return nil, fmt.Errorf("goal %s: %w", g.Name, err)
+		}
+		goals = append(goals, goal)
+	}
+	sort.SliceStable(goals, func(i, j int) bool {
+		a, b := goals[i].DueDate, goals[j].DueDate
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b.Time)
+	})
+	return goals, nil
+}
+
+func GetClickUpGoal(p ClickUpProfile, goalID string) (ClickUpGoal, error) {
+	var out struct {
+		Goal ClickUpGoal `json:"goal"`
+	}
+	err := doClickUp(p, http.MethodGet, "/goal/"+goalID, nil, nil, &out)
+	return out.Goal, err
+}
+
+// UpdateClickUpKeyResult records a target's new current value with an
+// optional note for its history.
+func UpdateClickUpKeyResult(p ClickUpProfile, keyResultID string, current float64, note string) error {
+	in := map[string]any{"steps_current": current}
+	if note != "" {
+		in["note"] = note
+	}
+	return doClickUp(p, http.MethodPut, "/key_result/"+keyResultID, nil, in, nil)
+}
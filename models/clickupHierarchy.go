@@ -0,0 +1,344 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type ClickUpStatus struct {
+	Status     string `json:"status"`
+	Color      string `json:"color"`
+	Type       string `json:"type"`
+	OrderIndex int    `json:"orderindex"`
+}
+
+type ClickUpSpace struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Private  bool            `json:"private"`
+	Statuses []ClickUpStatus `json:"statuses"`
+}
+
+type ClickUpFolder struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Hidden    bool          `json:"hidden"`
+	TaskCount looseCount    `json:"task_count"`
+	Lists     []ClickUpList `json:"lists"`
+}
+
+type ClickUpList struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	TaskCount looseCount `json:"task_count"`
+	Folder    struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Hidden bool   `json:"hidden"`
+	} `json:"folder"`
+	Space struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"space"`
+}
+
+// looseCount decodes task counts, which ClickUp sends as a number for lists
+// and as a string for folders.
+type looseCount int
+
+func (c *looseCount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*c = 0
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("task count %s: %w", data, err)
+	}
+	*c = looseCount(n)
+	return nil
+}
+
+var notArchived = url.Values{"archived": {"false"}}
+
+func ListClickUpSpaces(p ClickUpProfile) ([]ClickUpSpace, error) {
+	var out struct {
+		Spaces []ClickUpSpace `json:"spaces"`
+	}
+	err := doClickUp(p, http.MethodGet, "/team/"+p.WorkspaceID+"/space", notArchived, nil, &out)
+	return out.Spaces, err
+}
+
+func ListClickUpFolders(p ClickUpProfile, spaceID string) ([]ClickUpFolder, error) {
+	var out struct {
+		Folders []ClickUpFolder `json:"folders"`
+	}
+	err := doClickUp(p, http.MethodGet, "/space/"+spaceID+"/folder", notArchived, nil, &out)
+	return out.Folders, err
+}
+
+// ListClickUpFolderlessLists returns the lists that sit directly in a space.
+func ListClickUpFolderlessLists(p ClickUpProfile, spaceID string) ([]ClickUpList, error) {
+	var out struct {
+		Lists []ClickUpList `json:"lists"`
+	}
+	err := doClickUp(p, http.MethodGet, "/space/"+spaceID+"/list", notArchived, nil, &out)
+	return out.Lists, err
+}
+
+func ListClickUpLists(p ClickUpProfile, folderID string) ([]ClickUpList, error) {
+	var out struct {
+		Lists []ClickUpList `json:"lists"`
+	}
+	err := doClickUp(p, http.MethodGet, "/folder/"+folderID+"/list", notArchived, nil, &out)
+	return out.Lists, err
+}
+
+// ListAllClickUpLists walks every space in the workspace for its lists, with
+// Space and Folder filled in so they can be told apart by path.
+func ListAllClickUpLists(p ClickUpProfile) ([]ClickUpList, error) {
+	spaces, err := ListClickUpSpaces(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []ClickUpList
+	for _, s := range spaces {
+		folders, err := ListClickUpFolders(p, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		lists, err := ListClickUpFolderlessLists(p, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range folders {
+			if f.Hidden {
+				continue
+			}
+			for _, l := range f.Lists {
+				l.Folder.ID, l.Folder.Name = f.ID, f.Name
+				lists = append(lists, l)
+			}
+		}
+		for _, l := range lists {
+			l.Space.ID, l.Space.Name = s.ID, s.Name
+			all = append(all, l)
+		}
+	}
+	return all, nil
+}
+
+// path names a list by where it sits, e.g. "Engineering › Sprints › Sprint 12".
+func (l ClickUpList) path() string {
+	parts := []string{l.Space.Name}
+	if l.Folder.Name != "" && !l.Folder.Hidden && l.Folder.Name != "hidden" {
+		parts = append(parts, l.Folder.Name)
+	}
+	return strings.Join(append(parts, l.Name), " › ")
+}
+
+// ListClickUpTaskPage returns one page of up to 100 tasks in a list and
+// whether it was the last one.
+func ListClickUpTaskPage(p ClickUpProfile, listID string, page int) ([]ClickUpTask, bool, error) {
+	var out struct {
+		Tasks    []ClickUpTask `json:"tasks"`
+		LastPage bool          `json:"last_page"`
+	}
+	q := url.Values{"page": {strconv.Itoa(page)}, "subtasks": {"true"}}
+	err := doClickUp(p, http.MethodGet, "/list/"+listID+"/task", q, nil, &out)
+	return out.Tasks, out.LastPage, err
+}
+
+// ClickUpShared is what other members shared with the user outside the
+// spaces they belong to. Tasks may come as IDs or as task objects.
+type ClickUpShared struct {
+	Tasks   []json.RawMessage `json:"tasks"`
+	Lists   []ClickUpList     `json:"lists"`
+	Folders []ClickUpFolder   `json:"folders"`
+}
+
+func GetClickUpShared(p ClickUpProfile) (ClickUpShared, error) {
+	var out struct {
+		Shared ClickUpShared `json:"shared"`
+	}
+	err := doClickUp(p, http.MethodGet, "/team/"+p.WorkspaceID+"/shared", nil, nil, &out)
+	return out.Shared, err
+}
+
+func GetClickUpTask(p ClickUpProfile, taskID string) (ClickUpTask, error) {
+	var task ClickUpTask
+	err := doClickUp(p, http.MethodGet, "/task/"+taskID, nil, nil, &task)
+	return task, err
+}
+
+type clickUpNodeKind int
+
+const (
+	clickUpWorkspaceNode clickUpNodeKind = iota
+	clickUpSharedNode
+	clickUpSpaceNode
+	clickUpFolderNode
+	clickUpListNode
+	clickUpTaskNode
+	// clickUpMoreNode stands in for the next page of tasks in a list.
+	clickUpMoreNode
+)
+
+func (k clickUpNodeKind) String() string {
+	return [...]string{"Workspace", "Shared", "Space", "Folder", "List", "Task", ""}[k]
+}
+
+// ClickUpNode is one entry in the hierarchy navigator.
+type ClickUpNode struct {
+	Kind   clickUpNodeKind
+	ID     string
+	Name   string
+	Detail string
+	// Page is the task page a more node loads.
+	Page int
+	// Raw holds the task behind a task node.
+	Raw *ClickUpTask
+}
+
+// key identifies a node's children in the navigator cache.
+func (n ClickUpNode) key() string {
+	return fmt.Sprintf("%d/%s", n.Kind, n.ID)
+}
+
+func (n ClickUpNode) leaf() bool {
+	return n.Kind == clickUpTaskNode
+}
+
+func folderNode(f ClickUpFolder) ClickUpNode {
+	return ClickUpNode{Kind: clickUpFolderNode, ID: f.ID, Name: f.Name, Detail: countOf(len(f.Lists), "list")}
+}
+
+func listNode(l ClickUpList) ClickUpNode {
+	return ClickUpNode{Kind: clickUpListNode, ID: l.ID, Name: l.Name, Detail: countOf(int(l.TaskCount), "task")}
+}
+
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func taskNode(t ClickUpTask) ClickUpNode {
+	var names []string
+	for _, a := range t.Assignees {
+		names = append(names, a.Username)
+	}
+	detail := t.Status.Status
+	if len(names) > 0 {
+		detail += " • " + strings.Join(names, ", ")
+	}
+	name := t.Name
+	if t.Parent != "" {
+		name = "↳ " + name
+	}
+	return ClickUpNode{Kind: clickUpTaskNode, ID: t.ID, Name: name, Detail: detail, Raw: &t}
+}
+
+// taskPageNodes turns a page of tasks into nodes, ending with a more node
+// when the list has further pages.
+func taskPageNodes(p ClickUpProfile, listID string, page int) ([]ClickUpNode, error) {
+	tasks, last, err := ListClickUpTaskPage(p, listID, page)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]ClickUpNode, 0, len(tasks)+1)
+	for _, t := range tasks {
+		nodes = append(nodes, taskNode(t))
+	}
+	if !last && len(tasks) > 0 {
+		nodes = append(nodes, ClickUpNode{Kind: clickUpMoreNode, ID: listID, Name: "… load more tasks", Page: page + 1})
+	}
+	return nodes, nil
+}
+
+// ClickUpChildren loads what sits below a node in the hierarchy.
+func ClickUpChildren(p ClickUpProfile, n ClickUpNode) ([]ClickUpNode, error) {
+	var nodes []ClickUpNode
+
+	switch n.Kind {
+	case clickUpWorkspaceNode:
+		spaces, err := ListClickUpSpaces(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range spaces {
+			detail := ""
+			if s.Private {
+				detail = "private"
+			}
+			nodes = append(nodes, ClickUpNode{Kind: clickUpSpaceNode, ID: s.ID, Name: s.Name, Detail: detail})
+		}
+		nodes = append(nodes, ClickUpNode{Kind: clickUpSharedNode, ID: p.WorkspaceID, Name: "Shared with me"})
+
+	case clickUpSharedNode:
+		shared, err := GetClickUpShared(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range shared.Folders {
+			nodes = append(nodes, folderNode(f))
+		}
+		for _, l := range shared.Lists {
+			nodes = append(nodes, listNode(l))
+		}
+		for _, raw := range shared.Tasks {
+			var t ClickUpTask
+			var id string
+			if json.Unmarshal(raw, &id) == nil {
+				if t, err = GetClickUpTask(p, id); err != nil {
+					return nil, err
+				}
+			} else if err := json.Unmarshal(raw, &t); err != nil {
+				return nil, fmt.Errorf("decoding shared task: %w", err)
+			}
+			nodes = append(nodes, taskNode(t))
+		}
+
+	case clickUpSpaceNode:
+		folders, err := ListClickUpFolders(p, n.ID)
+		if err != nil {
+			return nil, err
+		}
+		lists, err := ListClickUpFolderlessLists(p, n.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range folders {
+			if !f.Hidden {
+				nodes = append(nodes, folderNode(f))
+			}
+		}
+		for _, l := range lists {
+			nodes = append(nodes, listNode(l))
+		}
+
+	case clickUpFolderNode:
+		lists, err := ListClickUpLists(p, n.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lists {
+			nodes = append(nodes, listNode(l))
+		}
+
+	case clickUpListNode:
+		return taskPageNodes(p, n.ID, 0)
+
+	case clickUpMoreNode:
+		return taskPageNodes(p, n.ID, n.Page)
+	}
+
+	return nodes, nil
+}
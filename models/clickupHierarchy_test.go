@@ -0,0 +1,157 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newClickUpStandIn serves the recorded responses in testdata/clickup and
+// points the ClickUp client at them. A request for /list/300/task?page=1 is
+// answered from list_300_task_page1.json.
+func newClickUpStandIn(t *testing.T) ClickUpProfile {
+	t.Helper()
+	p := ClickUpProfile{Token: "pk_test", WorkspaceID: "9001"}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != p.Token {
+			http.Error(w, `{"err":"Token invalid","ECODE":"OAUTH_025"}`, http.StatusUnauthorized)
+			return
+		}
+		name := strings.ReplaceAll(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/", "_")
+		if page := r.URL.Query().Get("page"); page != "" {
+			name += "_page" + page
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", "clickup", name+".json"))
+		if err != nil {
+			http.Error(w, `{"err":"Route not found","ECODE":"APP_001"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("CLICKUP_API_URL", srv.URL+"/api/v2")
+	return p
+}
+
+// nodeSummary flattens nodes into "Kind ID Name (Detail)" lines for comparison.
+func nodeSummary(nodes []ClickUpNode) []string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		out[i] = strings.TrimSpace(n.Kind.String() + " " + n.ID + " " + n.Name + " (" + n.Detail + ")")
+	}
+	return out
+}
+
+func TestClickUpChildren(t *testing.T) {
+	p := newClickUpStandIn(t)
+
+	tests := []struct {
+		name string
+		node ClickUpNode
+		want []string
+	}{
+		{
+			name: "workspace lists spaces and what was shared",
+			node: ClickUpNode{Kind: clickUpWorkspaceNode, ID: "9001"},
+			want: []string{
+				"Space 100 Engineering ()",
+				"Space 101 Leadership (private)",
+				"Shared 9001 Shared with me ()",
+			},
+		},
+		{
+			name: "space skips hidden folders and adds folderless lists",
+			node: ClickUpNode{Kind: clickUpSpaceNode, ID: "100"},
+			want: []string{
+				"Folder 200 Sprints (2 lists)",
+				"List 303 Backlog (1 task)",
+			},
+		},
+		{
+			name: "folder lists tolerate a null task count",
+			node: ClickUpNode{Kind: clickUpFolderNode, ID: "200"},
+			want: []string{
+				"List 300 Sprint 12 (3 tasks)",
+				"List 301 Sprint 13 (0 tasks)",
+			},
+		},
+		{
+			name: "shared resolves task IDs and task objects",
+			node: ClickUpNode{Kind: clickUpSharedNode, ID: "9001"},
+			want: []string{
+				"Folder 202 Partners (0 lists)",
+				"List 304 Launch checklist (2 tasks)",
+				"Task t9 Renew the certificate (blocked • lee)",
+				"Task t8 Review the design doc (to do)",
+			},
+		},
+		{
+			name: "list ends its first page with a more node",
+			node: ClickUpNode{Kind: clickUpListNode, ID: "300"},
+			want: []string{
+				"Task t1 Rotate S3 keys (in progress • sam, ada)",
+				"Task t2 ↳ Audit bucket policies (to do)",
+				"300 … load more tasks ()",
+			},
+		},
+		{
+			name: "more node loads the last page",
+			node: ClickUpNode{Kind: clickUpMoreNode, ID: "300", Page: 1},
+			want: []string{
+				"Task t3 Ship the release (done)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := ClickUpChildren(p, tt.node)
+			if err != nil {
+				t.Fatalf("ClickUpChildren: %v", err)
+			}
+			got := nodeSummary(nodes)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("nodes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestClickUpChildrenPaging(t *testing.T) {
+	p := newClickUpStandIn(t)
+
+	first, err := ClickUpChildren(p, ClickUpNode{Kind: clickUpListNode, ID: "300"})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	more := first[len(first)-1]
+	if more.Kind != clickUpMoreNode || more.Page != 1 {
+		t.Fatalf("last node = %+v, want a more node for page 1", more)
+	}
+
+	next, err := ClickUpChildren(p, more)
+	if err != nil {
+		t.Fatalf("next page: %v", err)
+	}
+	if last := next[len(next)-1]; last.Kind == clickUpMoreNode {
+		t.Errorf("last page ends with a more node: %+v", last)
+	}
+	if next[0].Raw == nil || next[0].Raw.ID != "t3" {
+		t.Errorf("first task of page 1 = %+v, want t3", next[0].Raw)
+	}
+}
+
+func TestClickUpChildrenError(t *testing.T) {
+	p := newClickUpStandIn(t)
+	p.Token = "pk_revoked"
+
+	_, err := ClickUpChildren(p, ClickUpNode{Kind: clickUpWorkspaceNode, ID: "9001"})
+	if err == nil || !strings.Contains(err.Error(), "Token invalid") {
+		t.Fatalf("err = %v, want the ClickUp error message", err)
+	}
+}
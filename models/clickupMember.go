@@ -0,0 +1,438 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Workspace roles, as ClickUp numbers them.
+const (
+	roleOwner  = 1
+	roleAdmin  = 2
+	roleMember = 3
+	roleGuest  = 4
+)
+
+// guestPermissionLevels are what a guest can be given on a folder, list or
+// task, least first.
+var guestPermissionLevels = []string{"read", "comment", "edit", "create"}
+
+func roleName(role int) string {
+	switch role {
+	case roleOwner:
+		return "owner"
+	case roleAdmin:
+		return "admin"
+	case roleMember:
+		return "member"
+	case roleGuest:
+		return "guest"
+	}
+	return "role " + strconv.Itoa(role)
+}
+
+// ClickUpMember is a person in the workspace, guests included.
+type ClickUpMember struct {
+	ClickUpUser
+	Role        int         `json:"role"`
+	LastActive  clickUpTime `json:"last_active"`
+	DateJoined  clickUpTime `json:"date_joined"`
+	DateInvited clickUpTime `json:"date_invited"`
+	CustomRole  *struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"custom_role"`
+}
+
+// role names the member's role, preferring a custom one.
+func (m ClickUpMember) role() string {
+	if m.CustomRole != nil && m.CustomRole.Name != "" {
+		return m.CustomRole.Name
+	}
+	return roleName(m.Role)
+}
+
+// pending reports whether the member was invited but hasn't joined yet.
+func (m ClickUpMember) pending() bool {
+	return m.DateJoined.IsZero() && !m.DateInvited.IsZero()
+}
+
+// ListClickUpPeople returns everyone in the profile's workspace, owners and
+// admins first and guests last.
+func ListClickUpPeople(p ClickUpProfile) ([]ClickUpMember, error) {
+	var out struct {
+		Teams []struct {
+			ID      string `json:"id"`
+			Members []struct {
+				User ClickUpMember `json:"user"`
+			} `json:"members"`
+		} `json:"teams"`
+	}
+	if err := doClickUp(p, http.MethodGet, "/team", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	for _, t := range out.Teams {
+		if t.ID != p.WorkspaceID {
+			continue
+		}
+		people := make([]ClickUpMember, len(t.Members))
+		for i, m := range t.Members {
+			people[i] = m.User
+		}
+		sort.SliceStable(people, func(i, j int) bool {
+			if people[i].Role != people[j].Role {
+				return people[i].Role < people[j].Role
+			}
+			return strings.ToLower(people[i].Username) < strings.ToLower(people[j].Username)
+		})
+		return people, nil
+	}
+	return nil, fmt.Errorf("workspace %s not found", p.WorkspaceID)
+}
+
+// ClickUpGuestPermissions are the workspace-wide abilities of a guest.
+type ClickUpGuestPermissions struct {
+	CanEditTags         bool `json:"can_edit_tags"`
+	CanSeeTimeSpent     bool `json:"can_see_time_spent"`
+	CanSeeTimeEstimated bool `json:"can_see_time_estimated"`
+	CanCreateViews      bool `json:"can_create_views"`
+}
+
+// ClickUpGuestShare is a folder, list or task shared with a guest.
+type ClickUpGuestShare struct {
+	Kind            string
+	ID              string
+	Name            string
+	PermissionLevel string
+}
+
+func (s ClickUpGuestShare) location() string {
+	return s.Kind + " " + s.ID
+}
+
+// UnmarshalJSON takes a shared item as an object or, for tasks, a bare ID.
+func (s *ClickUpGuestShare) UnmarshalJSON(data []byte) error {
+	var id string
+	if json.Unmarshal(data, &id) == nil {
+		s.ID = id
+		return nil
+	}
+	var item struct {
+		ID              looseID `json:"id"`
+		Name            string  `json:"name"`
+		PermissionLevel string  `json:"permission_level"`
+	}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return fmt.Errorf("decoding shared item: %w", err)
+	}
+	s.ID, s.Name, s.PermissionLevel = string(item.ID), item.Name, item.PermissionLevel
+	return nil
+}
+
+type ClickUpGuest struct {
+	User      ClickUpMember `json:"user"`
+	InvitedBy ClickUpUser   `json:"invited_by"`
+	ClickUpGuestPermissions
+	Shared struct {
+		Folders []ClickUpGuestShare `json:"folders"`
+		Lists   []ClickUpGuestShare `json:"lists"`
+		Tasks   []ClickUpGuestShare `json:"tasks"`
+	} `json:"shared"`
+}
+
+// shares lists what is shared with the guest, folders first.
+func (g ClickUpGuest) shares() []ClickUpGuestShare {
+	var shares []ClickUpGuestShare
+	for kind, items := range map[string][]ClickUpGuestShare{"folder": g.Shared.Folders, "list": g.Shared.Lists, "task": g.Shared.Tasks} {
+		for _, s := range items {
+			s.Kind = kind
+			shares = append(shares, s)
+		}
+	}
+	order := map[string]int{"folder": 0, "list": 1, "task": 2}
+	sort.SliceStable(shares, func(i, j int) bool {
+		if shares[i].Kind != shares[j].Kind {
+			return order[shares[i].Kind] < order[shares[j].Kind]
+		}
+		return strings.ToLower(shares[i].Name) < strings.ToLower(shares[j].Name)
+	})
+	return shares
+}
+
+// level is the guest's permission level on location, or "" without access.
+func (g ClickUpGuest) level(kind string, id string) string {
+	for _, s := range g.shares() {
+		if s.Kind == kind && s.ID == id {
+			if s.PermissionLevel == "" {
+				return "shared"
+			}
+			return s.PermissionLevel
+		}
+	}
+	return ""
+}
+
+func guestPath(p ClickUpProfile, guestID int) string {
+	return "/team/" + p.WorkspaceID + "/guest/" + strconv.Itoa(guestID)
+}
+
+func GetClickUpGuest(p ClickUpProfile, guestID int) (ClickUpGuest, error) {
+	var out struct {
+		Guest ClickUpGuest `json:"guest"`
+	}
+	err := doClickUp(p, http.MethodGet, guestPath(p, guestID), nil, nil, &out)
+	return out.Guest, err
+}
+
+// InviteClickUpGuest invites email to the workspace as a guest and returns
+// their user ID, which is what sharing with them takes.
+func InviteClickUpGuest(p ClickUpProfile, email string, perms ClickUpGuestPermissions) (int, error) {
+	in := struct {
+		Email string `json:"email"`
+		ClickUpGuestPermissions
+	}{email, perms}
+	if err := doClickUp(p, http.MethodPost, "/team/"+p.WorkspaceID+"/guest", nil, in, nil); err != nil {
+		return 0, err
+	}
+
+	// The reply is the whole workspace, so look the guest up by email.
+	people, err := ListClickUpPeople(p)
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range people {
+		if strings.EqualFold(m.Email, email) {
+			return m.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("invited %s, but they aren't listed in the workspace", email)
+}
+
+func EditClickUpGuest(p ClickUpProfile, guestID int, perms ClickUpGuestPermissions) error {
+	return doClickUp(p, http.MethodPut, guestPath(p, guestID), nil, perms, nil)
+}
+
+// RemoveClickUpGuest takes the guest out of the workspace and everything
+// shared with them.
+func RemoveClickUpGuest(p ClickUpProfile, guestID int) error {
+	return doClickUp(p, http.MethodDelete, guestPath(p, guestID), nil, nil, nil)
+}
+
+// parseAccessLocation reads "space ID", "folder ID", "list ID" or "task ID".
+func parseAccessLocation(s string) (string, string, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 2 {
+		switch kind := strings.ToLower(fields[0]); kind {
+		case "space", "folder", "list", "task":
+			return kind, fields[1], nil
+		}
+	}
+	return "", "", fmt.Errorf("type space ID, folder ID, list ID or task ID")
+}
+
+// ShareWithClickUpGuest gives a guest access to a folder, list or task at a
+// permission level from guestPermissionLevels.
+func ShareWithClickUpGuest(p ClickUpProfile, guestID int, location string, level string) error {
+	kind, id, err := parseAccessLocation(location)
+	if err != nil {
+		return err
+	}
+	if kind == "space" {
+		return fmt.Errorf("guests can't be given a whole space; share its folders or lists")
+	}
+	in := map[string]string{"permission_level": level}
+	return doClickUp(p, http.MethodPost, "/"+kind+"/"+id+"/guest/"+strconv.Itoa(guestID), nil, in, nil)
+}
+
+func UnshareWithClickUpGuest(p ClickUpProfile, guestID int, location string) error {
+	kind, id, err := parseAccessLocation(location)
+	if err != nil {
+		return err
+	}
+	return doClickUp(p, http.MethodDelete, "/"+kind+"/"+id+"/guest/"+strconv.Itoa(guestID), nil, nil, nil)
+}
+
+// ClickUpGroup is a user group, which ClickUp's API calls a team.
+type ClickUpGroup struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Handle  string        `json:"handle"`
+	Members []ClickUpUser `json:"members"`
+}
+
+func ListClickUpGroups(p ClickUpProfile) ([]ClickUpGroup, error) {
+	var out struct {
+		Groups []ClickUpGroup `json:"groups"`
+	}
+	q := url.Values{"team_id": {p.WorkspaceID}}
+	if err := doClickUp(p, http.MethodGet, "/group", q, nil, &out); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out.Groups, func(i, j int) bool {
+		return strings.ToLower(out.Groups[i].Name) < strings.ToLower(out.Groups[j].Name)
+	})
+	return out.Groups, nil
+}
+
+func CreateClickUpGroup(p ClickUpProfile, name string, handle string, members []int) error {
+	in := map[string]any{"name": name, "members": members}
+	if handle != "" {
+		in["handle"] = handle
+	}
+	return doClickUp(p, http.MethodPost, "/team/"+p.WorkspaceID+"/group", nil, in, nil)
+}
+
+// UpdateClickUpGroup renames a group and brings its members in line with
+// members, adding and removing only the difference.
+func UpdateClickUpGroup(p ClickUpProfile, g ClickUpGroup, name string, handle string, members []int) error {
+	had := make(map[int]bool)
+	for _, u := range g.Members {
+		had[u.ID] = true
+	}
+	add, rem := []int{}, []int{}
+	for _, id := range members {
+		if !had[id] {
+			add = append(add, id)
+		}
+		delete(had, id)
+	}
+	for id := range had {
+		rem = append(rem, id)
+	}
+	sort.Ints(rem)
+
+	in := map[string]any{
+		"name":    name,
+		"handle":  handle,
+		"members": map[string][]int{"add": add, "rem": rem},
+	}
+	return doClickUp(p, http.MethodPut, "/group/"+g.ID, nil, in, nil)
+}
+
+func DeleteClickUpGroup(p ClickUpProfile, groupID string) error {
+	return doClickUp(p, http.MethodDelete, "/group/"+groupID, nil, nil, nil)
+}
+
+// ClickUpCustomRole is a role defined on top of one of the built-in ones.
+type ClickUpCustomRole struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	InheritedRole int           `json:"inherited_role"`
+	Members       []ClickUpUser `json:"members"`
+}
+
+// ListClickUpCustomRoles returns the workspace's custom roles, which only
+// Enterprise workspaces have.
+func ListClickUpCustomRoles(p ClickUpProfile) ([]ClickUpCustomRole, error) {
+	var out struct {
+		Roles []ClickUpCustomRole `json:"custom_roles"`
+	}
+	q := url.Values{"include_members": {"true"}}
+	err := doClickUp(p, http.MethodGet, "/team/"+p.WorkspaceID+"/customroles", q, nil, &out)
+	return out.Roles, err
+}
+
+// ClickUpGuestAccess is a guest's permission level on a location.
+type ClickUpGuestAccess struct {
+	Guest ClickUpMember
+	Level string
+}
+
+// ClickUpAccess is who can get into a space, folder, list or task.
+type ClickUpAccess struct {
+	Kind    string
+	ID      string
+	Name    string
+	Private bool
+	// Members is everyone ClickUp lists with access; it is only known for
+	// spaces, lists and tasks.
+	Members []ClickUpUser
+	Guests  []ClickUpGuestAccess
+}
+
+func (a ClickUpAccess) location() string {
+	return a.Kind + " " + a.ID
+}
+
+// GetClickUpAccess looks up who can get into location. Guests' levels come
+// from each guest in turn, as ClickUp has no listing by location.
+func GetClickUpAccess(p ClickUpProfile, location string, people []ClickUpMember) (ClickUpAccess, error) {
+	kind, id, err := parseAccessLocation(location)
+	if err != nil {
+		return ClickUpAccess{}, err
+	}
+	a := ClickUpAccess{Kind: kind, ID: id}
+
+	var members struct {
+		Members []ClickUpUser `json:"members"`
+	}
+	switch kind {
+	case "space":
+		var space struct {
+			Name    string `json:"name"`
+			Private bool   `json:"private"`
+			Members []struct {
+				User ClickUpUser `json:"user"`
+			} `json:"members"`
+		}
+		if err := doClickUp(p, http.MethodGet, "/space/"+id, nil, nil, &space); err != nil {
+			return a, err
+		}
+		a.Name, a.Private = space.Name, space.Private
+		for _, m := range space.Members {
+			a.Members = append(a.Members, m.User)
+		}
+	case "folder":
+		var folder struct {
+			Name string `json:"name"`
+		}
+		if err := doClickUp(p, http.MethodGet, "/folder/"+id, nil, nil, &folder); err != nil {
+			return a, err
+		}
+		a.Name = folder.Name
+	case "list":
+		var list struct {
+			Name string `json:"name"`
+		}
+		if err := doClickUp(p, http.MethodGet, "/list/"+id, nil, nil, &list); err != nil {
+			return a, err
+		}
+		a.Name = list.Name
+		if err := doClickUp(p, http.MethodGet, "/list/"+id+"/member", nil, nil, &members); err != nil {
+			return a, err
+		}
+		a.Members = members.Members
+	case "task":
+		task, err := GetClickUpTask(p, id)
+		if err != nil {
+			return a, err
+		}
+		a.Name = task.Name
+		if err := doClickUp(p, http.MethodGet, "/task/"+id+"/member", nil, nil, &members); err != nil {
+			return a, err
+		}
+		a.Members = members.Members
+	}
+
+	if kind == "space" {
+		return a, nil
+	}
+	for _, m := range people {
+		if m.Role != roleGuest {
+			continue
+		}
+		guest, err := GetClickUpGuest(p, m.ID)
+		if err != nil {
+			return a, fmt.Errorf("guest %s: %w", m.Email, err)
+		}
+		if level := guest.level(kind, id); level != "" {
+			a.Guests = append(a.Guests, ClickUpGuestAccess{Guest: m, Level: level})
+		}
+	}
+	return a, nil
+}
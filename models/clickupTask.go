@@ -0,0 +1,643 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clickUpTaskPageSize is how many tasks ClickUp returns per page.
+const clickUpTaskPageSize = 100
+
+type ClickUpPriority struct {
+	ID       string `json:"id"`
+	Priority string `json:"priority"`
+	Color    string `json:"color"`
+}
+
+type ClickUpTag struct {
+	Name string `json:"name"`
+}
+
+type ClickUpFieldOption struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Label      string `json:"label"`
+	OrderIndex int    `json:"orderindex"`
+}
+
+type ClickUpCustomField struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	TypeConfig struct {
+		Options []ClickUpFieldOption `json:"options"`
+	} `json:"type_config"`
+	Value any `json:"value,omitempty"`
+}
+
+type ClickUpChecklistItem struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Resolved   bool         `json:"resolved"`
+	OrderIndex float64      `json:"orderindex"`
+	Assignee   *ClickUpUser `json:"assignee"`
+}
+
+type ClickUpChecklist struct {
+	ID    string                 `json:"id"`
+	Name  string                 `json:"name"`
+	Items []ClickUpChecklistItem `json:"items"`
+}
+
+// ClickUpDependency says TaskID waits on DependsOn.
+type ClickUpDependency struct {
+	TaskID    string `json:"task_id"`
+	DependsOn string `json:"depends_on"`
+}
+
+type ClickUpLink struct {
+	TaskID string `json:"task_id"`
+	LinkID string `json:"link_id"`
+}
+
+type ClickUpTask struct {
+	ID           string               `json:"id"`
+	CustomID     string               `json:"custom_id"`
+	Name         string               `json:"name"`
+	Status       ClickUpStatus        `json:"status"`
+	Assignees    []ClickUpUser        `json:"assignees"`
+	Priority     *ClickUpPriority     `json:"priority"`
+	DueDate      clickUpTime          `json:"due_date"`
+	DateCreated  clickUpTime          `json:"date_created"`
+	DateUpdated  clickUpTime          `json:"date_updated"`
+	Tags         []ClickUpTag         `json:"tags"`
+	CustomFields []ClickUpCustomField `json:"custom_fields"`
+	Parent       string               `json:"parent"`
+	URL          string               `json:"url"`
+	// The fields below are only filled in by GetClickUpTaskDetail.
+	MarkdownDescription string              `json:"markdown_description"`
+	TextContent         string              `json:"text_content"`
+	StartDate           clickUpTime         `json:"start_date"`
+	Subtasks            []ClickUpTask       `json:"subtasks"`
+	Checklists          []ClickUpChecklist  `json:"checklists"`
+	Dependencies        []ClickUpDependency `json:"dependencies"`
+	LinkedTasks         []ClickUpLink       `json:"linked_tasks"`
+	Attachments         []ClickUpAttachment `json:"attachments"`
+	List                struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"list"`
+}
+
+// clickUpTime decodes ClickUp timestamps, which are Unix milliseconds sent as
+// strings and null when unset.
+type clickUpTime struct {
+	time.Time
+}
+
+func (t *clickUpTime) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("timestamp %s: %w", data, err)
+	}
+	t.Time = time.UnixMilli(ms)
+	return nil
+}
+
+func (t clickUpTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(strconv.FormatInt(t.UnixMilli(), 10))
+}
+
+// clickUpPriorities are ClickUp's priority names by their numeric value.
+var clickUpPriorities = []string{"", "urgent", "high", "normal", "low"}
+
+// ClickUpFieldFilter matches a custom field, e.g. {Estimate > 3}.
+type ClickUpFieldFilter struct {
+	FieldID  string `json:"field_id"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// ClickUpTaskFilter selects tasks in one list, or across the workspace when
+// ListID is empty. Priorities are matched after the page arrives because the
+// API can't filter on them.
+type ClickUpTaskFilter struct {
+	ListID        string
+	Assignees     []int
+	Statuses      []string
+	Priorities    []int
+	DueAfter      time.Time
+	DueBefore     time.Time
+	Tags          []string
+	CustomFields  []ClickUpFieldFilter
+	IncludeClosed bool
+	// OrderBy is created, updated or due_date.
+	OrderBy string
+	Reverse bool
+	Page    int
+}
+
+func (f ClickUpTaskFilter) query() url.Values {
+	q := url.Values{
+		"page":     {strconv.Itoa(f.Page)},
+		"subtasks": {"true"},
+	}
+	if f.OrderBy != "" {
+		q.Set("order_by", f.OrderBy)
+	}
+	if f.Reverse {
+		q.Set("reverse", "true")
+	}
+	if f.IncludeClosed {
+		q.Set("include_closed", "true")
+	}
+	for _, a := range f.Assignees {
+		q.Add("assignees[]", strconv.Itoa(a))
+	}
+	for _, s := range f.Statuses {
+		q.Add("statuses[]", s)
+	}
+	for _, t := range f.Tags {
+		q.Add("tags[]", t)
+	}
+	if !f.DueAfter.IsZero() {
+		q.Set("due_date_gt", strconv.FormatInt(f.DueAfter.UnixMilli(), 10))
+	}
+	if !f.DueBefore.IsZero() {
+		q.Set("due_date_lt", strconv.FormatInt(f.DueBefore.UnixMilli(), 10))
+	}
+	if len(f.CustomFields) > 0 {
+		data, _ := json.Marshal(f.CustomFields)
+		q.Set("custom_fields", string(data))
+	}
+	return q
+}
+
+// ListClickUpTasks returns one page of tasks matching f and whether it was
+// the last page.
+func ListClickUpTasks(p ClickUpProfile, f ClickUpTaskFilter) ([]ClickUpTask, bool, error) {
+	path := "/team/" + p.WorkspaceID + "/task"
+	if f.ListID != "" {
+		path = "/list/" + f.ListID + "/task"
+	}
+
+	var out struct {
+		Tasks    []ClickUpTask `json:"tasks"`
+		LastPage bool          `json:"last_page"`
+	}
+	if err := doClickUp(p, http.MethodGet, path, f.query(), nil, &out); err != nil {
+		return nil, false, err
+	}
+	last := out.LastPage || len(out.Tasks) < clickUpTaskPageSize
+
+	if len(f.Priorities) == 0 {
+		return out.Tasks, last, nil
+	}
+	tasks := out.Tasks[:0]
+	for _, t := range out.Tasks {
+		if slices.Contains(f.Priorities, t.priority()) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, last, nil
+}
+
+// priority is the task's numeric priority, 0 when it has none.
+func (t ClickUpTask) priority() int {
+	if t.Priority == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(t.Priority.ID)
+	return n
+}
+
+// ListClickUpFields returns the custom fields usable in a list, or across the
+// workspace when listID is empty.
+func ListClickUpFields(p ClickUpProfile, listID string) ([]ClickUpCustomField, error) {
+	path := "/team/" + p.WorkspaceID + "/field"
+	if listID != "" {
+		path = "/list/" + listID + "/field"
+	}
+	var out struct {
+		Fields []ClickUpCustomField `json:"fields"`
+	}
+	err := doClickUp(p, http.MethodGet, path, nil, nil, &out)
+	return out.Fields, err
+}
+
+// ClickUpMembers returns the members of the profile's workspace.
+func ClickUpMembers(p ClickUpProfile) ([]ClickUpUser, error) {
+	workspaces, err := ListClickUpWorkspaces(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workspaces {
+		if w.ID != p.WorkspaceID {
+			continue
+		}
+		members := make([]ClickUpUser, len(w.Members))
+		for i, m := range w.Members {
+			members[i] = m.User
+		}
+		return members, nil
+	}
+	return nil, fmt.Errorf("workspace %s not found", p.WorkspaceID)
+}
+
+// resolveClickUpMember finds a member by "me", ID, username or email.
+func resolveClickUpMember(members []ClickUpUser, p ClickUpProfile, name string) (int, error) {
+	if strings.EqualFold(name, "me") {
+		return p.UserID, nil
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	for _, u := range members {
+		if strings.EqualFold(u.Username, name) || strings.EqualFold(u.Email, name) {
+			return u.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("no member named %q", name)
+}
+
+// startOfWeek is the Monday midnight starting t's week.
+func startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// parseDueFilter reads today, this week, next week, overdue, before DATE or
+// after DATE into a due date range; zero times leave that end open.
+func parseDueFilter(s string, now time.Time) (after time.Time, before time.Time, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "":
+		return
+	case "today":
+		return today.Add(-time.Millisecond), today.AddDate(0, 0, 1), nil
+	case "this week", "week":
+		start := startOfWeek(now)
+		return start.Add(-time.Millisecond), start.AddDate(0, 0, 7), nil
+	case "next week":
+		start := startOfWeek(now).AddDate(0, 0, 7)
+		return start.Add(-time.Millisecond), start.AddDate(0, 0, 7), nil
+	case "overdue":
+		return time.Time{}, now, nil
+	}
+
+	word, date, ok := strings.Cut(s, " ")
+	if ok && (word == "before" || word == "after") {
+		d, perr := time.ParseInLocation("2006-01-02", strings.TrimSpace(date), now.Location())
+		if perr != nil {
+			return after, before, fmt.Errorf("due dates look like 2024-05-31")
+		}
+		if word == "before" {
+			return time.Time{}, d, nil
+		}
+		return d.AddDate(0, 0, 1).Add(-time.Millisecond), time.Time{}, nil
+	}
+	return after, before, fmt.Errorf("due: use today, this week, next week, overdue, before DATE or after DATE")
+}
+
+// parseNaturalDate reads a date such as today, tomorrow, friday, next friday,
+// in 3 days, next week, end of month, may 31 or 2024-05-31. A bare weekday is
+// the next one to come; next WEEKDAY is that day in the following week.
+func parseNaturalDate(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "next week":
+		return startOfWeek(now).AddDate(0, 0, 7), nil
+	case "end of week":
+		return startOfWeek(now).AddDate(0, 0, 4), nil
+	case "end of month":
+		return time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()), nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "in "); ok {
+		count, unit, _ := strings.Cut(rest, " ")
+		n, err := strconv.Atoi(count)
+		if err == nil {
+			switch strings.TrimSuffix(unit, "s") {
+			case "day":
+				return today.AddDate(0, 0, n), nil
+			case "week":
+				return today.AddDate(0, 0, 7*n), nil
+			case "month":
+				return today.AddDate(0, n, 0), nil
+			}
+		}
+	}
+
+	next := false
+	day := s
+	if rest, ok := strings.CutPrefix(s, "next "); ok {
+		next, day = true, rest
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if day != name && day != name[:3] {
+			continue
+		}
+		if next {
+			// Monday-based like startOfWeek, so next sunday is the one after next saturday.
+			return startOfWeek(now).AddDate(0, 0, 7+(int(wd)+6)%7), nil
+		}
+		ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead), nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "Jan 2", "January 2", "2 Jan", "2 January", "Jan 2 2006", "January 2 2006"} {
+		d, err := time.ParseInLocation(layout, s, now.Location())
+		if err != nil {
+			continue
+		}
+		if strings.Contains(layout, "2006") {
+			return d, nil
+		}
+		// Without a year, take the next time that date comes round. Feb 29
+		// only comes round in a leap year; in any other it would roll over
+		// to Mar 1.
+		for year := now.Year(); ; year++ {
+			next := time.Date(year, d.Month(), d.Day(), 0, 0, 0, 0, now.Location())
+			if next.Month() == d.Month() && !next.Before(today) {
+				return next, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a date; try tomorrow, next friday, in 3 days or 2024-05-31", s)
+}
+
+// parseFieldFilter reads "Name op value" against the available custom fields.
+func parseFieldFilter(s string, fields []ClickUpCustomField) (ClickUpFieldFilter, error) {
+	for _, op := range []string{"!=", ">=", "<=", "=", ">", "<", " is not set", " is set"} {
+		name, value, ok := strings.Cut(s, op)
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		i := slices.IndexFunc(fields, func(f ClickUpCustomField) bool { return strings.EqualFold(f.Name, name) })
+		if i < 0 {
+			return ClickUpFieldFilter{}, fmt.Errorf("no custom field named %q", name)
+		}
+
+		filter := ClickUpFieldFilter{FieldID: fields[i].ID, Operator: op}
+		switch op {
+		case " is set":
+			filter.Operator = "IS NOT NULL"
+		case " is not set":
+			filter.Operator = "IS NULL"
+		default:
+			value = strings.TrimSpace(value)
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				filter.Value = n
+			} else {
+				filter.Value = value
+			}
+		}
+		return filter, nil
+	}
+	return ClickUpFieldFilter{}, fmt.Errorf("custom field filters look like Estimate > 3 or Sprint is set")
+}
+
+// splitList splits a comma-separated input, dropping blanks.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// GetClickUpTaskDetail returns a task with its markdown description and subtasks.
+func GetClickUpTaskDetail(p ClickUpProfile, taskID string) (ClickUpTask, error) {
+	var task ClickUpTask
+	q := url.Values{"include_subtasks": {"true"}, "include_markdown_description": {"true"}}
+	err := doClickUp(p, http.MethodGet, "/task/"+taskID, q, nil, &task)
+	return task, err
+}
+
+// ListClickUpStatuses returns the statuses configured for a list, in order.
+func ListClickUpStatuses(p ClickUpProfile, listID string) ([]ClickUpStatus, error) {
+	var out struct {
+		Statuses []ClickUpStatus `json:"statuses"`
+	}
+	if err := doClickUp(p, http.MethodGet, "/list/"+listID, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(out.Statuses, func(a, b ClickUpStatus) int { return a.OrderIndex - b.OrderIndex })
+	return out.Statuses, nil
+}
+
+// UpdateClickUpTask changes the given task properties, e.g. {"status": "done"}.
+// A nil value clears a property such as priority or due_date.
+func UpdateClickUpTask(p ClickUpProfile, taskID string, changes map[string]any) error {
+	return doClickUp(p, http.MethodPut, "/task/"+taskID, nil, changes, nil)
+}
+
+// ClickUpNewTask is what the quick task form sends to create a task.
+type ClickUpNewTask struct {
+	Name                string `json:"name"`
+	MarkdownDescription string `json:"markdown_content,omitempty"`
+	Assignees           []int  `json:"assignees,omitempty"`
+	DueDate             int64  `json:"due_date,omitempty"`
+	DueDateTime         bool   `json:"due_date_time"`
+}
+
+func CreateClickUpTask(p ClickUpProfile, listID string, task ClickUpNewTask) (ClickUpTask, error) {
+	var created ClickUpTask
+	err := doClickUp(p, http.MethodPost, "/list/"+listID+"/task", nil, task, &created)
+	return created, err
+}
+
+func AddClickUpTag(p ClickUpProfile, taskID string, tag string) error {
+	return doClickUp(p, http.MethodPost, "/task/"+taskID+"/tag/"+url.PathEscape(tag), nil, nil, nil)
+}
+
+func RemoveClickUpTag(p ClickUpProfile, taskID string, tag string) error {
+	return doClickUp(p, http.MethodDelete, "/task/"+taskID+"/tag/"+url.PathEscape(tag), nil, nil, nil)
+}
+
+// SetClickUpField sets a custom field on a task; a nil value clears it.
+func SetClickUpField(p ClickUpProfile, taskID string, fieldID string, value any) error {
+	if value == nil {
+		return doClickUp(p, http.MethodDelete, "/task/"+taskID+"/field/"+fieldID, nil, nil, nil)
+	}
+	return doClickUp(p, http.MethodPost, "/task/"+taskID+"/field/"+fieldID, nil, map[string]any{"value": value}, nil)
+}
+
+func CreateClickUpChecklist(p ClickUpProfile, taskID string, name string) error {
+	return doClickUp(p, http.MethodPost, "/task/"+taskID+"/checklist", nil, map[string]any{"name": name}, nil)
+}
+
+func AddClickUpChecklistItem(p ClickUpProfile, checklistID string, name string) error {
+	return doClickUp(p, http.MethodPost, "/checklist/"+checklistID+"/checklist_item", nil, map[string]any{"name": name}, nil)
+}
+
+func ResolveClickUpChecklistItem(p ClickUpProfile, checklistID string, itemID string, resolved bool) error {
+	return doClickUp(p, http.MethodPut, "/checklist/"+checklistID+"/checklist_item/"+itemID, nil, map[string]any{"resolved": resolved}, nil)
+}
+
+func DeleteClickUpChecklistItem(p ClickUpProfile, checklistID string, itemID string) error {
+	return doClickUp(p, http.MethodDelete, "/checklist/"+checklistID+"/checklist_item/"+itemID, nil, nil, nil)
+}
+
+// AddClickUpDependency makes taskID wait on other, or block it when blocking is set.
+func AddClickUpDependency(p ClickUpProfile, taskID string, other string, blocking bool) error {
+	body := map[string]any{"depends_on": other}
+	if blocking {
+		body = map[string]any{"dependency_of": other}
+	}
+	return doClickUp(p, http.MethodPost, "/task/"+taskID+"/dependency", nil, body, nil)
+}
+
+func RemoveClickUpDependency(p ClickUpProfile, dep ClickUpDependency) error {
+	q := url.Values{"depends_on": {dep.DependsOn}}
+	return doClickUp(p, http.MethodDelete, "/task/"+dep.TaskID+"/dependency", q, nil, nil)
+}
+
+func LinkClickUpTasks(p ClickUpProfile, taskID string, other string) error {
+	return doClickUp(p, http.MethodPost, "/task/"+taskID+"/link/"+other, nil, nil, nil)
+}
+
+func UnlinkClickUpTasks(p ClickUpProfile, taskID string, other string) error {
+	return doClickUp(p, http.MethodDelete, "/task/"+taskID+"/link/"+other, nil, nil, nil)
+}
+
+// option finds a drop-down or label option by ID, orderindex or name.
+func (f ClickUpCustomField) option(v any) (ClickUpFieldOption, bool) {
+	for _, o := range f.TypeConfig.Options {
+		switch v := v.(type) {
+		case float64:
+			if o.OrderIndex == int(v) {
+				return o, true
+			}
+		case string:
+			if o.ID == v || strings.EqualFold(o.Name, v) || strings.EqualFold(o.Label, v) {
+				return o, true
+			}
+		}
+	}
+	return ClickUpFieldOption{}, false
+}
+
+// fieldText renders a custom field's value for display.
+func fieldText(f ClickUpCustomField) string {
+	if f.Value == nil {
+		return ""
+	}
+
+	switch f.Type {
+	case "drop_down":
+		if o, ok := f.option(f.Value); ok {
+			return o.Name
+		}
+	case "labels":
+		ids, _ := f.Value.([]any)
+		var names []string
+		for _, id := range ids {
+			if o, ok := f.option(id); ok {
+				names = append(names, o.Label)
+			}
+		}
+		return strings.Join(names, ", ")
+	case "date":
+		var t clickUpTime
+		data, _ := json.Marshal(f.Value)
+		if t.UnmarshalJSON(data) == nil && !t.IsZero() {
+			return t.Local().Format("2006-01-02")
+		}
+	case "users":
+		users, _ := f.Value.([]any)
+		var names []string
+		for _, u := range users {
+			if u, ok := u.(map[string]any); ok {
+				names = append(names, fmt.Sprint(u["username"]))
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+
+	switch v := f.Value.(type) {
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	}
+	data, _ := json.Marshal(f.Value)
+	return string(data)
+}
+
+// fieldValue converts text typed for a custom field into the value ClickUp
+// expects for its type. Blank text clears the field.
+func fieldValue(f ClickUpCustomField, text string) (any, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case "drop_down":
+		if o, ok := f.option(text); ok {
+			return o.ID, nil
+		}
+		var names []string
+		for _, o := range f.TypeConfig.Options {
+			names = append(names, o.Name)
+		}
+		return nil, fmt.Errorf("%s is one of: %s", f.Name, strings.Join(names, ", "))
+	case "labels":
+		var ids []string
+		for _, name := range splitList(text) {
+			o, ok := f.option(name)
+			if !ok {
+				return nil, fmt.Errorf("%s has no label %q", f.Name, name)
+			}
+			ids = append(ids, o.ID)
+		}
+		return ids, nil
+	case "checkbox":
+		return strconv.ParseBool(text)
+	case "number", "currency", "rating", "emoji":
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number", f.Name)
+		}
+		return n, nil
+	case "date":
+		d, err := time.ParseInLocation("2006-01-02", text, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("dates look like 2024-05-31")
+		}
+		return d.UnixMilli(), nil
+	case "text", "short_text", "email", "url", "phone":
+		return text, nil
+	}
+	return nil, fmt.Errorf("%s fields can't be edited here yet", f.Type)
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// taskTestNow is a Wednesday afternoon in a year that is not a leap year.
+var taskTestNow = time.Date(2025, time.May, 14, 15, 30, 0, 0, time.UTC)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseNaturalDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"today", day(2025, time.May, 14)},
+		{"  Tomorrow ", day(2025, time.May, 15)},
+		{"next week", day(2025, time.May, 19)},
+		{"end of week", day(2025, time.May, 16)},
+		{"end of month", day(2025, time.May, 31)},
+		{"in 1 day", day(2025, time.May, 15)},
+		{"in 3 days", day(2025, time.May, 17)},
+		{"in 2 weeks", day(2025, time.May, 28)},
+		{"in 1 month", day(2025, time.June, 14)},
+		{"friday", day(2025, time.May, 16)},
+		{"fri", day(2025, time.May, 16)},
+		{"monday", day(2025, time.May, 19)},
+		{"wednesday", day(2025, time.May, 21)},
+		{"next friday", day(2025, time.May, 23)},
+		{"next monday", day(2025, time.May, 19)},
+		{"next sunday", day(2025, time.May, 25)},
+		{"2024-05-31", day(2024, time.May, 31)},
+		{"may 14", day(2025, time.May, 14)},
+		{"may 31", day(2025, time.May, 31)},
+		{"31 May", day(2025, time.May, 31)},
+		{"may 1", day(2026, time.May, 1)},
+		{"January 2", day(2026, time.January, 2)},
+		{"feb 28", day(2026, time.February, 28)},
+		{"feb 29", day(2028, time.February, 29)},
+		{"feb 29 2028", day(2028, time.February, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseNaturalDate(tt.in, taskTestNow)
+			if err != nil {
+				t.Fatalf("parseNaturalDate: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got.Format("Mon 2006-01-02 15:04"), tt.want.Format("Mon 2006-01-02 15:04"))
+			}
+		})
+	}
+
+	for _, in := range []string{"", "someday", "in three days", "in 2 fortnights", "feb 30", "feb 29 2025"} {
+		if got, err := parseNaturalDate(in, taskTestNow); err == nil {
+			t.Errorf("parseNaturalDate(%q) = %s, want an error", in, got)
+		}
+	}
+}
+
+func TestParseNaturalDateFeb29InLeapYear(t *testing.T) {
+	now := time.Date(2028, time.January, 10, 9, 0, 0, 0, time.UTC)
+	got, err := parseNaturalDate("feb 29", now)
+	if err != nil {
+		t.Fatalf("parseNaturalDate: %v", err)
+	}
+	if !got.Equal(day(2028, time.February, 29)) {
+		t.Errorf("got %s, want 2028-02-29", got.Format("2006-01-02"))
+	}
+}
+
+func TestParseDueFilter(t *testing.T) {
+	justBefore := func(t time.Time) time.Time { return t.Add(-time.Millisecond) }
+	tests := []struct {
+		in     string
+		after  time.Time
+		before time.Time
+	}{
+		{"", time.Time{}, time.Time{}},
+		{"today", justBefore(day(2025, time.May, 14)), day(2025, time.May, 15)},
+		{"This week", justBefore(day(2025, time.May, 12)), day(2025, time.May, 19)},
+		{"week", justBefore(day(2025, time.May, 12)), day(2025, time.May, 19)},
+		{"next week", justBefore(day(2025, time.May, 19)), day(2025, time.May, 26)},
+		{"overdue", time.Time{}, taskTestNow},
+		{"before 2025-06-01", time.Time{}, day(2025, time.June, 1)},
+		{"after 2025-06-01", justBefore(day(2025, time.June, 2)), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			after, before, err := parseDueFilter(tt.in, taskTestNow)
+			if err != nil {
+				t.Fatalf("parseDueFilter: %v", err)
+			}
+			if !after.Equal(tt.after) || !before.Equal(tt.before) {
+				t.Errorf("range = (%s, %s), want (%s, %s)", after, before, tt.after, tt.before)
+			}
+		})
+	}
+
+	for _, in := range []string{"tomorrow", "before june", "after 2025-13-01"} {
+		if _, _, err := parseDueFilter(in, taskTestNow); err == nil {
+			t.Errorf("parseDueFilter(%q) succeeded, want an error", in)
+		}
+	}
+}
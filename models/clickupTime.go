@@ -0,0 +1,187 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClickUpTimeEntry is a tracked stretch of time. While its timer runs it has
+// no end and a negative duration.
+type ClickUpTimeEntry struct {
+	ID   string `json:"id"`
+	Task *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"task"`
+	User        ClickUpUser     `json:"user"`
+	Billable    bool            `json:"billable"`
+	Start       clickUpTime     `json:"start"`
+	End         clickUpTime     `json:"end"`
+	Duration    clickUpDuration `json:"duration"`
+	Description string          `json:"description"`
+	TaskURL     string          `json:"task_url"`
+}
+
+func (e ClickUpTimeEntry) running() bool {
+	return e.End.IsZero() || e.Duration < 0
+}
+
+// elapsed is how long the entry has run so far.
+func (e ClickUpTimeEntry) elapsed(now time.Time) time.Duration {
+	if e.running() {
+		return now.Sub(e.Start.Time)
+	}
+	return time.Duration(e.Duration)
+}
+
+func (e ClickUpTimeEntry) taskID() string {
+	if e.Task == nil {
+		return ""
+	}
+	return e.Task.ID
+}
+
+func (e ClickUpTimeEntry) taskName() string {
+	if e.Task == nil {
+		return "(no task)"
+	}
+	return e.Task.Name
+}
+
+// clickUpDuration decodes durations ClickUp sends as milliseconds, as a
+// string or a number.
+type clickUpDuration time.Duration
+
+func (d *clickUpDuration) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*d = 0
+		return nil
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("duration %s: %w", data, err)
+	}
+	*d = clickUpDuration(time.Duration(ms) * time.Millisecond)
+	return nil
+}
+
+// ClickUpTimeEntryInput is what a manual entry or an edit sends.
+type ClickUpTimeEntryInput struct {
+	TaskID      string
+	Start       time.Time
+	Duration    time.Duration
+	Description string
+	Billable    bool
+}
+
+func (in ClickUpTimeEntryInput) body() map[string]any {
+	body := map[string]any{
+		"start":       in.Start.UnixMilli(),
+		"duration":    in.Duration.Milliseconds(),
+		"end":         in.Start.Add(in.Duration).UnixMilli(),
+		"description": in.Description,
+		"billable":    in.Billable,
+	}
+	if in.TaskID != "" {
+		body["tid"] = in.TaskID
+	}
+	return body
+}
+
+func timeEntriesPath(p ClickUpProfile) string {
+	return "/team/" + p.WorkspaceID + "/time_entries"
+}
+
+// GetClickUpTimer returns the user's running timer, or nil if none is running.
+func GetClickUpTimer(p ClickUpProfile) (*ClickUpTimeEntry, error) {
+	var out struct {
+		Data *ClickUpTimeEntry `json:"data"`
+	}
+	if err := doClickUp(p, http.MethodGet, timeEntriesPath(p)+"/current", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	if out.Data == nil || out.Data.ID == "" {
+		return nil, nil
+	}
+	return out.Data, nil
+}
+
+func StartClickUpTimer(p ClickUpProfile, taskID string, description string, billable bool) (*ClickUpTimeEntry, error) {
+	var out struct {
+		Data ClickUpTimeEntry `json:"data"`
+	}
+	in := map[string]any{"tid": taskID, "description": description, "billable": billable}
+	if err := doClickUp(p, http.MethodPost, timeEntriesPath(p)+"/start", nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out.Data, nil
+}
+
+func StopClickUpTimer(p ClickUpProfile) error {
+	return doClickUp(p, http.MethodPost, timeEntriesPath(p)+"/stop", nil, nil, nil)
+}
+
+// ListClickUpTimeEntries returns the user's entries starting in [from, to).
+func ListClickUpTimeEntries(p ClickUpProfile, from time.Time, to time.Time) ([]ClickUpTimeEntry, error) {
+	var out struct {
+		Data []ClickUpTimeEntry `json:"data"`
+	}
+	q := url.Values{
+		"start_date": {strconv.FormatInt(from.UnixMilli(), 10)},
+		"end_date":   {strconv.FormatInt(to.UnixMilli()-1, 10)},
+	}
+	err := doClickUp(p, http.MethodGet, timeEntriesPath(p), q, nil, &out)
+	return out.Data, err
+}
+
+func CreateClickUpTimeEntry(p ClickUpProfile, in ClickUpTimeEntryInput) error {
+	return doClickUp(p, http.MethodPost, timeEntriesPath(p), nil, in.body(), nil)
+}
+
+func UpdateClickUpTimeEntry(p ClickUpProfile, id string, in ClickUpTimeEntryInput) error {
+	return doClickUp(p, http.MethodPut, timeEntriesPath(p)+"/"+id, nil, in.body(), nil)
+}
+
+func DeleteClickUpTimeEntry(p ClickUpProfile, id string) error {
+	return doClickUp(p, http.MethodDelete, timeEntriesPath(p)+"/"+id, nil, nil, nil)
+}
+
+// parseWorkDuration reads 1h30m, 90m, 1:30 or a number of hours such as 1.5.
+// Signs are refused anywhere, so -90m or 1:-5 can't make a negative entry.
+func parseWorkDuration(s string) (time.Duration, error) {
+	invalid := fmt.Errorf("durations look like 1h30m, 90m, 1:30 or 1.5")
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "+-") {
+		return 0, invalid
+	}
+	if h, m, ok := strings.Cut(s, ":"); ok {
+		hours, herr := strconv.Atoi(h)
+		minutes, merr := strconv.Atoi(m)
+		if herr == nil && merr == nil && minutes < 60 {
+			return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+		}
+	} else if hours, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(hours) && !math.IsInf(hours, 0) {
+		return time.Duration(hours * float64(time.Hour)), nil
+	} else if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return 0, invalid
+}
+
+// formatClock shows a duration as h:mm:ss for the running timer.
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// formatHours shows a duration as h:mm for timesheets.
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
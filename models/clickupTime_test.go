@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorkDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{"90m", 90 * time.Minute},
+		{" 1:30 ", 90 * time.Minute},
+		{"0:05", 5 * time.Minute},
+		{"1.5", 90 * time.Minute},
+		{"2", 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := parseWorkDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseWorkDuration(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "1:-5", "-1:30", "-0:30", "+1:30", "-1.5", "-90m", "1:60", "1:", "NaN", "Inf", "soon"} {
+		if got, err := parseWorkDuration(in); err == nil {
+			t.Errorf("parseWorkDuration(%q) = %v, want an error", in, got)
+		}
+	}
+}
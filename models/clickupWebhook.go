@@ -0,0 +1,255 @@
+package models
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxWebhookBody bounds what the receiver reads from one delivery.
+	maxWebhookBody = 1 << 20
+	// webhookBacklog is how many deliveries wait for the screen before
+	// further ones are dropped from the log.
+	webhookBacklog = 256
+)
+
+// clickUpWebhookEvents are the events a webhook can subscribe to; "*" is all.
+var clickUpWebhookEvents = []string{
+	"taskCreated", "taskUpdated", "taskDeleted", "taskPriorityUpdated", "taskStatusUpdated",
+	"taskAssigneeUpdated", "taskDueDateUpdated", "taskTagUpdated", "taskMoved", "taskCommentPosted",
+	"taskCommentUpdated", "taskTimeEstimateUpdated", "taskTimeTrackedUpdated",
+	"listCreated", "listUpdated", "listDeleted",
+	"folderCreated", "folderUpdated", "folderDeleted",
+	"spaceCreated", "spaceUpdated", "spaceDeleted",
+	"goalCreated", "goalUpdated", "goalDeleted",
+	"keyResultCreated", "keyResultUpdated", "keyResultDeleted",
+}
+
+// looseID decodes IDs ClickUp sends either as numbers or strings.
+type looseID string
+
+func (id *looseID) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		s = ""
+	}
+	*id = looseID(s)
+	return nil
+}
+
+type ClickUpWebhook struct {
+	ID       string   `json:"id"`
+	Endpoint string   `json:"endpoint"`
+	Events   []string `json:"events"`
+	SpaceID  looseID  `json:"space_id"`
+	FolderID looseID  `json:"folder_id"`
+	ListID   looseID  `json:"list_id"`
+	TaskID   looseID  `json:"task_id"`
+	Secret   string   `json:"secret"`
+	Health   struct {
+		Status    string     `json:"status"`
+		FailCount looseCount `json:"fail_count"`
+	} `json:"health"`
+}
+
+// location says which part of the workspace the webhook watches.
+func (w ClickUpWebhook) location() string {
+	switch {
+	case w.TaskID != "":
+		return "task " + string(w.TaskID)
+	case w.ListID != "":
+		return "list " + string(w.ListID)
+	case w.FolderID != "":
+		return "folder " + string(w.FolderID)
+	case w.SpaceID != "":
+		return "space " + string(w.SpaceID)
+	}
+	return "workspace"
+}
+
+// parseWebhookLocation reads "space ID", "folder ID", "list ID" or "task ID"
+// into the field ClickUp expects; blank watches the whole workspace.
+func parseWebhookLocation(s string) (string, string, error) {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 0:
+		return "", "", nil
+	case len(fields) == 2 && (fields[0] == "space" || fields[0] == "folder" || fields[0] == "list" || fields[0] == "task"):
+		return fields[0] + "_id", fields[1], nil
+	}
+	return "", "", fmt.Errorf("type space ID, folder ID, list ID or task ID, or leave it blank for the workspace")
+}
+
+func ListClickUpWebhooks(p ClickUpProfile) ([]ClickUpWebhook, error) {
+	var out struct {
+		Webhooks []ClickUpWebhook `json:"webhooks"`
+	}
+	err := doClickUp(p, http.MethodGet, "/team/"+p.WorkspaceID+"/webhook", nil, nil, &out)
+	return out.Webhooks, err
+}
+
+// CreateClickUpWebhook registers endpoint for events, optionally only for a
+// location as read by parseWebhookLocation. The reply carries the secret
+// deliveries are signed with.
+func CreateClickUpWebhook(p ClickUpProfile, endpoint string, events []string, location string) (ClickUpWebhook, error) {
+	field, id, err := parseWebhookLocation(location)
+	if err != nil {
+		return ClickUpWebhook{}, err
+	}
+	in := map[string]any{"endpoint": endpoint, "events": events}
+	if field != "" {
+		in[field] = id
+	}
+	var out struct {
+		Webhook ClickUpWebhook `json:"webhook"`
+	}
+	err = doClickUp(p, http.MethodPost, "/team/"+p.WorkspaceID+"/webhook", nil, in, &out)
+	return out.Webhook, err
+}
+
+// UpdateClickUpWebhook changes a webhook's endpoint and events, and with
+// status "active" or "suspended" turns deliveries on or off.
+func UpdateClickUpWebhook(p ClickUpProfile, id string, endpoint string, events []string, status string) error {
+	in := map[string]any{"endpoint": endpoint, "events": events, "status": status}
+	return doClickUp(p, http.MethodPut, "/webhook/"+id, nil, in, nil)
+}
+
+func DeleteClickUpWebhook(p ClickUpProfile, id string) error {
+	return doClickUp(p, http.MethodDelete, "/webhook/"+id, nil, nil, nil)
+}
+
+// signClickUpWebhook is the X-Signature ClickUp sends with a delivery: the
+// hex HMAC-SHA256 of the body keyed with the webhook's secret.
+func signClickUpWebhook(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyClickUpSignature reports whether signature matches body under any
+// of secrets.
+func verifyClickUpSignature(body []byte, signature string, secrets []string) bool {
+	got, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		want, _ := hex.DecodeString(signClickUpWebhook(body, secret))
+		if hmac.Equal(got, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClickUpWebhookDelivery is one request the receiver got.
+type ClickUpWebhookDelivery struct {
+	Received  time.Time
+	Remote    string
+	Event     string
+	WebhookID string
+	TaskID    string
+	Verified  bool
+	// Body is the payload, indented when it is JSON.
+	Body string
+}
+
+// WebhookReceiver is a local HTTP listener for webhook deliveries. It answers
+// 200 to correctly signed ones and 401 to the rest, and passes both on.
+type WebhookReceiver struct {
+	Addr       string
+	server     *http.Server
+	deliveries chan ClickUpWebhookDelivery
+	done       chan struct{}
+}
+
+// StartWebhookReceiver listens on addr, checking signatures against secrets.
+func StartWebhookReceiver(addr string, secrets []string) (*WebhookReceiver, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("starting receiver: %w", err)
+	}
+
+	r := &WebhookReceiver{
+		Addr:       listener.Addr().String(),
+		deliveries: make(chan ClickUpWebhookDelivery, webhookBacklog),
+		done:       make(chan struct{}),
+	}
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "ClickUp webhooks are POSTed.", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, "Reading body failed.", http.StatusBadRequest)
+			return
+		}
+
+		d := ClickUpWebhookDelivery{
+			Received: time.Now(),
+			Remote:   req.RemoteAddr,
+			Verified: verifyClickUpSignature(body, req.Header.Get("X-Signature"), secrets),
+			Body:     string(body),
+		}
+		var payload struct {
+			Event     string  `json:"event"`
+			WebhookID string  `json:"webhook_id"`
+			TaskID    looseID `json:"task_id"`
+		}
+		if json.Unmarshal(body, &payload) == nil {
+			d.Event, d.WebhookID, d.TaskID = payload.Event, payload.WebhookID, string(payload.TaskID)
+			var indented bytes.Buffer
+			if json.Indent(&indented, body, "", "  ") == nil {
+				d.Body = indented.String()
+			}
+		}
+
+		if d.Verified {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			http.Error(w, "Signature doesn't match.", http.StatusUnauthorized)
+		}
+		select {
+		case r.deliveries <- d:
+		case <-r.done:
+		default:
+		}
+	}
+	r.server = &http.Server{Handler: http.HandlerFunc(handler), ReadHeaderTimeout: 10 * time.Second}
+	go r.server.Serve(listener)
+
+	return r, nil
+}
+
+// Next waits for the next delivery; ok is false once the receiver is closed.
+func (r *WebhookReceiver) Next() (ClickUpWebhookDelivery, bool) {
+	select {
+	case d := <-r.deliveries:
+		return d, true
+	case <-r.done:
+		return ClickUpWebhookDelivery{}, false
+	}
+}
+
+func (r *WebhookReceiver) Close() {
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+	close(r.done)
+	r.server.Close()
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyClickUpSignature(t *testing.T) {
+	body := []byte(`{"event":"taskCreated","webhook_id":"wh1","task_id":"t1"}`)
+	signature := signClickUpWebhook(body, "s3cret")
+
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		secrets   []string
+		want      bool
+	}{
+		{"valid", body, signature, []string{"s3cret"}, true},
+		{"valid under a later secret", body, signature, []string{"", "other", "s3cret"}, true},
+		{"tampered body", []byte(`{"event":"taskDeleted","webhook_id":"wh1","task_id":"t1"}`), signature, []string{"s3cret"}, false},
+		{"wrong secret", body, signature, []string{"other"}, false},
+		{"missing signature", body, "", []string{"s3cret"}, false},
+		{"not hex", body, "not-a-signature", []string{"s3cret"}, false},
+		{"no secrets", body, signature, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyClickUpSignature(tt.body, tt.signature, tt.secrets); got != tt.want {
+				t.Errorf("verifyClickUpSignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookReceiver(t *testing.T) {
+	r, err := StartWebhookReceiver("127.0.0.1:0", []string{"s3cret"})
+	if err != nil {
+		t.Fatalf("StartWebhookReceiver: %v", err)
+	}
+	defer r.Close()
+
+	body := []byte(`{"event":"taskCreated","webhook_id":"wh1","task_id":"t1"}`)
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		status    int
+	}{
+		{"valid", body, signClickUpWebhook(body, "s3cret"), http.StatusOK},
+		{"tampered body", []byte(`{"event":"taskDeleted","webhook_id":"wh1","task_id":"t1"}`), signClickUpWebhook(body, "s3cret"), http.StatusUnauthorized},
+		{"wrong secret", body, signClickUpWebhook(body, "other"), http.StatusUnauthorized},
+		{"missing header", body, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://"+r.Addr+"/", bytes.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.signature != "" {
+				req.Header.Set("X-Signature", tt.signature)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			d, ok := r.Next()
+			if !ok {
+				t.Fatal("receiver closed before the delivery arrived")
+			}
+			if d.Verified != (tt.status == http.StatusOK) {
+				t.Errorf("Verified = %v for status %d", d.Verified, tt.status)
+			}
+			if d.WebhookID != "wh1" || d.TaskID != "t1" {
+				t.Errorf("delivery = %+v, want webhook wh1 and task t1", d)
+			}
+		})
+	}
+
+	t.Run("GET is rejected", func(t *testing.T) {
+		resp, err := http.Get("http://" + r.Addr + "/")
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type Config struct {
+	// OpenAIBudget is the monthly OpenAI spend in USD above which the status bar warns. Zero disables it.
+	OpenAIBudget float64 `json:"openaiBudget"`
+	// S3Workers is how many S3 transfers run at once.
+	S3Workers int `json:"s3Workers,omitempty"`
+	// S3PartSizeMB is the part size for multipart uploads of large files.
+	S3PartSizeMB int `json:"s3PartSizeMB,omitempty"`
+}
+
+// configDir is where the TUI keeps its settings and local data, creating it if needed.
+func configDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding config directory: %w", err)
+	}
+	dir := filepath.Join(base, "effective-computing-machine")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("creating config directory: %w", err)
+	}
+	return dir, nil
+}
+
+func LoadConfig() (Config, error) {
+	var config Config
+
+	dir, err := configDir()
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("decoding config: %w", err)
+	}
+	return config, nil
+}
+
+func SaveConfig(config Config) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type dbOpsMode int
+
+const (
+	dbOpsAsk dbOpsMode = iota
+	dbOpsReview
+	dbOpsResults
+)
+
+type DatabaseOperations struct {
+	mode        dbOpsMode
+	question    textinput.Model
+	editor      textarea.Model
+	results     table.Model
+	db          *sql.DB
+	schema      string
+	allowWrites bool
+	confirming  bool
+	loading     bool
+	status      string
+	// conversation groups this session's questions in the usage ledger.
+	conversation string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type DBSchemaMsg struct {
+	db     *sql.DB
+	schema string
+	err    error
+}
+
+type DBGeneratedSQLMsg struct {
+	sql      string
+	usageErr error
+	err      error
+}
+
+type DBQueryResultMsg struct {
+	result *QueryResult
+	err    error
+}
+
+const sqlSystemPrompt = `You translate questions into a single PostgreSQL query.
+Only use the tables and columns in the schema below. Reply with the SQL only, no explanation.
+Prefer SELECT statements; never modify data unless the question explicitly asks for it.
+
+Schema:
+%s`
+
+func InitialDatabaseOperations(token string, refreshToken string, user User) DatabaseOperations {
+	q := textinput.New()
+	q.Cursor.Style = cursorStyle
+	q.CharLimit = 512
+	q.Width = 80
+	q.Placeholder = "Ask a question about the crispy-doodle data"
+	q.Focus()
+	q.PromptStyle = focusedStyle
+	q.TextStyle = focusedStyle
+
+	e := textarea.New()
+	e.Placeholder = "SELECT ..."
+	e.SetWidth(80)
+	e.SetHeight(10)
+	e.CharLimit = 0
+
+	return DatabaseOperations{
+		mode:         dbOpsAsk,
+		question:     q,
+		editor:       e,
+		results:      table.New(table.WithHeight(15)),
+		loading:      true,
+		status:       "Connecting to database...",
+		conversation: NewConversation("Database Operations"),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+func (m DatabaseOperations) Init() tea.Cmd {
+	return tea.Batch(
+		tea.SetWindowTitle("Database Operations"),
+		textinput.Blink,
+		loadSchema,
+	)
+}
+
+func loadSchema() tea.Msg {
+	db, err := OpenDatabase()
+	if err != nil {
+		return DBSchemaMsg{err: err}
+	}
+	schema, err := GetSchemaSummary(db)
+	if err != nil {
+		db.Close()
+		return DBSchemaMsg{err: err}
+	}
+	return DBSchemaMsg{db: db, schema: schema}
+}
+
+func generateSQL(token string, conversation string, schema string, question string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := SendChat(token, ChatRequest{
+			Messages: []ChatMessage{
+				{Role: "system", Content: fmt.Sprintf(sqlSystemPrompt, schema)},
+				{Role: "user", Content: question},
+			},
+			Conversation: conversation,
+		})
+		msg := DBGeneratedSQLMsg{err: err}
+		if errors.Is(err, ErrUsageNotRecorded) {
+			msg.usageErr, msg.err = err, nil
+		}
+		if msg.err != nil {
+			return msg
+		}
+		msg.sql = stripCodeFence(resp.Choices[0].Message.Content)
+		return msg
+	}
+}
+
+func runSQL(db *sql.DB, query string, readOnly bool) tea.Cmd {
+	return func() tea.Msg {
+		result, err := RunQuery(db, query, readOnly)
+		return DBQueryResultMsg{result: result, err: err}
+	}
+}
+
+// stripCodeFence removes the markdown fence models like to wrap code in.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.TrimSuffix(strings.TrimSpace(s), "```")
+	return strings.TrimSpace(s)
+}
+
+func (m DatabaseOperations) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DBSchemaMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error loading schema: %v", msg.err)
+			return m, nil
+		}
+		m.db = msg.db
+		m.schema = msg.schema
+		m.status = fmt.Sprintf("Connected. %d tables in schema.", strings.Count(msg.schema, "\n"))
+		return m, nil
+
+	case DBGeneratedSQLMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error generating SQL: %v", msg.err)
+			return m, nil
+		}
+		m.editor.SetValue(msg.sql)
+		m.status = "Review the generated SQL. ctrl+r to run." + usageWarning(msg.usageErr)
+		cmd := m.setMode(dbOpsReview)
+		return m, cmd
+
+	case DBQueryResultMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.setResults(msg.result)
+		m.status = fmt.Sprintf("%d rows.", len(msg.result.Rows))
+		cmd := m.setMode(dbOpsResults)
+		return m, cmd
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.loading = true
+				m.status = "Running with writes enabled..."
+				return m, runSQL(m.db, m.editor.Value(), false)
+			default:
+				m.confirming = false
+				m.status = "Cancelled."
+			}
+			return m, nil
+		}
+
+		switch m.mode {
+		case dbOpsAsk:
+			switch msg.String() {
+			case "esc":
+				return m, m.back()
+			case "ctrl+e":
+				cmd := m.setMode(dbOpsReview)
+				return m, cmd
+			case "enter":
+				question := strings.TrimSpace(m.question.Value())
+				if question == "" {
+					return m, nil
+				}
+				if m.db == nil {
+					m.status = "Not connected to a database."
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Generating SQL..."
+				return m, generateSQL(m.token, m.conversation, m.schema, question)
+			}
+
+		case dbOpsReview:
+			switch msg.String() {
+			case "esc":
+				cmd := m.setMode(dbOpsAsk)
+				return m, cmd
+			case "ctrl+w":
+				m.allowWrites = !m.allowWrites
+				return m, nil
+			case "ctrl+r":
+				query := strings.TrimSpace(m.editor.Value())
+				if query == "" {
+					return m, nil
+				}
+				if m.db == nil {
+					m.status = "Not connected to a database."
+					return m, nil
+				}
+				if m.allowWrites {
+					m.confirming = true
+					m.status = "Writes are enabled. Run this statement? (y/n)"
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Running read-only..."
+				return m, runSQL(m.db, query, true)
+			}
+
+		case dbOpsResults:
+			if msg.String() == "esc" {
+				cmd := m.setMode(dbOpsReview)
+				return m, cmd
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.mode {
+	case dbOpsAsk:
+		m.question, cmd = m.question.Update(msg)
+	case dbOpsReview:
+		m.editor, cmd = m.editor.Update(msg)
+	case dbOpsResults:
+		m.results, cmd = m.results.Update(msg)
+	}
+
+	return m, cmd
+}
+
+func (m *DatabaseOperations) setMode(mode dbOpsMode) tea.Cmd {
+	m.mode = mode
+	m.question.Blur()
+	m.editor.Blur()
+	m.results.Blur()
+
+	switch mode {
+	case dbOpsAsk:
+		return m.question.Focus()
+	case dbOpsReview:
+		return m.editor.Focus()
+	case dbOpsResults:
+		m.results.Focus()
+	}
+	return nil
+}
+
+func (m *DatabaseOperations) setResults(result *QueryResult) {
+	columns := make([]table.Column, len(result.Columns))
+	for i, c := range result.Columns {
+		width := len(c)
+		for _, row := range result.Rows {
+			width = max(width, len(row[i]))
+		}
+		columns[i] = table.Column{Title: c, Width: min(width, 30)}
+	}
+
+	rows := make([]table.Row, len(result.Rows))
+	for i, r := range result.Rows {
+		rows[i] = table.Row(r)
+	}
+
+	// Columns must be replaced before rows so the table never renders
+	// rows wider than its header.
+	m.results.SetRows(nil)
+	m.results.SetColumns(columns)
+	m.results.SetRows(rows)
+	m.results.GotoTop()
+}
+
+// taskDraft turns the selected result row into a quick task. A row from the
+// messages table becomes the message itself and its ID, not a link to the
+// local API that nobody else can open.
+func (m DatabaseOperations) taskDraft() ClickUpTaskDraft {
+	row := m.results.SelectedRow()
+	if m.mode != dbOpsResults || row == nil {
+		return ClickUpTaskDraft{}
+	}
+	fields := make(map[string]string)
+	var b strings.Builder
+	for i, c := range m.results.Columns() {
+		fields[c.Title] = row[i]
+		fmt.Fprintf(&b, "- **%s**: %s\n", c.Title, row[i])
+	}
+
+	if text, ok := fields["text"]; ok && fields["channel"] != "" {
+		description := quoteMarkdown(text) + "\n\n"
+		description += "crispy-doodle message"
+		if fields["id"] != "" {
+			description += " " + fields["id"]
+		}
+		description += " in channel " + fields["channel"]
+		if fields["sender"] != "" {
+			description += " from " + fields["sender"]
+		}
+		return ClickUpTaskDraft{Title: draftTitle(text), Description: description}
+	}
+
+	query := strings.TrimSpace(m.editor.Value())
+	return ClickUpTaskDraft{Description: b.String() + "\nFrom the crispy-doodle database:\n\n```sql\n" + query + "\n```"}
+}
+
+func (m DatabaseOperations) back() tea.Cmd {
+	if m.db != nil {
+		m.db.Close()
+	}
+	return func() tea.Msg {
+		return MainMenuMsg{selected: 0, token: m.token, refreshToken: m.refreshToken, user: m.user}
+	}
+}
+
+func (m DatabaseOperations) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nDatabase Operations\n\n")
+
+	switch m.mode {
+	case dbOpsAsk:
+		b.WriteString(m.question.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: generate SQL • ctrl+e: write SQL by hand • esc: back"))
+	case dbOpsReview:
+		b.WriteString(m.editor.View())
+		b.WriteString("\n\n")
+		if m.allowWrites {
+			b.WriteString(focusedStyle.Render("Mode: WRITES ENABLED"))
+		} else {
+			b.WriteString("Mode: read-only")
+		}
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("ctrl+r: run • ctrl+w: toggle writes • esc: back to question"))
+	case dbOpsResults:
+		b.WriteString(m.results.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("↑/↓: scroll • esc: back to editor"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,302 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	diffChangedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+type DynamoItemEditor struct {
+	table        *DynamoTable
+	original     DynamoItem
+	deleting     bool
+	editor       textarea.Model
+	reviewing    bool
+	updated      DynamoItem
+	diff         []string
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+// DynamoEditRequestMsg opens the item editor from the explorer. A nil item
+// creates a new one.
+type DynamoEditRequestMsg struct {
+	table  *DynamoTable
+	item   DynamoItem
+	delete bool
+}
+
+// ShowDynamoExplorerMsg returns to the explorer, reloading the page after a write.
+type ShowDynamoExplorerMsg struct {
+	reload bool
+}
+
+type DynamoItemCheckedMsg struct {
+	updated DynamoItem
+	err     error
+}
+
+type DynamoItemSavedMsg struct {
+	err error
+}
+
+type ExternalEditorMsg struct {
+	text string
+	err  error
+}
+
+func InitialDynamoItemEditor(token string, refreshToken string, user User, table *DynamoTable, item DynamoItem, deleting bool) DynamoItemEditor {
+	e := textarea.New()
+	e.SetWidth(100)
+	e.SetHeight(20)
+	e.CharLimit = 0
+
+	if item != nil {
+		e.SetValue(itemJSON(item))
+	} else {
+		// Start new items from their key attributes with the right types.
+		skeleton := make(DynamoItem)
+		for _, k := range table.KeySchema {
+			skeleton[k.AttributeName] = AttributeValue{table.attributeType(k.AttributeName): ""}
+		}
+		e.SetValue(itemJSON(skeleton))
+	}
+	e.Focus()
+
+	m := DynamoItemEditor{
+		table:        table,
+		original:     item,
+		deleting:     deleting,
+		editor:       e,
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+	if deleting {
+		m.reviewing = true
+		m.diff = ItemDiff(item, nil)
+		m.status = "Delete this item? (y/n)"
+	}
+	return m
+}
+
+func itemJSON(item DynamoItem) string {
+	data, _ := json.MarshalIndent(item, "", "  ")
+	return string(data)
+}
+
+func (m DynamoItemEditor) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("DynamoDB Item"), textarea.Blink)
+}
+
+// openExternalEditor suspends the program and edits text in $EDITOR, in a
+// temporary file named after pattern so the editor can pick a syntax.
+func openExternalEditor(text string, pattern string) tea.Cmd {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return func() tea.Msg { return ExternalEditorMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(text)
+	f.Close()
+	if err != nil {
+		return func() tea.Msg { return ExternalEditorMsg{err: err} }
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return ExternalEditorMsg{err: err}
+		}
+		data, err := os.ReadFile(path)
+		return ExternalEditorMsg{text: string(data), err: err}
+	})
+}
+
+// checkItem re-reads the stored item so changes a condition expression can't
+// catch, such as edits inside maps and lists, are found before the diff is shown.
+func (m DynamoItemEditor) checkItem(updated DynamoItem) tea.Cmd {
+	token, table, original := m.token, m.table, m.original
+	return func() tea.Msg {
+		key := table.KeyOf(updated)
+		if original != nil {
+			key = table.KeyOf(original)
+		}
+		current, err := GetDynamoItem(token, table, key)
+		if err != nil {
+			return DynamoItemCheckedMsg{err: err}
+		}
+		if original == nil && current != nil {
+			return DynamoItemCheckedMsg{err: fmt.Errorf("an item with this key already exists")}
+		}
+		if original != nil && len(ItemDiff(original, current)) > 0 {
+			return DynamoItemCheckedMsg{err: errItemChanged}
+		}
+		return DynamoItemCheckedMsg{updated: updated}
+	}
+}
+
+func (m DynamoItemEditor) save() tea.Cmd {
+	token, table, original, updated, deleting := m.token, m.table, m.original, m.updated, m.deleting
+	return func() tea.Msg {
+		b := newExprBuilder()
+		switch {
+		case deleting:
+			return DynamoItemSavedMsg{err: DeleteDynamoItem(token, table, table.KeyOf(original), b, unchangedCondition(b, original, nil))}
+		case original == nil:
+			return DynamoItemSavedMsg{err: PutDynamoItem(token, table, updated, b, newItemCondition(b, table))}
+		default:
+			return DynamoItemSavedMsg{err: PutDynamoItem(token, table, updated, b, unchangedCondition(b, original, updated))}
+		}
+	}
+}
+
+func (m DynamoItemEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ExternalEditorMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.editor.SetValue(msg.text)
+		m.status = "Loaded changes from the editor. ctrl+s to review them."
+		return m, nil
+
+	case DynamoItemCheckedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.updated = msg.updated
+		m.diff = ItemDiff(m.original, m.updated)
+		if len(m.diff) == 0 {
+			m.status = "No changes."
+			return m, nil
+		}
+		m.reviewing = true
+		m.editor.Blur()
+		m.status = "Write these changes? (y/n)"
+		return m, nil
+
+	case DynamoItemSavedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		return m, func() tea.Msg { return ShowDynamoExplorerMsg{reload: true} }
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		if m.reviewing {
+			switch msg.String() {
+			case "y":
+				m.loading = true
+				m.status = "Writing..."
+				return m, m.save()
+			case "n", "esc":
+				if m.deleting {
+					return m, func() tea.Msg { return ShowDynamoExplorerMsg{} }
+				}
+				m.reviewing = false
+				m.status = ""
+				return m, m.editor.Focus()
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return ShowDynamoExplorerMsg{} }
+		case "ctrl+e":
+			return m, openExternalEditor(m.editor.Value(), "dynamodb-item-*.json")
+		case "ctrl+s":
+			updated, err := ParseDynamoItem(m.editor.Value(), m.table)
+			if err != nil {
+				m.status = err.Error()
+				return m, nil
+			}
+			if m.original != nil && itemJSON(m.table.KeyOf(updated)) != itemJSON(m.table.KeyOf(m.original)) {
+				m.status = "The key can't be changed; create a new item instead."
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Checking the stored item..."
+			return m, m.checkItem(updated)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m DynamoItemEditor) View() string {
+	var b strings.Builder
+
+	action := "Edit item in"
+	switch {
+	case m.deleting:
+		action = "Delete item from"
+	case m.original == nil:
+		action = "New item in"
+	}
+	fmt.Fprintf(&b, "\n%s %s\n\n", action, m.table.TableName)
+
+	if m.reviewing {
+		for _, line := range m.diff {
+			switch line[0] {
+			case '+':
+				b.WriteString(diffAddedStyle.Render(line))
+			case '-':
+				b.WriteString(diffRemovedStyle.Render(line))
+			default:
+				b.WriteString(diffChangedStyle.Render(line))
+			}
+			b.WriteRune('\n')
+		}
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render("y: write • n: back"))
+	} else {
+		b.WriteString(m.editor.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("values use DynamoDB JSON, e.g. {\"S\": \"text\"}, {\"N\": \"42\"}, {\"BOOL\": true}"))
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render("ctrl+s: review changes • ctrl+e: open in $EDITOR • esc: cancel"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
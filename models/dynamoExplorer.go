@@ -0,0 +1,452 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultDynamoPageSize = 25
+	maxItemColumns        = 6
+)
+
+type dynamoMode int
+
+const (
+	dynamoTables dynamoMode = iota
+	dynamoQueryForm
+	dynamoResults
+)
+
+type DynamoExplorer struct {
+	mode         dynamoMode
+	tables       []*DynamoTable
+	tableList    table.Model
+	table        *DynamoTable
+	focusIndex   int
+	inputs       []textinput.Model
+	scan         bool
+	query        DynamoQuery
+	starts       []DynamoItem
+	page         *DynamoPage
+	items        table.Model
+	showJSON     bool
+	json         viewport.Model
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type DynamoTablesMsg struct {
+	tables []*DynamoTable
+	err    error
+}
+
+type DynamoPageMsg struct {
+	page   *DynamoPage
+	starts []DynamoItem
+	err    error
+}
+
+func InitialDynamoExplorer(token string, refreshToken string, user User) DynamoExplorer {
+	m := DynamoExplorer{
+		tableList: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Table", Width: 30},
+				{Title: "Keys", Width: 30},
+				{Title: "Indexes", Width: 25},
+				{Title: "Items", Width: 10},
+				{Title: "Size", Width: 10},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		items:        table.New(table.WithHeight(15), table.WithFocused(true)),
+		json:         viewport.New(100, 20),
+		inputs:       make([]textinput.Model, 6),
+		loading:      true,
+		status:       "Loading tables...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+
+	m.inputs[0] = newFormInput("Index (blank for the table)", 255, 40)
+	m.inputs[1] = newFormInput("Partition key value", 1024, 60)
+	m.inputs[2] = newFormInput("Sort key condition, e.g. begins_with 2024- or between 1 and 5", 1024, 60)
+	m.inputs[3] = newFormInput("Filter, e.g. status = active, age > 30", 1024, 60)
+	m.inputs[4] = newFormInput("Projection, e.g. id, name, email", 1024, 60)
+	m.inputs[5] = newFormInput("Page size", 4, 20)
+	m.inputs[5].SetValue(strconv.Itoa(defaultDynamoPageSize))
+
+	return m
+}
+
+func (m DynamoExplorer) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("DynamoDB"), loadDynamoTables(m.token))
+}
+
+func loadDynamoTables(token string) tea.Cmd {
+	return func() tea.Msg {
+		names, err := ListDynamoTables(token)
+		if err != nil {
+			return DynamoTablesMsg{err: err}
+		}
+
+		tables := make([]*DynamoTable, 0, len(names))
+		for _, name := range names {
+			t, err := DescribeDynamoTable(token, name)
+			if err != nil {
+				return DynamoTablesMsg{err: err}
+			}
+			tables = append(tables, t)
+		}
+		return DynamoTablesMsg{tables: tables}
+	}
+}
+
+// fetchPage loads the page starting at the last key in starts. starts becomes
+// the page stack once the page arrives, so a failed request leaves paging as it was.
+func (m DynamoExplorer) fetchPage(starts []DynamoItem) tea.Cmd {
+	token, table, q := m.token, m.table, m.query
+	q.ExclusiveStartKey = starts[len(starts)-1]
+	return func() tea.Msg {
+		page, err := RunDynamoQuery(token, table, q)
+		return DynamoPageMsg{page: page, starts: starts, err: err}
+	}
+}
+
+// reload fetches the current page again, e.g. after an item was written.
+func (m *DynamoExplorer) reload() tea.Cmd {
+	if m.page == nil {
+		return nil
+	}
+	m.loading = true
+	m.status = "Reloading..."
+	return m.fetchPage(m.starts)
+}
+
+func (m *DynamoExplorer) showTables() {
+	rows := make([]table.Row, len(m.tables))
+	for i, t := range m.tables {
+		var indexes []string
+		for _, idx := range t.GlobalSecondaryIndexes {
+			indexes = append(indexes, idx.IndexName)
+		}
+		for _, idx := range t.LocalSecondaryIndexes {
+			indexes = append(indexes, idx.IndexName+" (local)")
+		}
+		rows[i] = table.Row{
+			t.TableName,
+			t.KeyDescription(t.KeySchema),
+			strings.Join(indexes, ", "),
+			strconv.FormatInt(t.ItemCount, 10),
+			formatBytes(t.TableSizeBytes),
+		}
+	}
+	m.tableList.SetRows(rows)
+	// A refresh can return fewer tables than before; SetCursor clamps the
+	// old cursor back into range.
+	m.tableList.SetCursor(m.tableList.Cursor())
+}
+
+func (m DynamoExplorer) selectedTable() (*DynamoTable, bool) {
+	i := m.tableList.Cursor()
+	if i < 0 || i >= len(m.tables) {
+		return nil, false
+	}
+	return m.tables[i], true
+}
+
+func (m *DynamoExplorer) showPage() {
+	columns := ItemAttributes(m.table, m.page.Items)
+	if len(columns) > maxItemColumns {
+		columns = columns[:maxItemColumns]
+	}
+
+	// Clear rows before changing columns so no row is wider than the columns.
+	m.items.SetRows(nil)
+	cols := make([]table.Column, len(columns))
+	for i, name := range columns {
+		cols[i] = table.Column{Title: name, Width: 20}
+	}
+	m.items.SetColumns(cols)
+
+	rows := make([]table.Row, len(m.page.Items))
+	for i, item := range m.page.Items {
+		row := make(table.Row, len(columns))
+		for j, name := range columns {
+			row[j] = formatAttribute(item[name])
+		}
+		rows[i] = row
+	}
+	m.items.SetRows(rows)
+	m.items.GotoTop()
+
+	plain := make([]map[string]any, len(m.page.Items))
+	for i, item := range m.page.Items {
+		plain[i] = PlainItem(item)
+	}
+	data, _ := json.MarshalIndent(plain, "", "  ")
+	m.json.SetContent(string(data))
+	m.json.GotoTop()
+}
+
+func (m DynamoExplorer) pageStatus() string {
+	s := fmt.Sprintf("Page %d • %d items", len(m.starts), m.page.Count)
+	if m.query.Filter != "" {
+		s += fmt.Sprintf(" (%d read before filtering)", m.page.ScannedCount)
+	}
+	if m.page.LastEvaluatedKey != nil {
+		s += " • more available"
+	}
+	return s
+}
+
+// taskDraft turns the item under the cursor into a quick task naming its key.
+func (m DynamoExplorer) taskDraft() ClickUpTaskDraft {
+	if m.mode != dynamoResults || m.page == nil || len(m.page.Items) == 0 {
+		return ClickUpTaskDraft{}
+	}
+	item := m.page.Items[m.items.Cursor()]
+	var key []string
+	for _, k := range m.table.KeySchema {
+		key = append(key, k.AttributeName+"="+formatAttribute(item[k.AttributeName]))
+	}
+	data, _ := json.MarshalIndent(PlainItem(item), "", "  ")
+	return ClickUpTaskDraft{
+		Title:       fmt.Sprintf("Follow up on %s item %s", m.table.TableName, strings.Join(key, ", ")),
+		Description: fmt.Sprintf("DynamoDB table `%s`, item:\n\n```json\n%s\n```", m.table.TableName, data),
+	}
+}
+
+func (m DynamoExplorer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DynamoTablesMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		sort.Slice(msg.tables, func(i, j int) bool { return msg.tables[i].TableName < msg.tables[j].TableName })
+		m.tables = msg.tables
+		m.status = fmt.Sprintf("%d tables", len(m.tables))
+		m.showTables()
+		return m, nil
+
+	case DynamoPageMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.page = msg.page
+		m.starts = msg.starts
+		m.mode = dynamoResults
+		m.showPage()
+		m.status = m.pageStatus()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch m.mode {
+		case dynamoTables:
+			switch msg.String() {
+			case "esc":
+				return m, func() tea.Msg {
+					return MainMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case "r":
+				m.loading = true
+				m.status = "Loading tables..."
+				return m, loadDynamoTables(m.token)
+			case "enter":
+				table, ok := m.selectedTable()
+				if !ok {
+					return m, nil
+				}
+				m.table = table
+				m.mode = dynamoQueryForm
+				m.focusIndex = 1
+				m.status = ""
+				return m, focusInput(m.inputs, m.focusIndex)
+			case "E", "I", "C":
+				table, ok := m.selectedTable()
+				if !ok {
+					return m, nil
+				}
+				kind := map[string]dynamoTransferKind{"E": dynamoExport, "I": dynamoImport, "C": dynamoCopy}[msg.String()]
+				return m, func() tea.Msg { return DynamoTransferRequestMsg{kind: kind, table: table} }
+			}
+
+		case dynamoQueryForm:
+			switch msg.String() {
+			case "esc":
+				m.mode = dynamoTables
+				m.status = fmt.Sprintf("%d tables", len(m.tables))
+				return m, nil
+			case "ctrl+s":
+				m.scan = !m.scan
+				return m, nil
+			case "tab", "shift+tab", "enter", "up", "down":
+				s := msg.String()
+
+				if s == "enter" && m.focusIndex == len(m.inputs) {
+					limit, err := strconv.Atoi(strings.TrimSpace(m.inputs[5].Value()))
+					if err != nil || limit < 1 {
+						m.status = "Page size must be a positive number."
+						return m, nil
+					}
+					m.query = DynamoQuery{
+						Table:          m.table.TableName,
+						Index:          strings.TrimSpace(m.inputs[0].Value()),
+						Scan:           m.scan,
+						PartitionValue: m.inputs[1].Value(),
+						SortCondition:  m.inputs[2].Value(),
+						Filter:         m.inputs[3].Value(),
+						Projection:     m.inputs[4].Value(),
+						Limit:          limit,
+					}
+					m.loading = true
+					m.status = "Running..."
+					return m, m.fetchPage([]DynamoItem{nil})
+				}
+
+				m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+				return m, focusInput(m.inputs, m.focusIndex)
+			}
+
+			cmds := make([]tea.Cmd, len(m.inputs))
+			for i := range m.inputs {
+				m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+			}
+			return m, tea.Batch(cmds...)
+
+		case dynamoResults:
+			switch msg.String() {
+			case "esc":
+				m.mode = dynamoQueryForm
+				m.status = ""
+				return m, nil
+			case "j":
+				m.showJSON = !m.showJSON
+				return m, nil
+			case "a":
+				table := m.table
+				return m, func() tea.Msg { return DynamoEditRequestMsg{table: table} }
+			case "e", "x":
+				if len(m.page.Items) == 0 {
+					return m, nil
+				}
+				table, item, del := m.table, m.page.Items[m.items.Cursor()], msg.String() == "x"
+				return m, func() tea.Msg { return DynamoEditRequestMsg{table: table, item: item, delete: del} }
+			case "E":
+				table, q := m.table, m.query
+				q.ExclusiveStartKey = nil
+				return m, func() tea.Msg { return DynamoTransferRequestMsg{kind: dynamoExport, table: table, query: &q} }
+			case "n":
+				if m.page.LastEvaluatedKey == nil {
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Loading next page..."
+				return m, m.fetchPage(append(slices.Clip(m.starts), m.page.LastEvaluatedKey))
+			case "p":
+				if len(m.starts) < 2 {
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Loading previous page..."
+				return m, m.fetchPage(m.starts[:len(m.starts)-1])
+			}
+
+			var cmd tea.Cmd
+			if m.showJSON {
+				m.json, cmd = m.json.Update(msg)
+			} else {
+				m.items, cmd = m.items.Update(msg)
+			}
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.mode == dynamoTables {
+		m.tableList, cmd = m.tableList.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m DynamoExplorer) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nDynamoDB")
+	if m.table != nil && m.mode != dynamoTables {
+		b.WriteString(" › " + m.table.TableName)
+	}
+	b.WriteString("\n\n")
+
+	switch m.mode {
+	case dynamoTables:
+		b.WriteString(m.tableList.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: query • E: export • I: import • C: copy to table • r: refresh • esc: back"))
+
+	case dynamoQueryForm:
+		fmt.Fprintf(&b, "Key: %s\n", m.table.KeyDescription(m.table.KeySchema))
+		for _, idx := range m.table.indexes() {
+			fmt.Fprintf(&b, "Index %s: %s\n", idx.IndexName, m.table.KeyDescription(idx.KeySchema))
+		}
+		operation := "Query"
+		if m.scan {
+			operation = "Scan (key fields are ignored)"
+		}
+		fmt.Fprintf(&b, "Operation: %s\n\n", focusedStyle.Render(operation))
+
+		for i := range m.inputs {
+			b.WriteString(m.inputs[i].View())
+			b.WriteRune('\n')
+		}
+		button := &blurredButton
+		if m.focusIndex == len(m.inputs) {
+			button = &focusedButton
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", *button)
+		b.WriteString(helpStyle.Render("ctrl+s: toggle query/scan • esc: tables"))
+
+	case dynamoResults:
+		if m.showJSON {
+			b.WriteString(m.json.View())
+		} else {
+			b.WriteString(m.items.View())
+		}
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("n: next page • p: previous page • j: toggle table/JSON • esc: edit query"))
+		b.WriteRune('\n')
+		b.WriteString(helpStyle.Render("e: edit item • a: add item • x: delete item • E: export results"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
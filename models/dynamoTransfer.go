@@ -0,0 +1,487 @@
+package models
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// BatchWriteItem takes at most 25 requests.
+	maxBatchWriteItems    = 25
+	maxDynamoRetries      = 8
+	dynamoRetryBaseDelay  = 100 * time.Millisecond
+	dynamoRetryMaxDelay   = 10 * time.Second
+	tableActivePollPeriod = time.Second
+)
+
+type dynamoTransferKind int
+
+const (
+	dynamoExport dynamoTransferKind = iota
+	dynamoImport
+	dynamoCopy
+)
+
+func (k dynamoTransferKind) String() string {
+	switch k {
+	case dynamoExport:
+		return "Export"
+	case dynamoImport:
+		return "Import"
+	default:
+		return "Copy"
+	}
+}
+
+type DynamoTransferProgressMsg struct {
+	run     *dynamoRun
+	items   int
+	retries int
+}
+
+type DynamoTransferDoneMsg struct {
+	run   *dynamoRun
+	items int
+	err   error
+}
+
+// dynamoRun streams progress from an import, export or copy back into the Bubble Tea loop.
+type dynamoRun struct {
+	events  chan tea.Msg
+	cancel  context.CancelFunc
+	items   int
+	retries int
+}
+
+func waitForDynamo(run *dynamoRun) tea.Cmd {
+	return func() tea.Msg {
+		return <-run.events
+	}
+}
+
+// startDynamoRun runs work in the background. work reports each batch of items
+// through run.progress and each retry through run.retry.
+func startDynamoRun(work func(ctx context.Context, run *dynamoRun) error) *dynamoRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &dynamoRun{events: make(chan tea.Msg, 64), cancel: cancel}
+	go func() {
+		err := work(ctx, run)
+		select {
+		case run.events <- DynamoTransferDoneMsg{run: run, items: run.items, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+	return run
+}
+
+func (run *dynamoRun) progress(n int) {
+	run.items += n
+	select {
+	case run.events <- DynamoTransferProgressMsg{run: run, items: run.items, retries: run.retries}:
+	default:
+		// The screen only needs the latest count; skip updates while it catches up.
+	}
+}
+
+func (run *dynamoRun) retry() {
+	run.retries++
+}
+
+// backoff sleeps before retry attempt, doubling each time with jitter.
+func backoff(ctx context.Context, attempt int) error {
+	delay := min(dynamoRetryBaseDelay<<attempt, dynamoRetryMaxDelay)
+	delay += time.Duration(rand.Int63n(int64(delay)))
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryThrottled calls fn until it succeeds, fails with something other than
+// throttling, or runs out of attempts.
+func retryThrottled(ctx context.Context, run *dynamoRun, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isThrottled(err) || attempt == maxDynamoRetries {
+			return err
+		}
+		run.retry()
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// BatchWriteDynamo puts items into a table in chunks of 25, retrying
+// unprocessed items and throttled requests with exponential backoff.
+func BatchWriteDynamo(ctx context.Context, run *dynamoRun, endpoint string, token string, table string, items []DynamoItem) error {
+	for start := 0; start < len(items); start += maxBatchWriteItems {
+		chunk := items[start:min(start+maxBatchWriteItems, len(items))]
+		requests := make([]any, len(chunk))
+		for i, item := range chunk {
+			requests[i] = map[string]any{"PutRequest": map[string]any{"Item": item}}
+		}
+
+		for attempt := 0; len(requests) > 0; attempt++ {
+			if attempt > maxDynamoRetries {
+				return fmt.Errorf("gave up on %d unprocessed items after %d attempts", len(requests), attempt)
+			}
+			if attempt > 0 {
+				run.retry()
+				if err := backoff(ctx, attempt-1); err != nil {
+					return err
+				}
+			}
+
+			var out struct {
+				UnprocessedItems map[string][]any `json:"UnprocessedItems"`
+			}
+			in := map[string]any{"RequestItems": map[string]any{table: requests}}
+			err := callDynamoAt(endpoint, token, "BatchWriteItem", in, &out)
+			if err != nil && !isThrottled(err) {
+				return err
+			}
+			if err == nil {
+				requests = out.UnprocessedItems[table]
+			}
+		}
+		run.progress(len(chunk))
+	}
+	return nil
+}
+
+// forEachDynamoPage runs q page by page from the start, handing each page's items to fn.
+func forEachDynamoPage(ctx context.Context, run *dynamoRun, token string, table *DynamoTable, q DynamoQuery, fn func([]DynamoItem) error) error {
+	q.ExclusiveStartKey = nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var page *DynamoPage
+		err := retryThrottled(ctx, run, func() error {
+			var err error
+			page, err = RunDynamoQuery(token, table, q)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err := fn(page.Items); err != nil {
+			return err
+		}
+		if page.LastEvaluatedKey == nil {
+			return nil
+		}
+		q.ExclusiveStartKey = page.LastEvaluatedKey
+	}
+}
+
+func isCSV(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".csv")
+}
+
+// ExportDynamo writes the results of q to path, as CSV of plain values when it
+// ends in .csv and otherwise as JSON lines of DynamoDB JSON, which keeps types.
+func ExportDynamo(ctx context.Context, run *dynamoRun, token string, table *DynamoTable, q DynamoQuery, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !isCSV(path) {
+		w := bufio.NewWriter(f)
+		err := forEachDynamoPage(ctx, run, token, table, q, func(items []DynamoItem) error {
+			for _, item := range items {
+				data, err := json.Marshal(item)
+				if err != nil {
+					return err
+				}
+				w.Write(data)
+				w.WriteByte('\n')
+			}
+			run.progress(len(items))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	// CSV needs every column up front, so collect the items first.
+	var items []DynamoItem
+	err = forEachDynamoPage(ctx, run, token, table, q, func(page []DynamoItem) error {
+		items = append(items, page...)
+		run.progress(len(page))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	columns := ItemAttributes(table, items)
+	w := csv.NewWriter(f)
+	w.Write(columns)
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, name := range columns {
+			row[i] = formatAttribute(item[name])
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// ImportDynamo puts every item in path into table. JSON lines may hold
+// DynamoDB JSON or plain objects; CSV cells are typed by the table's key
+// definitions, then guessed, with JSON objects and arrays becoming maps and lists.
+func ImportDynamo(ctx context.Context, run *dynamoRun, token string, table *DynamoTable, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	var batch []DynamoItem
+	add := func(item DynamoItem) error {
+		batch = append(batch, item)
+		if len(batch) < maxBatchWriteItems {
+			return nil
+		}
+		err := BatchWriteDynamo(ctx, run, endpoint, token, table.TableName, batch)
+		batch = nil
+		return err
+	}
+
+	if isCSV(path) {
+		r := csv.NewReader(f)
+		header, err := r.Read()
+		if err != nil {
+			return fmt.Errorf("reading CSV header: %w", err)
+		}
+		for line := 2; ; line++ {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			item := make(DynamoItem)
+			for i, cell := range record {
+				if cell == "" || i >= len(header) {
+					continue
+				}
+				typ := ""
+				if isKeyAttribute(table, header[i]) {
+					typ = table.attributeType(header[i])
+				}
+				item[header[i]] = csvAttribute(cell, typ)
+			}
+			if err := table.checkKey(item); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := add(item); err != nil {
+				return err
+			}
+		}
+	} else {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			item, err := parseImportLine(text, table)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := add(item); err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	return BatchWriteDynamo(ctx, run, endpoint, token, table.TableName, batch)
+}
+
+func isKeyAttribute(table *DynamoTable, name string) bool {
+	for _, k := range table.KeySchema {
+		if k.AttributeName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func csvAttribute(cell string, typ string) AttributeValue {
+	if typ != "" {
+		return typedAttribute(cell, typ)
+	}
+	if strings.HasPrefix(cell, "{") || strings.HasPrefix(cell, "[") {
+		dec := json.NewDecoder(strings.NewReader(cell))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err == nil {
+			return typedFromPlain(v)
+		}
+	}
+	return guessAttribute(cell)
+}
+
+func parseImportLine(text string, table *DynamoTable) (DynamoItem, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if looksTyped(raw) {
+		return ParseDynamoItem(text, table)
+	}
+
+	item := make(DynamoItem, len(raw))
+	for name, v := range raw {
+		item[name] = typedFromPlain(v)
+	}
+	if err := table.checkKey(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// looksTyped reports whether every value is a DynamoDB JSON type descriptor.
+func looksTyped(raw map[string]any) bool {
+	for _, v := range raw {
+		av, ok := v.(map[string]any)
+		if !ok || len(av) != 1 {
+			return false
+		}
+		for typ := range av {
+			switch typ {
+			case "S", "N", "B", "BOOL", "NULL", "M", "L", "SS", "NS", "BS":
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// typedFromPlain is the inverse of plainValue for values decoded with UseNumber.
+func typedFromPlain(v any) AttributeValue {
+	switch v := v.(type) {
+	case nil:
+		return AttributeValue{"NULL": true}
+	case bool:
+		return AttributeValue{"BOOL": v}
+	case json.Number:
+		return AttributeValue{"N": v.String()}
+	case string:
+		return AttributeValue{"S": v}
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, inner := range v {
+			m[k] = map[string]any(typedFromPlain(inner))
+		}
+		return AttributeValue{"M": m}
+	case []any:
+		l := make([]any, len(v))
+		for i, inner := range v {
+			l[i] = map[string]any(typedFromPlain(inner))
+		}
+		return AttributeValue{"L": l}
+	}
+	return AttributeValue{"S": fmt.Sprint(v)}
+}
+
+// CopyDynamoTable copies every item of src into dest at destEndpoint, creating
+// dest with the same keys and indexes first if it doesn't exist.
+func CopyDynamoTable(ctx context.Context, run *dynamoRun, token string, src *DynamoTable, destEndpoint string, dest string) error {
+	if err := ensureDynamoTable(ctx, destEndpoint, token, src, dest); err != nil {
+		return err
+	}
+	return forEachDynamoPage(ctx, run, token, src, DynamoQuery{Scan: true}, func(items []DynamoItem) error {
+		return BatchWriteDynamo(ctx, run, destEndpoint, token, dest, items)
+	})
+}
+
+func ensureDynamoTable(ctx context.Context, endpoint string, token string, like *DynamoTable, name string) error {
+	describe := map[string]any{"TableName": name}
+	err := callDynamoAt(endpoint, token, "DescribeTable", describe, nil)
+	var se *statusError
+	if err == nil {
+		return nil
+	}
+	if !errors.As(err, &se) || !strings.Contains(se.Body, "ResourceNotFoundException") {
+		return err
+	}
+
+	in := map[string]any{
+		"TableName":            name,
+		"KeySchema":            like.KeySchema,
+		"AttributeDefinitions": like.AttributeDefinitions,
+		"BillingMode":          "PAY_PER_REQUEST",
+	}
+	// Indexes project everything; the source's projections aren't carried over.
+	for field, indexes := range map[string][]DynamoIndex{
+		"GlobalSecondaryIndexes": like.GlobalSecondaryIndexes,
+		"LocalSecondaryIndexes":  like.LocalSecondaryIndexes,
+	} {
+		if len(indexes) == 0 {
+			continue
+		}
+		var specs []map[string]any
+		for _, idx := range indexes {
+			specs = append(specs, map[string]any{
+				"IndexName":  idx.IndexName,
+				"KeySchema":  idx.KeySchema,
+				"Projection": map[string]string{"ProjectionType": "ALL"},
+			})
+		}
+		in[field] = specs
+	}
+	if err := callDynamoAt(endpoint, token, "CreateTable", in, nil); err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+
+	for {
+		var out struct {
+			Table DynamoTable `json:"Table"`
+		}
+		if err := callDynamoAt(endpoint, token, "DescribeTable", describe, &out); err != nil {
+			return err
+		}
+		if out.Table.TableStatus == "ACTIVE" {
+			return nil
+		}
+		select {
+		case <-time.After(tableActivePollPeriod):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type DynamoTransfer struct {
+	kind         dynamoTransferKind
+	table        *DynamoTable
+	query        DynamoQuery
+	focusIndex   int
+	inputs       []textinput.Model
+	run          *dynamoRun
+	items        int
+	retries      int
+	running      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+// DynamoTransferRequestMsg opens an export, import or copy from the explorer.
+// Exports run query, which is a full scan unless the explorer passes its own.
+type DynamoTransferRequestMsg struct {
+	kind  dynamoTransferKind
+	table *DynamoTable
+	query *DynamoQuery
+}
+
+func InitialDynamoTransfer(token string, refreshToken string, user User, kind dynamoTransferKind, table *DynamoTable, query *DynamoQuery) DynamoTransfer {
+	m := DynamoTransfer{
+		kind:         kind,
+		table:        table,
+		query:        DynamoQuery{Table: table.TableName, Scan: true},
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+	if query != nil {
+		m.query = *query
+	}
+
+	switch kind {
+	case dynamoExport:
+		m.inputs = []textinput.Model{newFormInput("File, .jsonl or .csv", 512, 60)}
+		m.inputs[0].SetValue(fmt.Sprintf("%s-%d.jsonl", table.TableName, time.Now().Unix()))
+	case dynamoImport:
+		m.inputs = []textinput.Model{newFormInput("File, .jsonl or .csv", 512, 60)}
+	case dynamoCopy:
+		m.inputs = []textinput.Model{
+			newFormInput("Destination table", 255, 60),
+			newFormInput("Destination endpoint, e.g. http://localhost:8000 (blank: same as source)", 512, 60),
+		}
+		m.inputs[0].SetValue(table.TableName)
+	}
+	focusInput(m.inputs, 0)
+
+	return m
+}
+
+func (m DynamoTransfer) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("DynamoDB "+m.kind.String()), textinput.Blink)
+}
+
+func (m DynamoTransfer) start() (*dynamoRun, error) {
+	token, table, query := m.token, m.table, m.query
+
+	switch m.kind {
+	case dynamoExport:
+		path := strings.TrimSpace(m.inputs[0].Value())
+		return startDynamoRun(func(ctx context.Context, run *dynamoRun) error {
+			return ExportDynamo(ctx, run, token, table, query, path)
+		}), nil
+
+	case dynamoImport:
+		path := strings.TrimSpace(m.inputs[0].Value())
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+		return startDynamoRun(func(ctx context.Context, run *dynamoRun) error {
+			return ImportDynamo(ctx, run, token, table, path)
+		}), nil
+
+	default:
+		dest := strings.TrimSpace(m.inputs[0].Value())
+		endpoint := strings.TrimSpace(m.inputs[1].Value())
+		if endpoint == "" {
+			endpoint = os.Getenv("DYNAMODB_ENDPOINT")
+		}
+		if dest == table.TableName && endpoint == os.Getenv("DYNAMODB_ENDPOINT") {
+			return nil, fmt.Errorf("the destination is the source table")
+		}
+		return startDynamoRun(func(ctx context.Context, run *dynamoRun) error {
+			return CopyDynamoTable(ctx, run, token, table, endpoint, dest)
+		}), nil
+	}
+}
+
+func (m DynamoTransfer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DynamoTransferProgressMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.items = msg.items
+		m.retries = msg.retries
+		return m, waitForDynamo(m.run)
+
+	case DynamoTransferDoneMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.running = false
+		m.items = msg.items
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error after %d items: %v", m.items, msg.err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Done: %d items.", m.items)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			if m.run != nil {
+				m.run.cancel()
+			}
+			return m, tea.Quit
+		case "esc":
+			if m.run != nil {
+				m.run.cancel()
+			}
+			reload := m.kind == dynamoImport && m.items > 0
+			return m, func() tea.Msg { return ShowDynamoExplorerMsg{reload: reload} }
+		}
+		if m.running {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				if strings.TrimSpace(m.inputs[0].Value()) == "" {
+					return m, nil
+				}
+				run, err := m.start()
+				if err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+					return m, nil
+				}
+				m.run = run
+				m.running = true
+				m.items, m.retries = 0, 0
+				m.status = ""
+				return m, waitForDynamo(m.run)
+			}
+
+			m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+			return m, focusInput(m.inputs, m.focusIndex)
+		}
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m DynamoTransfer) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\n%s %s\n\n", m.kind, m.table.TableName)
+	switch m.kind {
+	case dynamoExport:
+		if m.query.Scan && m.query.Filter == "" {
+			b.WriteString("Exports the whole table. JSON lines keep attribute types; CSV holds plain values.\n\n")
+		} else {
+			b.WriteString("Exports every page of the current query.\n\n")
+		}
+	case dynamoImport:
+		b.WriteString("Items whose key already exists are overwritten. CSV values other than keys are typed by guessing.\n\n")
+	case dynamoCopy:
+		b.WriteString("The destination is created with the same keys and indexes if it doesn't exist.\n\n")
+	}
+
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		b.WriteRune('\n')
+	}
+	button := &blurredButton
+	if m.focusIndex == len(m.inputs) {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+
+	if m.running {
+		fmt.Fprintf(&b, "%d items so far", m.items)
+		if m.retries > 0 {
+			fmt.Fprintf(&b, " • %d retries after throttling", m.retries)
+		}
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
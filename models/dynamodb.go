@@ -0,0 +1,819 @@
+package models
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dynamoTargetPrefix = "DynamoDB_20120810."
+
+// AttributeValue is one value in DynamoDB's typed JSON, e.g. {"S": "abc"} or {"N": "42"}.
+type AttributeValue map[string]any
+
+type DynamoItem map[string]AttributeValue
+
+type DynamoKeyElement struct {
+	AttributeName string `json:"AttributeName"`
+	KeyType       string `json:"KeyType"`
+}
+
+type DynamoAttributeDefinition struct {
+	AttributeName string `json:"AttributeName"`
+	AttributeType string `json:"AttributeType"`
+}
+
+type DynamoIndex struct {
+	IndexName string             `json:"IndexName"`
+	KeySchema []DynamoKeyElement `json:"KeySchema"`
+	ItemCount int64              `json:"ItemCount"`
+}
+
+type DynamoTable struct {
+	TableName              string                      `json:"TableName"`
+	TableStatus            string                      `json:"TableStatus"`
+	KeySchema              []DynamoKeyElement          `json:"KeySchema"`
+	AttributeDefinitions   []DynamoAttributeDefinition `json:"AttributeDefinitions"`
+	ItemCount              int64                       `json:"ItemCount"`
+	TableSizeBytes         int64                       `json:"TableSizeBytes"`
+	GlobalSecondaryIndexes []DynamoIndex               `json:"GlobalSecondaryIndexes"`
+	LocalSecondaryIndexes  []DynamoIndex               `json:"LocalSecondaryIndexes"`
+}
+
+// DynamoQuery describes a Query, or with Scan set a Scan, as entered in the
+// query builder. SortCondition and Filter use the syntax parsed by condition.
+type DynamoQuery struct {
+	Table             string
+	Index             string
+	Scan              bool
+	PartitionValue    string
+	SortCondition     string
+	Filter            string
+	Projection        string
+	Limit             int
+	ExclusiveStartKey DynamoItem
+}
+
+type DynamoPage struct {
+	Items            []DynamoItem `json:"Items"`
+	Count            int          `json:"Count"`
+	ScannedCount     int          `json:"ScannedCount"`
+	LastEvaluatedKey DynamoItem   `json:"LastEvaluatedKey"`
+}
+
+// callDynamo sends one DynamoDB API action. Requests normally go through the
+// crispy-doodle backend, which holds the AWS credentials. With DYNAMODB_ENDPOINT
+// set, e.g. to http://localhost:8000 for DynamoDB Local, they go straight to that
+// endpoint, signed with the AWS_* environment variables.
+func callDynamo(token string, action string, in any, out any) error {
+	return callDynamoAt(os.Getenv("DYNAMODB_ENDPOINT"), token, action, in, out)
+}
+
+// callDynamoAt is callDynamo against an explicit endpoint, where "" means the
+// backend. Copies use it to read from one place and write to another.
+func callDynamoAt(endpoint string, token string, action string, in any, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	direct := endpoint != ""
+	if !direct {
+		endpoint = "http://localhost:8080/api/dynamodb"
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", dynamoTargetPrefix+action)
+
+	var resp *http.Response
+	if direct {
+		signV4(req, body, "dynamodb", time.Now().UTC())
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("sending request: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return &statusError{StatusCode: resp.StatusCode, Body: string(data)}
+		}
+	} else {
+		resp, err = doBackendRequest(token, req)
+		if err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// signV4 adds AWS Signature Version 4 headers to req. DynamoDB Local accepts any
+// credentials, so placeholders are used when none are set.
+func signV4(req *http.Request, body []byte, service string, now time.Time) {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" {
+		accessKey, secretKey = "local", "local"
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if sessionToken := os.Getenv("AWS_SESSION_TOKEN"); sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	var names []string
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func ListDynamoTables(token string) ([]string, error) {
+	var tables []string
+	start := ""
+	for {
+		in := map[string]any{}
+		if start != "" {
+			in["ExclusiveStartTableName"] = start
+		}
+
+		var out struct {
+			TableNames             []string `json:"TableNames"`
+			LastEvaluatedTableName string   `json:"LastEvaluatedTableName"`
+		}
+		if err := callDynamo(token, "ListTables", in, &out); err != nil {
+			return nil, err
+		}
+		tables = append(tables, out.TableNames...)
+		if out.LastEvaluatedTableName == "" {
+			return tables, nil
+		}
+		start = out.LastEvaluatedTableName
+	}
+}
+
+func DescribeDynamoTable(token string, table string) (*DynamoTable, error) {
+	var out struct {
+		Table DynamoTable `json:"Table"`
+	}
+	if err := callDynamo(token, "DescribeTable", map[string]any{"TableName": table}, &out); err != nil {
+		return nil, err
+	}
+	return &out.Table, nil
+}
+
+func (t *DynamoTable) indexes() []DynamoIndex {
+	return slices.Concat(t.GlobalSecondaryIndexes, t.LocalSecondaryIndexes)
+}
+
+// keySchemaFor returns the partition and sort key names of the table or one of its indexes.
+func (t *DynamoTable) keySchemaFor(index string) (string, string, error) {
+	schema := t.KeySchema
+	if index != "" {
+		found := false
+		for _, idx := range t.indexes() {
+			if idx.IndexName == index {
+				schema, found = idx.KeySchema, true
+			}
+		}
+		if !found {
+			return "", "", fmt.Errorf("table %s has no index %q", t.TableName, index)
+		}
+	}
+
+	var partition, sortKey string
+	for _, k := range schema {
+		if k.KeyType == "HASH" {
+			partition = k.AttributeName
+		} else {
+			sortKey = k.AttributeName
+		}
+	}
+	return partition, sortKey, nil
+}
+
+func (t *DynamoTable) attributeType(name string) string {
+	for _, a := range t.AttributeDefinitions {
+		if a.AttributeName == name {
+			return a.AttributeType
+		}
+	}
+	return "S"
+}
+
+// KeyDescription renders a key schema like "id (S), created (N)".
+func (t *DynamoTable) KeyDescription(schema []DynamoKeyElement) string {
+	var parts []string
+	for _, k := range schema {
+		parts = append(parts, fmt.Sprintf("%s (%s)", k.AttributeName, t.attributeType(k.AttributeName)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// exprBuilder hands out #name and :value placeholders so user input never has
+// to be escaped into an expression.
+type exprBuilder struct {
+	names  map[string]string
+	values map[string]AttributeValue
+}
+
+func newExprBuilder() *exprBuilder {
+	return &exprBuilder{names: make(map[string]string), values: make(map[string]AttributeValue)}
+}
+
+func (b *exprBuilder) name(attr string) string {
+	for placeholder, n := range b.names {
+		if n == attr {
+			return placeholder
+		}
+	}
+	placeholder := fmt.Sprintf("#n%d", len(b.names))
+	b.names[placeholder] = attr
+	return placeholder
+}
+
+func (b *exprBuilder) value(av AttributeValue) string {
+	placeholder := fmt.Sprintf(":v%d", len(b.values))
+	b.values[placeholder] = av
+	return placeholder
+}
+
+// condition turns "op value" into an expression on attr. Operators are =, <>,
+// <, <=, >, >=, begins_with, contains, between (as "between a and b"), exists
+// and not_exists. typ fixes the value type; when empty it's guessed from the text.
+func (b *exprBuilder) condition(attr string, cond string, typ string) (string, error) {
+	op, rest, _ := strings.Cut(strings.TrimSpace(cond), " ")
+	rest = strings.TrimSpace(rest)
+	value := func(s string) AttributeValue {
+		if typ != "" {
+			return typedAttribute(s, typ)
+		}
+		return guessAttribute(s)
+	}
+	name := b.name(attr)
+
+	switch strings.ToLower(op) {
+	case "=", "<>", "<", "<=", ">", ">=":
+		if rest == "" {
+			return "", fmt.Errorf("missing value after %s %s", attr, op)
+		}
+		return fmt.Sprintf("%s %s %s", name, op, b.value(value(rest))), nil
+	case "begins_with", "contains":
+		if rest == "" {
+			return "", fmt.Errorf("missing value after %s %s", attr, op)
+		}
+		return fmt.Sprintf("%s(%s, %s)", strings.ToLower(op), name, b.value(value(rest))), nil
+	case "between":
+		low, high, ok := strings.Cut(rest, " and ")
+		if !ok {
+			return "", fmt.Errorf("expected %s between <low> and <high>", attr)
+		}
+		return fmt.Sprintf("%s BETWEEN %s AND %s", name, b.value(value(strings.TrimSpace(low))), b.value(value(strings.TrimSpace(high)))), nil
+	case "exists":
+		return fmt.Sprintf("attribute_exists(%s)", name), nil
+	case "not_exists":
+		return fmt.Sprintf("attribute_not_exists(%s)", name), nil
+	}
+	return "", fmt.Errorf("unknown operator %q for %s", op, attr)
+}
+
+// filter parses comma-separated "attr op value" clauses into an expression
+// that requires all of them.
+func (b *exprBuilder) filter(s string) (string, error) {
+	var clauses []string
+	for _, clause := range strings.Split(s, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		attr, cond, ok := strings.Cut(clause, " ")
+		if !ok {
+			return "", fmt.Errorf("expected \"attribute operator value\", got %q", clause)
+		}
+		expr, err := b.condition(attr, cond, "")
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, expr)
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+func (b *exprBuilder) projection(s string) string {
+	var names []string
+	for _, attr := range strings.Split(s, ",") {
+		if attr = strings.TrimSpace(attr); attr != "" {
+			names = append(names, b.name(attr))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// apply adds the placeholders used so far to a request.
+func (b *exprBuilder) apply(in map[string]any) {
+	if len(b.names) > 0 {
+		in["ExpressionAttributeNames"] = b.names
+	}
+	if len(b.values) > 0 {
+		in["ExpressionAttributeValues"] = b.values
+	}
+}
+
+// RunDynamoQuery fetches one page of a query or scan.
+func RunDynamoQuery(token string, table *DynamoTable, q DynamoQuery) (*DynamoPage, error) {
+	b := newExprBuilder()
+	in := map[string]any{"TableName": table.TableName}
+	if q.Index != "" {
+		in["IndexName"] = q.Index
+	}
+	if q.Limit > 0 {
+		in["Limit"] = q.Limit
+	}
+	if q.ExclusiveStartKey != nil {
+		in["ExclusiveStartKey"] = q.ExclusiveStartKey
+	}
+
+	action := "Scan"
+	if !q.Scan {
+		action = "Query"
+		partition, sortKey, err := table.keySchemaFor(q.Index)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(q.PartitionValue) == "" {
+			return nil, fmt.Errorf("a query needs a value for the partition key %s", partition)
+		}
+
+		keyCondition := fmt.Sprintf("%s = %s", b.name(partition), b.value(typedAttribute(strings.TrimSpace(q.PartitionValue), table.attributeType(partition))))
+		if strings.TrimSpace(q.SortCondition) != "" {
+			if sortKey == "" {
+				return nil, fmt.Errorf("%s has no sort key", table.TableName)
+			}
+			expr, err := b.condition(sortKey, q.SortCondition, table.attributeType(sortKey))
+			if err != nil {
+				return nil, err
+			}
+			keyCondition += " AND " + expr
+		}
+		in["KeyConditionExpression"] = keyCondition
+	}
+
+	if strings.TrimSpace(q.Filter) != "" {
+		expr, err := b.filter(q.Filter)
+		if err != nil {
+			return nil, err
+		}
+		in["FilterExpression"] = expr
+	}
+	if strings.TrimSpace(q.Projection) != "" {
+		in["ProjectionExpression"] = b.projection(q.Projection)
+	}
+	b.apply(in)
+
+	var page DynamoPage
+	if err := callDynamo(token, action, in, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// typedAttribute builds a value of a key attribute type: S, N or B (base64).
+func typedAttribute(s string, typ string) AttributeValue {
+	return AttributeValue{typ: s}
+}
+
+// guessAttribute types a value typed into a filter: true/false are booleans,
+// null is NULL, numbers are N and anything else, or anything in quotes, is S.
+func guessAttribute(s string) AttributeValue {
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return AttributeValue{"S": unquoted}
+	}
+	switch s {
+	case "true", "false":
+		return AttributeValue{"BOOL": s == "true"}
+	case "null":
+		return AttributeValue{"NULL": true}
+	}
+	if dynamoNumber.MatchString(s) {
+		return AttributeValue{"N": s}
+	}
+	return AttributeValue{"S": s}
+}
+
+// dynamoNumber matches the decimal numbers DynamoDB accepts. strconv.ParseFloat
+// would also let through NaN, Inf and hex floats, which DynamoDB rejects.
+var dynamoNumber = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// plainValue converts a typed value to the plain JSON value it represents.
+func plainValue(av AttributeValue) any {
+	for typ, v := range av {
+		switch typ {
+		case "N":
+			if s, ok := v.(string); ok {
+				return json.Number(s)
+			}
+		case "NULL":
+			return nil
+		case "M":
+			m, _ := v.(map[string]any)
+			out := make(map[string]any, len(m))
+			for k, inner := range m {
+				innerMap, _ := inner.(map[string]any)
+				out[k] = plainValue(innerMap)
+			}
+			return out
+		case "L":
+			l, _ := v.([]any)
+			out := make([]any, len(l))
+			for i, inner := range l {
+				innerMap, _ := inner.(map[string]any)
+				out[i] = plainValue(innerMap)
+			}
+			return out
+		case "NS":
+			l, _ := v.([]any)
+			out := make([]any, len(l))
+			for i, n := range l {
+				s, _ := n.(string)
+				out[i] = json.Number(s)
+			}
+			return out
+		}
+		return v
+	}
+	return nil
+}
+
+// PlainItem converts an item to plain JSON values for display and export.
+func PlainItem(item DynamoItem) map[string]any {
+	out := make(map[string]any, len(item))
+	for k, v := range item {
+		out[k] = plainValue(v)
+	}
+	return out
+}
+
+func formatAttribute(av AttributeValue) string {
+	if av == nil {
+		return ""
+	}
+	switch v := plainValue(av).(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// ItemAttributes lists the attribute names used across items, key attributes first.
+func ItemAttributes(table *DynamoTable, items []DynamoItem) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, k := range table.KeySchema {
+		seen[k.AttributeName] = true
+		names = append(names, k.AttributeName)
+	}
+
+	var rest []string
+	for _, item := range items {
+		for name := range item {
+			if !seen[name] {
+				seen[name] = true
+				rest = append(rest, name)
+			}
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// KeyOf returns just the primary key attributes of item.
+func (t *DynamoTable) KeyOf(item DynamoItem) DynamoItem {
+	key := make(DynamoItem, len(t.KeySchema))
+	for _, k := range t.KeySchema {
+		key[k.AttributeName] = item[k.AttributeName]
+	}
+	return key
+}
+
+// GetDynamoItem reads an item with a strongly consistent read, returning nil
+// if it doesn't exist.
+func GetDynamoItem(token string, table *DynamoTable, key DynamoItem) (DynamoItem, error) {
+	var out struct {
+		Item DynamoItem `json:"Item"`
+	}
+	in := map[string]any{"TableName": table.TableName, "Key": key, "ConsistentRead": true}
+	if err := callDynamo(token, "GetItem", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Item, nil
+}
+
+// PutDynamoItem writes item only if condition, built with b, holds.
+func PutDynamoItem(token string, table *DynamoTable, item DynamoItem, b *exprBuilder, condition string) error {
+	in := map[string]any{"TableName": table.TableName, "Item": item}
+	if condition != "" {
+		in["ConditionExpression"] = condition
+		b.apply(in)
+	}
+	return conditionalError(callDynamo(token, "PutItem", in, nil))
+}
+
+// DeleteDynamoItem deletes the item with key only if condition, built with b, holds.
+func DeleteDynamoItem(token string, table *DynamoTable, key DynamoItem, b *exprBuilder, condition string) error {
+	in := map[string]any{"TableName": table.TableName, "Key": key}
+	if condition != "" {
+		in["ConditionExpression"] = condition
+		b.apply(in)
+	}
+	return conditionalError(callDynamo(token, "DeleteItem", in, nil))
+}
+
+var errItemChanged = errors.New("the item was changed by someone else since it was loaded; reload it and try again")
+
+func conditionalError(err error) error {
+	var se *statusError
+	if errors.As(err, &se) && strings.Contains(se.Body, "ConditionalCheckFailedException") {
+		return errItemChanged
+	}
+	return err
+}
+
+// newItemCondition only lets a write through if no item with the key exists yet.
+func newItemCondition(b *exprBuilder, table *DynamoTable) string {
+	partition, _, _ := table.keySchemaFor("")
+	return fmt.Sprintf("attribute_not_exists(%s)", b.name(partition))
+}
+
+// unchangedCondition only lets a write through if the stored item still looks
+// like original: every scalar attribute is equal, every other attribute exists
+// and attributes that original lacked are still missing. Maps, lists and sets
+// can't be compared in a condition, so callers also re-read the item first.
+func unchangedCondition(b *exprBuilder, original DynamoItem, updated DynamoItem) string {
+	var names []string
+	for name := range original {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var clauses []string
+	for _, name := range names {
+		av := original[name]
+		switch attributeType(av) {
+		case "S", "N", "B", "BOOL":
+			clauses = append(clauses, fmt.Sprintf("%s = %s", b.name(name), b.value(av)))
+		default:
+			clauses = append(clauses, fmt.Sprintf("attribute_exists(%s)", b.name(name)))
+		}
+	}
+
+	var added []string
+	for name := range updated {
+		if _, ok := original[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		clauses = append(clauses, fmt.Sprintf("attribute_not_exists(%s)", b.name(name)))
+	}
+
+	return strings.Join(clauses, " AND ")
+}
+
+func attributeType(av AttributeValue) string {
+	for typ := range av {
+		return typ
+	}
+	return ""
+}
+
+// ParseDynamoItem parses an item written in DynamoDB's typed JSON and checks
+// every value against its type descriptor and the key attributes against the
+// table's attribute definitions.
+func ParseDynamoItem(text string, table *DynamoTable) (DynamoItem, error) {
+	var raw map[string]any
+	dec := json.NewDecoder(strings.NewReader(text))
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("expected a JSON object of attributes")
+	}
+
+	item := make(DynamoItem, len(raw))
+	for name, v := range raw {
+		if err := validateAttribute(name, v); err != nil {
+			return nil, err
+		}
+		item[name] = AttributeValue(v.(map[string]any))
+	}
+
+	if err := table.checkKey(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// checkKey makes sure item has every key attribute, non-empty and of the
+// type in the table's attribute definitions.
+func (t *DynamoTable) checkKey(item DynamoItem) error {
+	for _, k := range t.KeySchema {
+		av, ok := item[k.AttributeName]
+		if !ok {
+			return fmt.Errorf("missing key attribute %s", k.AttributeName)
+		}
+		want := t.attributeType(k.AttributeName)
+		if got := attributeType(av); got != want {
+			return fmt.Errorf("key attribute %s must be %s, not %s", k.AttributeName, want, got)
+		}
+		if s, _ := av[want].(string); s == "" {
+			return fmt.Errorf("key attribute %s can't be empty", k.AttributeName)
+		}
+	}
+	return nil
+}
+
+func validateAttribute(path string, v any) error {
+	av, ok := v.(map[string]any)
+	if !ok || len(av) != 1 {
+		return fmt.Errorf("%s: expected one type descriptor like {\"S\": \"text\"}", path)
+	}
+
+	for typ, value := range av {
+		switch typ {
+		case "S", "B":
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%s: %s value must be a string", path, typ)
+			}
+		case "N":
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s: N value must be a number in a string, e.g. {\"N\": \"42\"}", path)
+			}
+			if !dynamoNumber.MatchString(s) {
+				return fmt.Errorf("%s: %q is not a number", path, s)
+			}
+		case "BOOL":
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%s: BOOL value must be true or false", path)
+			}
+		case "NULL":
+			if value != true {
+				return fmt.Errorf("%s: NULL value must be true", path)
+			}
+		case "SS", "NS", "BS":
+			list, ok := value.([]any)
+			if !ok || len(list) == 0 {
+				return fmt.Errorf("%s: %s value must be a non-empty list", path, typ)
+			}
+			seen := make(map[string]bool)
+			for _, member := range list {
+				s, ok := member.(string)
+				if !ok {
+					return fmt.Errorf("%s: %s members must be strings", path, typ)
+				}
+				if typ == "NS" {
+					if !dynamoNumber.MatchString(s) {
+						return fmt.Errorf("%s: %q is not a number", path, s)
+					}
+				}
+				if seen[s] {
+					return fmt.Errorf("%s: duplicate set member %q", path, s)
+				}
+				seen[s] = true
+			}
+		case "M":
+			m, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: M value must be an object", path)
+			}
+			for name, inner := range m {
+				if err := validateAttribute(path+"."+name, inner); err != nil {
+					return err
+				}
+			}
+		case "L":
+			l, ok := value.([]any)
+			if !ok {
+				return fmt.Errorf("%s: L value must be a list", path)
+			}
+			for i, inner := range l {
+				if err := validateAttribute(fmt.Sprintf("%s[%d]", path, i), inner); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("%s: unknown type %q", path, typ)
+		}
+	}
+	return nil
+}
+
+// ItemDiff lists attribute changes from before to after as "+", "-" and "~" lines.
+func ItemDiff(before DynamoItem, after DynamoItem) []string {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, name := range sorted {
+		old, hadOld := before[name]
+		updated, hasNew := after[name]
+		oldJSON, _ := json.Marshal(old)
+		newJSON, _ := json.Marshal(updated)
+		switch {
+		case !hadOld:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", name, newJSON))
+		case !hasNew:
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, oldJSON))
+		case !bytes.Equal(oldJSON, newJSON):
+			lines = append(lines, fmt.Sprintf("~ %s: %s → %s", name, oldJSON, newJSON))
+		}
+	}
+	return lines
+}
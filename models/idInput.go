@@ -159,3 +159,45 @@ func (m IdInput) View() string {
 
 	return b.String()
 }
+
+// focusInput moves focus to inputs[focusIndex], blurring the rest. A focusIndex
+// of len(inputs) leaves every input blurred so a submit button can take focus.
+func focusInput(inputs []textinput.Model, focusIndex int) tea.Cmd {
+	cmds := make([]tea.Cmd, len(inputs))
+	for i := range inputs {
+		if i == focusIndex {
+			cmds[i] = inputs[i].Focus()
+			inputs[i].PromptStyle = focusedStyle
+			inputs[i].TextStyle = focusedStyle
+			continue
+		}
+		inputs[i].Blur()
+		inputs[i].PromptStyle = noStyle
+		inputs[i].TextStyle = noStyle
+	}
+	return tea.Batch(cmds...)
+}
+
+// nextFocus cycles focusIndex over the inputs plus the submit button.
+func nextFocus(focusIndex int, count int, key string) int {
+	if key == "up" || key == "shift+tab" {
+		focusIndex--
+	} else {
+		focusIndex++
+	}
+	if focusIndex > count {
+		return 0
+	} else if focusIndex < 0 {
+		return count
+	}
+	return focusIndex
+}
+
+func newFormInput(placeholder string, charLimit int, width int) textinput.Model {
+	t := textinput.New()
+	t.Cursor.Style = cursorStyle
+	t.CharLimit = charLimit
+	t.Width = width
+	t.Placeholder = placeholder
+	return t
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type imageProtocol int
+
+const (
+	protocolHalfblock imageProtocol = iota
+	protocolKitty
+	protocolSixel
+)
+
+// detectImageProtocol guesses what the terminal can draw from the environment.
+// ECM_IMAGE_PROTOCOL (kitty, sixel or halfblock) overrides the guess.
+func detectImageProtocol() imageProtocol {
+	switch strings.ToLower(os.Getenv("ECM_IMAGE_PROTOCOL")) {
+	case "kitty":
+		return protocolKitty
+	case "sixel":
+		return protocolSixel
+	case "halfblock", "ascii":
+		return protocolHalfblock
+	}
+
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", strings.Contains(term, "kitty"),
+		termProgram == "WezTerm", termProgram == "ghostty":
+		return protocolKitty
+	case strings.Contains(term, "sixel"), strings.Contains(term, "mlterm"),
+		termProgram == "iTerm.app", termProgram == "foot":
+		return protocolSixel
+	}
+	return protocolHalfblock
+}
+
+// RenderImage draws img in at most width columns and height rows using the best
+// protocol the terminal supports.
+func RenderImage(img image.Image, width int, height int) string {
+	switch detectImageProtocol() {
+	case protocolKitty:
+		return renderKitty(resizeImage(img, width*8, height*16), width, height)
+	case protocolSixel:
+		return renderSixel(resizeImage(img, width*8, height*16))
+	default:
+		return renderHalfblock(resizeImage(img, width, height*2))
+	}
+}
+
+func DecodeImage(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+	return img, nil
+}
+
+// resizeImage scales img with nearest-neighbour sampling so it fits in maxW x maxH
+// while keeping its aspect ratio.
+func resizeImage(img image.Image, maxW int, maxH int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	scale := min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	newW, newH := max(int(float64(w)*scale), 1), max(int(float64(h)*scale), 1)
+
+	out := image.NewRGBA(image.Rect(0, 0, newW, newH))
+	for y := 0; y < newH; y++ {
+		for x := 0; x < newW; x++ {
+			srcX := bounds.Min.X + x*w/newW
+			srcY := bounds.Min.Y + y*h/newH
+			out.Set(x, y, img.At(srcX, srcY))
+		}
+	}
+	return out
+}
+
+func hexColor(c color.Color) lipgloss.Color {
+	r, g, b, _ := c.RGBA()
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+}
+
+// renderHalfblock uses "▀" so every terminal row carries two pixel rows:
+// the foreground colour is the upper pixel and the background the lower.
+func renderHalfblock(img image.Image) string {
+	bounds := img.Bounds()
+	var b strings.Builder
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			style := lipgloss.NewStyle().Foreground(hexColor(img.At(x, y)))
+			if y+1 < bounds.Max.Y {
+				style = style.Background(hexColor(img.At(x, y+1)))
+			}
+			b.WriteString(style.Render("▀"))
+		}
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+// maxKittyReleases is how many freed images kittyReleases keeps deleting.
+const maxKittyReleases = 16
+
+var (
+	lastKittyID uint32
+	// kittyReleased holds the delete commands of recently freed images.
+	kittyMu       sync.Mutex
+	kittyReleased []string
+)
+
+// kittyPlacement finds the image a kitty preview places.
+var kittyPlacement = regexp.MustCompile(`\x1b_Ga=p,i=(\d+),`)
+
+// renderKitty returns img as PNG under a fresh image id using the kitty
+// graphics protocol, followed by the escape that places it in the given cell
+// area. Both go out with the frame: Bubble Tea only repaints changed lines, so
+// the image is sent again only when its line moves, and then under the same
+// id. ReleaseImage frees it.
+func renderKitty(img image.Image, width int, height int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return renderHalfblock(resizeImage(img, width, height*2))
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+	id := atomic.AddUint32(&lastKittyID, 1)
+
+	var b strings.Builder
+	const chunkSize = 4096
+	for i := 0; i < len(encoded); i += chunkSize {
+		end := min(i+chunkSize, len(encoded))
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=t,f=100,i=%d,q=2,m=%d;%s\x1b\\", id, more, encoded[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, encoded[i:end])
+		}
+	}
+	fmt.Fprintf(&b, "\x1b_Ga=p,i=%d,p=1,c=%d,r=%d,C=1,q=2\x1b\\", id, width, height)
+
+	return b.String() + strings.Repeat("\n", height)
+}
+
+// ReleaseImage frees the kitty image a preview from RenderImage shows, once
+// the preview is replaced or its screen is left. Other previews need nothing.
+func ReleaseImage(preview string) {
+	match := kittyPlacement.FindStringSubmatch(preview)
+	if match == nil {
+		return
+	}
+	kittyMu.Lock()
+	defer kittyMu.Unlock()
+	kittyReleased = append(kittyReleased, "\x1b_Ga=d,d=I,i="+match[1]+",q=2\x1b\\")
+	if len(kittyReleased) > maxKittyReleases {
+		kittyReleased = kittyReleased[len(kittyReleased)-maxKittyReleases:]
+	}
+}
+
+// kittyReleases is the delete commands of recently released images. AppModel
+// puts them at the top of every frame so they reach the terminal through
+// Bubble Tea's renderer rather than racing it on stdout; deleting an image
+// that is already gone does nothing.
+func kittyReleases() string {
+	kittyMu.Lock()
+	defer kittyMu.Unlock()
+	return strings.Join(kittyReleased, "")
+}
+
+// renderSixel encodes img as sixel graphics using a fixed 6x6x6 colour cube.
+func renderSixel(img image.Image) string {
+	bounds := img.Bounds()
+	var b strings.Builder
+
+	b.WriteString("\x1bPq")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+	for i := 0; i < 216; i++ {
+		r, g, bl := i/36, (i/6)%6, i%6
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*20, g*20, bl*20)
+	}
+
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += 6 {
+		// Collect the six-pixel-high columns of each colour in this band.
+		bands := make(map[int][]byte)
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			for dy := 0; dy < 6 && top+dy < bounds.Max.Y; dy++ {
+				idx := sixelIndex(img.At(x, top+dy))
+				row, ok := bands[idx]
+				if !ok {
+					row = make([]byte, bounds.Dx())
+					bands[idx] = row
+				}
+				row[x-bounds.Min.X] |= 1 << dy
+			}
+		}
+
+		for idx, row := range bands {
+			fmt.Fprintf(&b, "#%d", idx)
+			for _, bits := range row {
+				b.WriteByte(63 + bits)
+			}
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\\n")
+	return b.String()
+}
+
+func sixelIndex(c color.Color) int {
+	r, g, b, _ := c.RGBA()
+	return int(r>>8)*6/256*36 + int(g>>8)*6/256*6 + int(b>>8)*6/256
+}
@@ -16,9 +16,16 @@ type AWSMenu struct {
 	header       string
 }
 
-func InitialAWSMenu(token string, refreshToken string, user User) MainMenu {
-	return MainMenu{
-		choices:      []string{"S3", "Rekognition", "DynamoDB", "About"},
+type AWSMenuMsg struct {
+	selected     int
+	token        string
+	refreshToken string
+	user         User
+}
+
+func InitialAWSMenu(token string, refreshToken string, user User) AWSMenu {
+	return AWSMenu{
+		choices:      []string{"S3", "Rekognition", "Face Collections", "Batch Rekognition", "DynamoDB", "About"},
 		cursor:       0,
 		selected:     make(map[int]struct{}),
 		token:        token,
@@ -49,33 +56,33 @@ func (m AWSMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			m.selected = make(map[int]struct{})
 			m.selected[m.cursor] = struct{}{}
-			// switch m.cursor {
-			// case 0:
-			// 	m.header = "Postgres API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 0}
-			// 	}
-			// case 1:
-			// 	m.header = "OpenAI API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 1}
-			// 	}
-			// case 2:
-			// 	m.header = "AWS API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 2}
-			// 	}
-			// case 3:
-			// 	m.header = "ClickUp API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 3}
-			// 	}
-			// default:
-			// 	m.header = "Unknown API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: -1}
-			// 	}
-			// }
+			switch m.cursor {
+			case 0:
+				m.header = "S3 Selected"
+				return m, func() tea.Msg {
+					return AWSMenuMsg{selected: 0, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case 1:
+				m.header = "Rekognition Selected"
+				return m, func() tea.Msg {
+					return AWSMenuMsg{selected: 1, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case 2:
+				m.header = "Face Collections Selected"
+				return m, func() tea.Msg {
+					return AWSMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case 3:
+				m.header = "Batch Rekognition Selected"
+				return m, func() tea.Msg {
+					return AWSMenuMsg{selected: 3, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case 4:
+				m.header = "DynamoDB Selected"
+				return m, func() tea.Msg {
+					return AWSMenuMsg{selected: 4, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			}
 		}
 	}
 
@@ -13,12 +13,23 @@ type ClickUpMenu struct {
 	token        string
 	refreshToken string
 	user         User
+	clickup      ClickUpProfile
 	header       string
+	status       string
 }
 
-func InitialClickUpMenu(token string, refreshToken string, user User) MainMenu {
-	return MainMenu{
+// ClickUpMenuMsg opens the screen for a ClickUp menu choice.
+type ClickUpMenuMsg struct {
+	choice string
+}
+
+// ClickUpSignedOutMsg forgets the signed-out credential and returns to sign-in.
+type ClickUpSignedOutMsg struct{}
+
+func InitialClickUpMenu(token string, refreshToken string, user User, clickup ClickUpProfile) ClickUpMenu {
+	return ClickUpMenu{
 		choices: []string{
+			"Browse Hierarchy",
 			"Audit Logs",
 			"Authorization",
 			"Attachments",
@@ -55,6 +66,7 @@ func InitialClickUpMenu(token string, refreshToken string, user User) MainMenu {
 		token:        token,
 		refreshToken: refreshToken,
 		user:         user,
+		clickup:      clickup,
 		header:       "Select an API",
 	}
 }
@@ -69,6 +81,19 @@ func (m ClickUpMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: -1, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		case "w":
+			clickup := m.clickup
+			return m, func() tea.Msg { return ShowClickUpLoginMsg{profile: clickup} }
+		case "o":
+			if err := DeleteClickUpProfile(m.user); err != nil {
+				m.status = fmt.Sprintf("Error: %v", err)
+				return m, nil
+			}
+			return m, func() tea.Msg { return ClickUpSignedOutMsg{} }
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -80,33 +105,9 @@ func (m ClickUpMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			m.selected = make(map[int]struct{})
 			m.selected[m.cursor] = struct{}{}
-			// switch m.cursor {
-			// case 0:
-			// 	m.header = "Postgres API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 0}
-			// 	}
-			// case 1:
-			// 	m.header = "OpenAI API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 1}
-			// 	}
-			// case 2:
-			// 	m.header = "AWS API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 2}
-			// 	}
-			// case 3:
-			// 	m.header = "ClickUp API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 3}
-			// 	}
-			// default:
-			// 	m.header = "Unknown API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: -1}
-			// 	}
-			// }
+			choice := m.choices[m.cursor]
+			m.header = choice + " Selected"
+			return m, func() tea.Msg { return ClickUpMenuMsg{choice: choice} }
 		}
 	}
 
@@ -115,6 +116,7 @@ func (m ClickUpMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m ClickUpMenu) View() string {
 	s := "\nAvailible ClickUp APIs!\n\n"
+	s += fmt.Sprintf("Workspace: %s • signed in as %s\n\n", m.clickup.WorkspaceName, m.clickup.Username)
 
 	for i, choice := range m.choices {
 		cursor := " "
@@ -132,6 +134,10 @@ func (m ClickUpMenu) View() string {
 		s += fmt.Sprintf("%s [%s] %s\n", cursor, checked, choice)
 	}
 
+	s += "\n" + helpStyle.Render("w: switch workspace • o: sign out of ClickUp • esc: back")
+	if m.status != "" {
+		s += "\n\n" + m.status
+	}
 	s += "\n\nPress q to quit.\n"
 
 	return s
@@ -16,9 +16,16 @@ type OpenAIMenu struct {
 	header       string
 }
 
-func InitialOpemAIMenu(token string, refreshToken string, user User) MainMenu {
-	return MainMenu{
-		choices:      []string{"Ask ChatGPT", "Availible Models", "About"},
+type OpenAIMenuMsg struct {
+	selected     int
+	token        string
+	refreshToken string
+	user         User
+}
+
+func InitialOpemAIMenu(token string, refreshToken string, user User) OpenAIMenu {
+	return OpenAIMenu{
+		choices:      []string{"Ask ChatGPT", "Availible Models", "Generate Image", "Describe Image", "Usage", "About"},
 		cursor:       0,
 		selected:     make(map[int]struct{}),
 		token:        token,
@@ -49,33 +56,23 @@ func (m OpenAIMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			m.selected = make(map[int]struct{})
 			m.selected[m.cursor] = struct{}{}
-			// switch m.cursor {
-			// case 0:
-			// 	m.header = "Postgres API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 0}
-			// 	}
-			// case 1:
-			// 	m.header = "OpenAI API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 1}
-			// 	}
-			// case 2:
-			// 	m.header = "AWS API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 2}
-			// 	}
-			// case 3:
-			// 	m.header = "ClickUp API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 3}
-			// 	}
-			// default:
-			// 	m.header = "Unknown API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: -1}
-			// 	}
-			// }
+			switch m.cursor {
+			case 2:
+				m.header = "Generate Image Selected"
+				return m, func() tea.Msg {
+					return OpenAIMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case 3:
+				m.header = "Describe Image Selected"
+				return m, func() tea.Msg {
+					return OpenAIMenuMsg{selected: 3, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case 4:
+				m.header = "Usage Selected"
+				return m, func() tea.Msg {
+					return OpenAIMenuMsg{selected: 4, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			}
 		}
 	}
 
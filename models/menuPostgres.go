@@ -16,8 +16,15 @@ type PostgresMenu struct {
 	header       string
 }
 
-func InitialPostgresMenu(token string, refreshToken string, user User) MainMenu {
-	return MainMenu{
+type PostgresMenuMsg struct {
+	selected     int
+	token        string
+	refreshToken string
+	user         User
+}
+
+func InitialPostgresMenu(token string, refreshToken string, user User) PostgresMenu {
+	return PostgresMenu{
 		choices:      []string{"Users", "Channels/Messages", "Database Operations", "About"},
 		cursor:       0,
 		selected:     make(map[int]struct{}),
@@ -49,33 +56,13 @@ func (m PostgresMenu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			m.selected = make(map[int]struct{})
 			m.selected[m.cursor] = struct{}{}
-			// switch m.cursor {
-			// case 0:
-			// 	m.header = "Postgres API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 0}
-			// 	}
-			// case 1:
-			// 	m.header = "OpenAI API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 1}
-			// 	}
-			// case 2:
-			// 	m.header = "AWS API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 2}
-			// 	}
-			// case 3:
-			// 	m.header = "ClickUp API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: 3}
-			// 	}
-			// default:
-			// 	m.header = "Unknown API Selected"
-			// 	return m, func() tea.Msg {
-			// 		return MainMenuMsg{selected: -1}
-			// 	}
-			// }
+			switch m.cursor {
+			case 2:
+				m.header = "Database Operations Selected"
+				return m, func() tea.Msg {
+					return PostgresMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			}
 		}
 	}
 
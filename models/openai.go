@@ -1,7 +1,12 @@
 package models
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/cursor"
@@ -14,6 +19,75 @@ type OpenAI struct {
 	response string
 }
 
+const (
+	defaultChatModel  = "gpt-4o-mini"
+	defaultImageModel = "dall-e-3"
+)
+
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	// Images holds data or https URLs sent alongside Content for vision models.
+	Images []string `json:"-"`
+}
+
+// MarshalJSON sends messages carrying images in the content-parts form the
+// vision models expect, and plain messages as a string.
+func (c ChatMessage) MarshalJSON() ([]byte, error) {
+	if len(c.Images) == 0 {
+		type plain ChatMessage
+		return json.Marshal(plain(c))
+	}
+
+	parts := []map[string]any{{"type": "text", "text": c.Content}}
+	for _, img := range c.Images {
+		parts = append(parts, map[string]any{
+			"type":      "image_url",
+			"image_url": map[string]string{"url": img},
+		})
+	}
+	return json.Marshal(map[string]any{"role": c.Role, "content": parts})
+}
+
+type ChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	// Conversation labels the request in the usage ledger.
+	Conversation string `json:"-"`
+}
+
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type ChatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Message ChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage ChatUsage `json:"usage"`
+}
+
+type ImageRequest struct {
+	Model          string `json:"model,omitempty"`
+	Prompt         string `json:"prompt"`
+	Size           string `json:"size,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	// Conversation labels the request in the usage ledger.
+	Conversation string `json:"-"`
+}
+
+type ImageResponse struct {
+	Data []struct {
+		B64JSON       string `json:"b64_json"`
+		URL           string `json:"url"`
+		RevisedPrompt string `json:"revised_prompt"`
+	} `json:"data"`
+}
+
 type AskAI struct {
 	focusIndex int
 	inputs     []textinput.Model
@@ -137,3 +211,122 @@ func (m AskAI) View() string {
 
 	return b.String()
 }
+
+// SendChat forwards a chat completion request through the crispy-doodle OpenAI proxy.
+// If only the usage ledger can't be written, the response is returned with an
+// error wrapping ErrUsageNotRecorded.
+func SendChat(token string, chat ChatRequest) (*ChatResponse, error) {
+	if chat.Model == "" {
+		chat.Model = defaultChatModel
+	}
+
+	jsonData, err := json.Marshal(chat)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "http://localhost:8080/api/openai/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	var parsed ChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+
+	model := parsed.Model
+	if model == "" {
+		model = chat.Model
+	}
+	err = RecordUsage(UsageEntry{
+		Model:            model,
+		Conversation:     chat.Conversation,
+		PromptTokens:     parsed.Usage.PromptTokens,
+		CompletionTokens: parsed.Usage.CompletionTokens,
+	})
+	if err != nil {
+		return &parsed, fmt.Errorf("%w: %v", ErrUsageNotRecorded, err)
+	}
+
+	return &parsed, nil
+}
+
+// GenerateImage asks the crispy-doodle OpenAI proxy for an image and returns the PNG bytes.
+// Like SendChat, it returns the image with an ErrUsageNotRecorded error when
+// only the ledger write fails.
+func GenerateImage(token string, image ImageRequest) ([]byte, error) {
+	if image.Model == "" {
+		image.Model = defaultImageModel
+	}
+	if image.ResponseFormat == "" {
+		image.ResponseFormat = "b64_json"
+	}
+
+	jsonData, err := json.Marshal(image)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "http://localhost:8080/api/openai/image", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	var parsed ImageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(parsed.Data) == 0 || parsed.Data[0].B64JSON == "" {
+		return nil, fmt.Errorf("no image in response")
+	}
+
+	usageErr := RecordUsage(UsageEntry{
+		Model:        image.Model,
+		Conversation: image.Conversation,
+		Images:       len(parsed.Data),
+	})
+
+	data, err := base64.StdEncoding.DecodeString(parsed.Data[0].B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+	if usageErr != nil {
+		return data, fmt.Errorf("%w: %v", ErrUsageNotRecorded, usageErr)
+	}
+
+	return data, nil
+}
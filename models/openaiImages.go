@@ -0,0 +1,372 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	previewWidth  = 60
+	previewHeight = 20
+)
+
+type ImageGeneration struct {
+	focusIndex int
+	inputs     []textinput.Model
+	loading    bool
+	status     string
+	savedPath  string
+	preview    string
+	// conversation groups this session's requests in the usage ledger.
+	conversation string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type ImageGeneratedMsg struct {
+	path     string
+	preview  string
+	usageErr error
+	err      error
+}
+
+type ImageUploadedMsg struct {
+	key string
+	err error
+}
+
+func InitialImageGeneration(token string, refreshToken string, user User) ImageGeneration {
+	m := ImageGeneration{
+		inputs:       make([]textinput.Model, 2),
+		conversation: NewConversation("Generate Image"),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+
+	m.inputs[0] = newFormInput("Describe the image", 1000, 60)
+	m.inputs[1] = newFormInput("Size (1024x1024)", 16, 20)
+	focusInput(m.inputs, 0)
+
+	return m
+}
+
+func (m ImageGeneration) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Generate Image"), textinput.Blink)
+}
+
+func generateImage(token string, conversation string, prompt string, size string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := GenerateImage(token, ImageRequest{Prompt: prompt, Size: size, Conversation: conversation})
+		var usageErr error
+		if errors.Is(err, ErrUsageNotRecorded) {
+			usageErr, err = err, nil
+		}
+		if err != nil {
+			return ImageGeneratedMsg{err: err}
+		}
+
+		path := fmt.Sprintf("image-%d.png", time.Now().Unix())
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return ImageGeneratedMsg{err: fmt.Errorf("saving image: %w", err)}
+		}
+
+		img, err := DecodeImage(data)
+		if err != nil {
+			return ImageGeneratedMsg{path: path, usageErr: usageErr, err: err}
+		}
+		return ImageGeneratedMsg{path: path, preview: RenderImage(img, previewWidth, previewHeight), usageErr: usageErr}
+	}
+}
+
+func uploadImage(token string, path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ImageUploadedMsg{err: err}
+		}
+		key := "images/" + filepath.Base(path)
+		if err := PutS3Object(token, defaultS3Bucket, key, "image/png", data); err != nil {
+			return ImageUploadedMsg{err: err}
+		}
+		return ImageUploadedMsg{key: key}
+	}
+}
+
+func (m ImageGeneration) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ImageGeneratedMsg:
+		m.loading = false
+		m.savedPath = msg.path
+		ReleaseImage(m.preview)
+		m.preview = msg.preview
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Saved to %s. ctrl+u to upload to S3.", msg.path) + usageWarning(msg.usageErr)
+		return m, nil
+
+	case ImageUploadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Upload failed: %v", msg.err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Uploaded to s3://%s/%s", defaultS3Bucket, msg.key)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.loading && msg.String() != "ctrl+c" {
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			ReleaseImage(m.preview)
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: 1, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		case "ctrl+u":
+			if m.savedPath == "" {
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Uploading..."
+			return m, uploadImage(m.token, m.savedPath)
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				prompt := strings.TrimSpace(m.inputs[0].Value())
+				if prompt == "" {
+					m.status = "Prompt cannot be empty."
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Generating image..."
+				ReleaseImage(m.preview)
+				m.preview = ""
+				m.savedPath = ""
+				return m, generateImage(m.token, m.conversation, prompt, strings.TrimSpace(m.inputs[1].Value()))
+			}
+
+			m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+			return m, focusInput(m.inputs, m.focusIndex)
+		}
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m ImageGeneration) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nGenerate Image\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		b.WriteRune('\n')
+	}
+
+	button := &blurredButton
+	if m.focusIndex == len(m.inputs) {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+
+	if m.preview != "" {
+		b.WriteString(m.preview)
+		b.WriteRune('\n')
+	}
+	if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	b.WriteString(helpStyle.Render("\nesc: back"))
+
+	return b.String()
+}
+
+type ImageVision struct {
+	focusIndex int
+	inputs     []textinput.Model
+	loading    bool
+	status     string
+	answer     string
+	preview    string
+	// conversation groups this session's requests in the usage ledger.
+	conversation string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type ImageVisionMsg struct {
+	answer   string
+	preview  string
+	usageErr error
+	err      error
+}
+
+func InitialImageVision(token string, refreshToken string, user User) ImageVision {
+	m := ImageVision{
+		inputs:       make([]textinput.Model, 2),
+		conversation: NewConversation("Describe Image"),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+
+	m.inputs[0] = newFormInput("Local path or s3://bucket/key", 512, 60)
+	m.inputs[1] = newFormInput("What do you want to know about the image?", 1000, 60)
+	focusInput(m.inputs, 0)
+
+	return m
+}
+
+func (m ImageVision) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Describe Image"), textinput.Blink)
+}
+
+// loadImageSource reads a local file or, for s3://bucket/key sources, the object
+// from the crispy-doodle S3 endpoints.
+func loadImageSource(token string, source string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(source, "s3://"); ok {
+		bucket, key, found := strings.Cut(rest, "/")
+		if !found || key == "" {
+			return nil, fmt.Errorf("expected s3://bucket/key, got %q", source)
+		}
+		return GetS3Object(token, bucket, key)
+	}
+	return os.ReadFile(source)
+}
+
+func askAboutImage(token string, conversation string, source string, question string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := loadImageSource(token, source)
+		if err != nil {
+			return ImageVisionMsg{err: err}
+		}
+
+		preview := ""
+		if img, err := DecodeImage(data); err == nil {
+			preview = RenderImage(img, previewWidth, previewHeight)
+		}
+
+		dataURL := fmt.Sprintf("data:%s;base64,%s", http.DetectContentType(data), base64.StdEncoding.EncodeToString(data))
+		resp, err := SendChat(token, ChatRequest{
+			Messages: []ChatMessage{
+				{Role: "user", Content: question, Images: []string{dataURL}},
+			},
+			Conversation: conversation,
+		})
+		var usageErr error
+		if errors.Is(err, ErrUsageNotRecorded) {
+			usageErr, err = err, nil
+		}
+		if err != nil {
+			return ImageVisionMsg{preview: preview, err: err}
+		}
+
+		return ImageVisionMsg{answer: resp.Choices[0].Message.Content, preview: preview, usageErr: usageErr}
+	}
+}
+
+func (m ImageVision) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ImageVisionMsg:
+		m.loading = false
+		ReleaseImage(m.preview)
+		m.preview = msg.preview
+		m.answer = msg.answer
+		m.status = strings.TrimPrefix(usageWarning(msg.usageErr), "\n")
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.loading && msg.String() != "ctrl+c" {
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			ReleaseImage(m.preview)
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: 1, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				source := strings.TrimSpace(m.inputs[0].Value())
+				question := strings.TrimSpace(m.inputs[1].Value())
+				if source == "" || question == "" {
+					m.status = "Image and question cannot be empty."
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Asking..."
+				m.answer = ""
+				return m, askAboutImage(m.token, m.conversation, source, question)
+			}
+
+			m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+			return m, focusInput(m.inputs, m.focusIndex)
+		}
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m ImageVision) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nDescribe Image\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		b.WriteRune('\n')
+	}
+
+	button := &blurredButton
+	if m.focusIndex == len(m.inputs) {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+
+	if m.preview != "" {
+		b.WriteString(m.preview)
+		b.WriteRune('\n')
+	}
+	if m.answer != "" {
+		b.WriteString(m.answer + "\n")
+	}
+	if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	b.WriteString(helpStyle.Render("\nesc: back"))
+
+	return b.String()
+}
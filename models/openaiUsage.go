@@ -0,0 +1,228 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type usageGrouping int
+
+const (
+	usageByDay usageGrouping = iota
+	usageByModel
+	usageByConversation
+)
+
+func (g usageGrouping) String() string {
+	switch g {
+	case usageByModel:
+		return "Model"
+	case usageByConversation:
+		return "Conversation"
+	default:
+		return "Day"
+	}
+}
+
+type OpenAIUsage struct {
+	grouping      usageGrouping
+	entries       []UsageEntry
+	totals        table.Model
+	budgetInput   textinput.Model
+	editingBudget bool
+	budget        float64
+	status        string
+	token         string
+	refreshToken  string
+	user          User
+}
+
+type UsageLoadedMsg struct {
+	entries []UsageEntry
+	err     error
+}
+
+type BudgetUpdatedMsg struct {
+	budget float64
+}
+
+func InitialOpenAIUsage(token string, refreshToken string, user User, budget float64) OpenAIUsage {
+	return OpenAIUsage{
+		totals: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Day", Width: 24},
+				{Title: "Requests", Width: 9},
+				{Title: "Prompt", Width: 10},
+				{Title: "Completion", Width: 10},
+				{Title: "Images", Width: 7},
+				{Title: "Cost (USD)", Width: 10},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		budgetInput:  newFormInput("Monthly budget in USD (0 to disable)", 12, 40),
+		budget:       budget,
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+func (m OpenAIUsage) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("OpenAI Usage"), func() tea.Msg {
+		entries, err := LoadUsage()
+		return UsageLoadedMsg{entries: entries, err: err}
+	})
+}
+
+func (m OpenAIUsage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case UsageLoadedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error loading usage: %v", msg.err)
+			return m, nil
+		}
+		m.entries = msg.entries
+		m.refreshTotals()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		if m.editingBudget {
+			switch msg.String() {
+			case "esc":
+				m.editingBudget = false
+				m.budgetInput.Blur()
+				return m, nil
+			case "enter":
+				budget, err := strconv.ParseFloat(strings.TrimSpace(m.budgetInput.Value()), 64)
+				if err != nil || budget < 0 {
+					m.status = "Budget must be a positive number."
+					return m, nil
+				}
+				config, err := LoadConfig()
+				if err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+					return m, nil
+				}
+				config.OpenAIBudget = budget
+				if err := SaveConfig(config); err != nil {
+					m.status = fmt.Sprintf("Error: %v", err)
+					return m, nil
+				}
+				m.budget = budget
+				m.editingBudget = false
+				m.budgetInput.Blur()
+				m.status = "Budget saved."
+				return m, func() tea.Msg { return BudgetUpdatedMsg{budget: budget} }
+			}
+			var cmd tea.Cmd
+			m.budgetInput, cmd = m.budgetInput.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: 1, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		case "tab":
+			m.grouping = (m.grouping + 1) % 3
+			m.refreshTotals()
+			return m, nil
+		case "b":
+			m.editingBudget = true
+			m.budgetInput.SetValue(strconv.FormatFloat(m.budget, 'f', 2, 64))
+			return m, m.budgetInput.Focus()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.totals, cmd = m.totals.Update(msg)
+	return m, cmd
+}
+
+func (m *OpenAIUsage) refreshTotals() {
+	var key func(UsageEntry) string
+	switch m.grouping {
+	case usageByModel:
+		key = func(e UsageEntry) string { return e.Model }
+	case usageByConversation:
+		key = func(e UsageEntry) string {
+			if e.Conversation == "" {
+				return "(none)"
+			}
+			return e.Conversation
+		}
+	default:
+		key = func(e UsageEntry) string { return e.Time.Local().Format("2006-01-02") }
+	}
+
+	totals := SummarizeUsage(m.entries, key)
+	if m.grouping == usageByDay {
+		sort.Slice(totals, func(i, j int) bool { return totals[i].Key > totals[j].Key })
+	}
+
+	rows := make([]table.Row, len(totals))
+	for i, t := range totals {
+		rows[i] = table.Row{
+			t.Key,
+			strconv.Itoa(t.Requests),
+			strconv.Itoa(t.PromptTokens),
+			strconv.Itoa(t.CompletionTokens),
+			strconv.Itoa(t.Images),
+			fmt.Sprintf("%.4f", t.Cost),
+		}
+	}
+
+	columns := m.totals.Columns()
+	columns[0].Title = m.grouping.String()
+	m.totals.SetColumns(columns)
+	m.totals.SetRows(rows)
+	m.totals.GotoTop()
+}
+
+func (m OpenAIUsage) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nOpenAI Usage\n\n")
+
+	total := 0.0
+	for _, e := range m.entries {
+		total += e.Cost
+	}
+	fmt.Fprintf(&b, "All time: $%.4f   This month: $%.4f", total, MonthToDateCost())
+	if m.budget > 0 {
+		fmt.Fprintf(&b, "   Budget: $%.2f", m.budget)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(m.totals.View())
+	b.WriteString("\n\n")
+
+	if m.editingBudget {
+		b.WriteString(m.budgetInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: save • esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("tab: group by day/model/conversation • b: set budget • esc: back"))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,387 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Rekognition only accepts inline image bytes up to 5 MB; larger images have to
+// be read from S3.
+const maxRekognitionBytes = 5 * 1024 * 1024
+
+const defaultMinConfidence = 50
+
+// The analyses AnalyzeImage can run, named after their backend endpoints.
+const (
+	analysisLabels     = "labels"
+	analysisFaces      = "faces"
+	analysisText       = "text"
+	analysisModeration = "moderation"
+)
+
+var rekognitionAnalyses = []string{analysisLabels, analysisFaces, analysisText, analysisModeration}
+
+type RekognitionS3Object struct {
+	Bucket string `json:"bucket"`
+	Name   string `json:"name"`
+}
+
+// RekognitionImage is either inline bytes or a reference to an S3 object.
+type RekognitionImage struct {
+	Bytes    []byte               `json:"bytes,omitempty"`
+	S3Object *RekognitionS3Object `json:"s3Object,omitempty"`
+}
+
+// BoundingBox is given as ratios of the image width and height.
+type BoundingBox struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Left   float64 `json:"left"`
+	Top    float64 `json:"top"`
+}
+
+// Detection is one result of any analysis, flattened for display.
+type Detection struct {
+	Analysis   string       `json:"analysis"`
+	Name       string       `json:"name"`
+	Confidence float64      `json:"confidence"`
+	Detail     string       `json:"detail,omitempty"`
+	Box        *BoundingBox `json:"boundingBox,omitempty"`
+}
+
+type rekognitionLabel struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+	Parents    []struct {
+		Name string `json:"name"`
+	} `json:"parents"`
+	Instances []struct {
+		BoundingBox BoundingBox `json:"boundingBox"`
+		Confidence  float64     `json:"confidence"`
+	} `json:"instances"`
+}
+
+type rekognitionFace struct {
+	BoundingBox BoundingBox `json:"boundingBox"`
+	Confidence  float64     `json:"confidence"`
+	AgeRange    *struct {
+		Low  int `json:"low"`
+		High int `json:"high"`
+	} `json:"ageRange"`
+	Emotions []struct {
+		Type       string  `json:"type"`
+		Confidence float64 `json:"confidence"`
+	} `json:"emotions"`
+}
+
+type rekognitionText struct {
+	DetectedText string  `json:"detectedText"`
+	Type         string  `json:"type"`
+	Confidence   float64 `json:"confidence"`
+	Geometry     struct {
+		BoundingBox BoundingBox `json:"boundingBox"`
+	} `json:"geometry"`
+}
+
+type rekognitionModerationLabel struct {
+	Name       string  `json:"name"`
+	ParentName string  `json:"parentName"`
+	Confidence float64 `json:"confidence"`
+}
+
+type rekognitionResponse struct {
+	Labels           []rekognitionLabel           `json:"labels"`
+	FaceDetails      []rekognitionFace            `json:"faceDetails"`
+	TextDetections   []rekognitionText            `json:"textDetections"`
+	ModerationLabels []rekognitionModerationLabel `json:"moderationLabels"`
+}
+
+func postRekognition(token string, endpoint string, body any, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "http://localhost:8080/api/rekognition"+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// AnalyzeImage runs one of the rekognitionAnalyses on img. Labels with several
+// instances yield one detection per instance so each box can be shown.
+func AnalyzeImage(token string, img RekognitionImage, analysis string) ([]Detection, error) {
+	body := map[string]any{"image": img}
+	if analysis != analysisFaces {
+		body["minConfidence"] = defaultMinConfidence
+	} else {
+		body["attributes"] = []string{"ALL"}
+	}
+
+	var resp rekognitionResponse
+	if err := postRekognition(token, "/"+analysis, body, &resp); err != nil {
+		return nil, err
+	}
+
+	var detections []Detection
+	switch analysis {
+	case analysisLabels:
+		for _, l := range resp.Labels {
+			var parents []string
+			for _, p := range l.Parents {
+				parents = append(parents, p.Name)
+			}
+			detail := strings.Join(parents, ", ")
+			if len(l.Instances) == 0 {
+				detections = append(detections, Detection{Analysis: analysis, Name: l.Name, Confidence: l.Confidence, Detail: detail})
+			}
+			for _, inst := range l.Instances {
+				box := inst.BoundingBox
+				detections = append(detections, Detection{Analysis: analysis, Name: l.Name, Confidence: inst.Confidence, Detail: detail, Box: &box})
+			}
+		}
+
+	case analysisFaces:
+		for i, f := range resp.FaceDetails {
+			var details []string
+			if f.AgeRange != nil {
+				details = append(details, fmt.Sprintf("age %d-%d", f.AgeRange.Low, f.AgeRange.High))
+			}
+			if len(f.Emotions) > 0 {
+				top := f.Emotions[0]
+				for _, e := range f.Emotions[1:] {
+					if e.Confidence > top.Confidence {
+						top = e
+					}
+				}
+				details = append(details, strings.ToLower(top.Type))
+			}
+			box := f.BoundingBox
+			detections = append(detections, Detection{
+				Analysis:   analysis,
+				Name:       fmt.Sprintf("Face %d", i+1),
+				Confidence: f.Confidence,
+				Detail:     strings.Join(details, ", "),
+				Box:        &box,
+			})
+		}
+
+	case analysisText:
+		for _, t := range resp.TextDetections {
+			box := t.Geometry.BoundingBox
+			detections = append(detections, Detection{
+				Analysis:   analysis,
+				Name:       t.DetectedText,
+				Confidence: t.Confidence,
+				Detail:     strings.ToLower(t.Type),
+				Box:        &box,
+			})
+		}
+
+	case analysisModeration:
+		for _, l := range resp.ModerationLabels {
+			detections = append(detections, Detection{Analysis: analysis, Name: l.Name, Confidence: l.Confidence, Detail: l.ParentName})
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown analysis %q", analysis)
+	}
+
+	return detections, nil
+}
+
+// ModerationVerdict summarises moderation detections by their top-level
+// categories, or reports the image as clean.
+func ModerationVerdict(detections []Detection) string {
+	var flagged []Detection
+	for _, d := range detections {
+		if d.Analysis == analysisModeration && d.Detail == "" {
+			flagged = append(flagged, d)
+		}
+	}
+	if len(flagged) == 0 {
+		return "clean"
+	}
+
+	sort.Slice(flagged, func(i, j int) bool { return flagged[i].Confidence > flagged[j].Confidence })
+	var parts []string
+	for _, d := range flagged {
+		parts = append(parts, fmt.Sprintf("%s (%.1f%%)", d.Name, d.Confidence))
+	}
+	return "flagged: " + strings.Join(parts, ", ")
+}
+
+// rekognitionImageFor builds the request image for a local path or an
+// s3://bucket/key source, and also returns the bytes for previewing.
+func rekognitionImageFor(token string, source string) (RekognitionImage, []byte, error) {
+	data, err := loadImageSource(token, source)
+	if err != nil {
+		return RekognitionImage{}, nil, err
+	}
+
+	if rest, ok := strings.CutPrefix(source, "s3://"); ok {
+		bucket, key, _ := strings.Cut(rest, "/")
+		return RekognitionImage{S3Object: &RekognitionS3Object{Bucket: bucket, Name: key}}, data, nil
+	}
+	if len(data) > maxRekognitionBytes {
+		return RekognitionImage{}, nil, fmt.Errorf("%s is %s; local images must be under %s, upload it to S3 first", source, formatBytes(int64(len(data))), formatBytes(maxRekognitionBytes))
+	}
+	return RekognitionImage{Bytes: data}, data, nil
+}
+
+// Faces at or above this similarity are treated as the same person.
+const faceMatchThreshold = 90
+
+// IndexedFace is a face stored in a collection. ExternalImageID holds the
+// crispy-doodle user ID the face was indexed for.
+type IndexedFace struct {
+	FaceID          string  `json:"faceId"`
+	ImageID         string  `json:"imageId"`
+	ExternalImageID string  `json:"externalImageId"`
+	Confidence      float64 `json:"confidence"`
+}
+
+type FaceMatch struct {
+	Similarity float64     `json:"similarity"`
+	Face       IndexedFace `json:"face"`
+}
+
+func rekognitionURL(path string, q url.Values) string {
+	return "http://localhost:8080/api/rekognition" + path + "?" + q.Encode()
+}
+
+func ListFaceCollections(token string) ([]string, error) {
+	var resp struct {
+		CollectionIDs []string `json:"collectionIds"`
+	}
+	if err := getBackendJSON(token, rekognitionURL("/collections", url.Values{}), &resp); err != nil {
+		return nil, err
+	}
+	return resp.CollectionIDs, nil
+}
+
+func CreateFaceCollection(token string, collectionID string) error {
+	return postRekognition(token, "/collections", map[string]string{"collectionId": collectionID}, nil)
+}
+
+func DeleteFaceCollection(token string, collectionID string) error {
+	q := url.Values{}
+	q.Set("collectionId", collectionID)
+	req, err := http.NewRequest("DELETE", rekognitionURL("/collections", q), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ListFaces returns every face in a collection, following pagination.
+func ListFaces(token string, collectionID string) ([]IndexedFace, error) {
+	var faces []IndexedFace
+	nextToken := ""
+	for {
+		q := url.Values{}
+		q.Set("collectionId", collectionID)
+		if nextToken != "" {
+			q.Set("nextToken", nextToken)
+		}
+
+		var page struct {
+			Faces     []IndexedFace `json:"faces"`
+			NextToken string        `json:"nextToken"`
+		}
+		if err := getBackendJSON(token, rekognitionURL("/collections/faces", q), &page); err != nil {
+			return nil, err
+		}
+		faces = append(faces, page.Faces...)
+		if page.NextToken == "" {
+			return faces, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// IndexFaces adds the largest face in img to a collection under externalID.
+func IndexFaces(token string, collectionID string, img RekognitionImage, externalID string) ([]IndexedFace, error) {
+	body := map[string]any{
+		"collectionId":    collectionID,
+		"image":           img,
+		"externalImageId": externalID,
+		"maxFaces":        1,
+	}
+
+	var resp struct {
+		FaceRecords []struct {
+			Face IndexedFace `json:"face"`
+		} `json:"faceRecords"`
+	}
+	if err := postRekognition(token, "/collections/faces", body, &resp); err != nil {
+		return nil, err
+	}
+
+	faces := make([]IndexedFace, len(resp.FaceRecords))
+	for i, r := range resp.FaceRecords {
+		faces[i] = r.Face
+	}
+	return faces, nil
+}
+
+// SearchFacesByImage finds faces in a collection that match the largest face in img.
+func SearchFacesByImage(token string, collectionID string, img RekognitionImage) ([]FaceMatch, error) {
+	return searchFaces(token, map[string]any{"collectionId": collectionID, "image": img, "faceMatchThreshold": faceMatchThreshold})
+}
+
+// SearchFaces finds other faces in a collection that match an indexed face.
+func SearchFaces(token string, collectionID string, faceID string) ([]FaceMatch, error) {
+	return searchFaces(token, map[string]any{"collectionId": collectionID, "faceId": faceID, "faceMatchThreshold": faceMatchThreshold})
+}
+
+func searchFaces(token string, body map[string]any) ([]FaceMatch, error) {
+	var resp struct {
+		FaceMatches []FaceMatch `json:"faceMatches"`
+	}
+	if err := postRekognition(token, "/collections/search", body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.FaceMatches, nil
+}
+
+// avatarExternalID derives the user ID from an avatar key such as
+// avatars/<user id>.png, which is how crispy-doodle stores them.
+func avatarExternalID(key string) string {
+	return strings.TrimSuffix(path.Base(key), path.Ext(key))
+}
+
+func isImageKey(key string) bool {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,345 @@
+package models
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var analysisColors = map[string]color.RGBA{
+	analysisLabels: {R: 0x2e, G: 0xcc, B: 0x71, A: 0xff},
+	analysisFaces:  {R: 0x34, G: 0x98, B: 0xdb, A: 0xff},
+	analysisText:   {R: 0xf1, G: 0xc4, B: 0x0f, A: 0xff},
+}
+
+var selectedBoxColor = color.RGBA{R: 0xff, G: 0x00, B: 0xff, A: 0xff}
+
+var detectionSorts = []string{"confidence", "name", "type"}
+
+type RekognitionAnalysis struct {
+	source       textinput.Model
+	results      table.Model
+	detections   []Detection
+	sortBy       int
+	pending      int
+	errs         []string
+	moderated    bool
+	img          image.Image
+	preview      string
+	fromBrowser  bool
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type RekognitionImageMsg struct {
+	image   RekognitionImage
+	decoded image.Image
+	err     error
+}
+
+type RekognitionResultMsg struct {
+	analysis   string
+	detections []Detection
+	err        error
+}
+
+// RekognitionRequestMsg opens the analysis screen on an S3 object.
+type RekognitionRequestMsg struct {
+	bucket string
+	key    string
+}
+
+func InitialRekognitionAnalysis(token string, refreshToken string, user User) RekognitionAnalysis {
+	source := newFormInput("Local path or s3://bucket/key", 512, 60)
+	source.Focus()
+	source.PromptStyle = focusedStyle
+	source.TextStyle = focusedStyle
+
+	return RekognitionAnalysis{
+		source: source,
+		results: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Type", Width: 10},
+				{Title: "Name", Width: 30},
+				{Title: "Confidence", Width: 17},
+				{Title: "Detail", Width: 30},
+			}),
+			table.WithHeight(10),
+		),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+// InitialRekognitionObject analyses an S3 object straight away and returns to
+// the browser when done.
+func InitialRekognitionObject(token string, refreshToken string, user User, bucket string, key string) RekognitionAnalysis {
+	m := InitialRekognitionAnalysis(token, refreshToken, user)
+	m.source.SetValue(fmt.Sprintf("s3://%s/%s", bucket, key))
+	m.fromBrowser = true
+	m.loading = true
+	m.status = "Loading image..."
+	return m
+}
+
+func (m RekognitionAnalysis) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(tea.SetWindowTitle("Rekognition"), loadRekognitionImage(m.token, m.source.Value()))
+	}
+	return tea.Batch(tea.SetWindowTitle("Rekognition"), textinput.Blink)
+}
+
+func loadRekognitionImage(token string, source string) tea.Cmd {
+	return func() tea.Msg {
+		img, data, err := rekognitionImageFor(token, source)
+		if err != nil {
+			return RekognitionImageMsg{err: err}
+		}
+		// Formats the decoders don't know can still be analysed, just not previewed.
+		decoded, _ := DecodeImage(data)
+		// Boxes are redrawn on every cursor move, so keep only what the
+		// preview can show rather than the full-resolution photo.
+		if decoded != nil {
+			if b := decoded.Bounds(); b.Dx() > previewWidth*8 || b.Dy() > previewHeight*16 {
+				decoded = resizeImage(decoded, previewWidth*8, previewHeight*16)
+			}
+		}
+		return RekognitionImageMsg{image: img, decoded: decoded}
+	}
+}
+
+func analyzeImage(token string, img RekognitionImage, analysis string) tea.Cmd {
+	return func() tea.Msg {
+		detections, err := AnalyzeImage(token, img, analysis)
+		return RekognitionResultMsg{analysis: analysis, detections: detections, err: err}
+	}
+}
+
+func (m RekognitionAnalysis) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case RekognitionImageMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.img = msg.decoded
+		m.detections = nil
+		m.errs = nil
+		m.moderated = false
+		m.pending = len(rekognitionAnalyses)
+		m.status = "Analysing..."
+		m.refresh()
+
+		cmds := make([]tea.Cmd, len(rekognitionAnalyses))
+		for i, analysis := range rekognitionAnalyses {
+			cmds[i] = analyzeImage(m.token, msg.image, analysis)
+		}
+		return m, tea.Batch(cmds...)
+
+	case RekognitionResultMsg:
+		m.pending--
+		if msg.err != nil {
+			m.errs = append(m.errs, fmt.Sprintf("%s: %v", msg.analysis, msg.err))
+		} else if msg.analysis == analysisModeration {
+			m.moderated = true
+		}
+		m.detections = append(m.detections, msg.detections...)
+		if m.pending == 0 {
+			m.loading = false
+			m.status = ""
+			m.source.Blur()
+			m.results.Focus()
+		}
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			ReleaseImage(m.preview)
+			if m.fromBrowser {
+				return m, func() tea.Msg { return ShowS3BrowserMsg{} }
+			}
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		case "tab":
+			if m.source.Focused() {
+				m.source.Blur()
+				m.results.Focus()
+				return m, nil
+			}
+			m.results.Blur()
+			return m, m.source.Focus()
+		}
+
+		if m.source.Focused() {
+			if msg.String() == "enter" {
+				source := strings.TrimSpace(m.source.Value())
+				if source == "" {
+					m.status = "Choose an image first."
+					return m, nil
+				}
+				m.loading = true
+				m.status = "Loading image..."
+				return m, loadRekognitionImage(m.token, source)
+			}
+			var cmd tea.Cmd
+			m.source, cmd = m.source.Update(msg)
+			return m, cmd
+		}
+
+		if msg.String() == "s" {
+			m.sortBy = (m.sortBy + 1) % len(detectionSorts)
+			m.refresh()
+			return m, nil
+		}
+		cursor := m.results.Cursor()
+		var cmd tea.Cmd
+		m.results, cmd = m.results.Update(msg)
+		if m.results.Cursor() != cursor {
+			m.renderPreview()
+		}
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.source, cmd = m.source.Update(msg)
+	return m, cmd
+}
+
+// refresh sorts the detections, rebuilds the table rows and redraws the preview.
+func (m *RekognitionAnalysis) refresh() {
+	d := m.detections
+	sort.SliceStable(d, func(i, j int) bool {
+		switch detectionSorts[m.sortBy] {
+		case "name":
+			return strings.ToLower(d[i].Name) < strings.ToLower(d[j].Name)
+		case "type":
+			if d[i].Analysis != d[j].Analysis {
+				return d[i].Analysis < d[j].Analysis
+			}
+		}
+		return d[i].Confidence > d[j].Confidence
+	})
+
+	rows := make([]table.Row, len(d))
+	for i, det := range d {
+		rows[i] = table.Row{det.Analysis, det.Name, confidenceBar(det.Confidence), det.Detail}
+	}
+	m.results.SetRows(rows)
+	m.renderPreview()
+}
+
+func (m *RekognitionAnalysis) renderPreview() {
+	ReleaseImage(m.preview)
+	if m.img == nil {
+		m.preview = ""
+		return
+	}
+	m.preview = RenderImage(drawBoundingBoxes(m.img, m.detections, m.results.Cursor()), previewWidth, previewHeight)
+}
+
+// confidenceBar renders a percentage as a ten-cell bar followed by the value.
+func confidenceBar(confidence float64) string {
+	filled := int(confidence/10 + 0.5)
+	filled = max(0, min(filled, 10))
+	return fmt.Sprintf("%s%s %5.1f%%", strings.Repeat("█", filled), strings.Repeat("░", 10-filled), confidence)
+}
+
+// drawBoundingBoxes outlines every detection with a box on a copy of img, the
+// selected one in a highlight colour so it stands out from the rest. Lines are
+// at least one preview cell thick so they survive scaling down.
+func drawBoundingBoxes(img image.Image, detections []Detection, selected int) image.Image {
+	bounds := img.Bounds()
+	out := image.NewRGBA(bounds)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Src)
+
+	thickness := max(1, bounds.Dx()/previewWidth)
+	for i, d := range detections {
+		if d.Box == nil || i == selected {
+			continue
+		}
+		drawBox(out, d.Box, analysisColors[d.Analysis], thickness)
+	}
+	if selected >= 0 && selected < len(detections) && detections[selected].Box != nil {
+		drawBox(out, detections[selected].Box, selectedBoxColor, thickness)
+	}
+	return out
+}
+
+func drawBox(img *image.RGBA, box *BoundingBox, c color.RGBA, thickness int) {
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	r := image.Rect(
+		bounds.Min.X+int(box.Left*w),
+		bounds.Min.Y+int(box.Top*h),
+		bounds.Min.X+int((box.Left+box.Width)*w),
+		bounds.Min.Y+int((box.Top+box.Height)*h),
+	).Intersect(bounds)
+	if r.Empty() {
+		return
+	}
+
+	src := image.NewUniform(c)
+	edges := []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+thickness),
+		image.Rect(r.Min.X, r.Max.Y-thickness, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+thickness, r.Max.Y),
+		image.Rect(r.Max.X-thickness, r.Min.Y, r.Max.X, r.Max.Y),
+	}
+	for _, e := range edges {
+		draw.Draw(img, e.Intersect(r), src, image.Point{}, draw.Src)
+	}
+}
+
+func (m RekognitionAnalysis) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nRekognition\n\n")
+	b.WriteString(m.source.View())
+	b.WriteString("\n\n")
+
+	if m.preview != "" {
+		b.WriteString(m.preview)
+		b.WriteRune('\n')
+	}
+	if len(m.detections) > 0 || len(m.errs) > 0 {
+		if m.moderated {
+			fmt.Fprintf(&b, "Moderation: %s\n\n", ModerationVerdict(m.detections))
+		}
+		b.WriteString(m.results.View())
+		fmt.Fprintf(&b, "\nSorted by %s\n", detectionSorts[m.sortBy])
+		for _, err := range m.errs {
+			b.WriteString("Error: " + err + "\n")
+		}
+		b.WriteRune('\n')
+	}
+
+	b.WriteString(helpStyle.Render("enter: analyse • tab: switch between image and results • s: sort • esc: back"))
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
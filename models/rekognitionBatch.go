@@ -0,0 +1,234 @@
+package models
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultBatchWorkers   = 4
+	defaultFlagThreshold  = 80
+	maxBatchRetries       = 5
+	batchRetryBaseDelay   = 500 * time.Millisecond
+	maxReportedDetections = 5
+)
+
+// imageAnalyzer runs one analysis; batches take it as a parameter so they can be
+// pointed at a stub returning canned analyses instead of the backend.
+type imageAnalyzer func(img RekognitionImage, analysis string) ([]Detection, error)
+
+func backendAnalyzer(token string) imageAnalyzer {
+	return func(img RekognitionImage, analysis string) ([]Detection, error) {
+		return AnalyzeImage(token, img, analysis)
+	}
+}
+
+type BatchResult struct {
+	Key        string      `json:"key"`
+	Analysis   string      `json:"analysis"`
+	Detections []Detection `json:"detections"`
+	Moderation float64     `json:"moderationConfidence"`
+	Flagged    bool        `json:"flagged"`
+	Retries    int         `json:"retries"`
+	Error      string      `json:"error,omitempty"`
+}
+
+type BatchItemMsg struct {
+	index  int
+	result BatchResult
+}
+
+type BatchDoneMsg struct{}
+
+// batchRun streams results from the batch workers back into the Bubble Tea loop.
+type batchRun struct {
+	events chan tea.Msg
+	cancel context.CancelFunc
+}
+
+func waitForBatch(run *batchRun) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-run.events
+		if !ok {
+			return BatchDoneMsg{}
+		}
+		return msg
+	}
+}
+
+// StartBatch analyses every key in bucket on a pool of workers. Items whose
+// strongest top-level moderation label reaches threshold are flagged.
+func StartBatch(analyze imageAnalyzer, bucket string, keys []string, analysis string, workers int, threshold float64) *batchRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &batchRun{events: make(chan tea.Msg, 64), cancel: cancel}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				img := RekognitionImage{S3Object: &RekognitionS3Object{Bucket: bucket, Name: keys[i]}}
+				detections, retries, err := analyzeWithRetry(ctx, analyze, img, analysis)
+
+				result := BatchResult{Key: keys[i], Analysis: analysis, Detections: detections, Retries: retries}
+				if err != nil {
+					result.Error = err.Error()
+				}
+				for _, d := range detections {
+					if d.Analysis == analysisModeration && d.Detail == "" {
+						result.Moderation = max(result.Moderation, d.Confidence)
+					}
+				}
+				result.Flagged = result.Moderation >= threshold
+
+				select {
+				case run.events <- BatchItemMsg{index: i, result: result}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(run.events)
+		defer wg.Wait()
+		defer close(queue)
+		for i := range keys {
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return run
+}
+
+// analyzeWithRetry retries throttled calls with exponential backoff and jitter,
+// returning how many retries it needed.
+func analyzeWithRetry(ctx context.Context, analyze imageAnalyzer, img RekognitionImage, analysis string) ([]Detection, int, error) {
+	for attempt := 0; ; attempt++ {
+		detections, err := analyze(img, analysis)
+		if err == nil || !isThrottled(err) || attempt == maxBatchRetries {
+			return detections, attempt, err
+		}
+
+		delay := batchRetryBaseDelay << attempt
+		delay += time.Duration(rand.Int63n(int64(delay)))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, attempt, ctx.Err()
+		}
+	}
+}
+
+func isThrottled(err error) bool {
+	var se *statusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	if se.StatusCode == 429 || se.StatusCode == 503 {
+		return true
+	}
+	for _, code := range []string{"ThrottlingException", "ProvisionedThroughputExceeded", "LimitExceeded"} {
+		if strings.Contains(se.Body, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListImageKeys returns the keys of every image under bucket/prefix.
+func ListImageKeys(token string, bucket string, prefix string) ([]string, error) {
+	objects, err := ListAllS3Objects(token, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, obj := range objects {
+		if isImageKey(obj.Key) {
+			keys = append(keys, obj.Key)
+		}
+	}
+	return keys, nil
+}
+
+// WriteBatchReport writes results as <base>.csv and <base>.json, flagged items
+// first, and returns both paths.
+func WriteBatchReport(results []BatchResult, base string) (string, string, error) {
+	sorted := append([]BatchResult(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Flagged != sorted[j].Flagged {
+			return sorted[i].Flagged
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	jsonPath := base + ".json"
+	data, err := json.MarshalIndent(sorted, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("encoding report: %w", err)
+	}
+	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		return "", "", err
+	}
+
+	csvPath := base + ".csv"
+	f, err := os.Create(csvPath)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"key", "analysis", "flagged", "moderation_confidence", "top_detections", "retries", "error"})
+	for _, r := range sorted {
+		w.Write([]string{
+			r.Key,
+			r.Analysis,
+			strconv.FormatBool(r.Flagged),
+			strconv.FormatFloat(r.Moderation, 'f', 1, 64),
+			topDetections(r.Detections),
+			strconv.Itoa(r.Retries),
+			r.Error,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", "", err
+	}
+
+	return csvPath, jsonPath, nil
+}
+
+func topDetections(detections []Detection) string {
+	sorted := append([]Detection(nil), detections...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Confidence > sorted[j].Confidence })
+
+	var parts []string
+	for _, d := range sorted[:min(len(sorted), maxReportedDetections)] {
+		parts = append(parts, fmt.Sprintf("%s (%.1f%%)", d.Name, d.Confidence))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,227 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cannedAnalyses is a stub imageAnalyzer answering from a map keyed by image
+// name. It records how often each image was analysed.
+type cannedAnalyses struct {
+	mu      sync.Mutex
+	results map[string][]Detection
+	// throttle is how many times each image is answered with a 429 first.
+	throttle map[string]int
+	calls    map[string]int
+}
+
+func (c *cannedAnalyses) analyze(img RekognitionImage, analysis string) ([]Detection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := img.S3Object.Name
+	c.calls[key]++
+	if c.calls[key] <= c.throttle[key] {
+		return nil, &statusError{StatusCode: http.StatusTooManyRequests, Body: "ThrottlingException: Rate exceeded"}
+	}
+	return c.results[key], nil
+}
+
+// collectBatch waits for every result of run, keyed by index.
+func collectBatch(t *testing.T, run *batchRun) map[int]BatchResult {
+	t.Helper()
+	results := make(map[int]BatchResult)
+	for {
+		msgs := make(chan any, 1)
+		go func() { msgs <- waitForBatch(run)() }()
+		select {
+		case msg := <-msgs:
+			switch msg := msg.(type) {
+			case BatchItemMsg:
+				results[msg.index] = msg.result
+			case BatchDoneMsg:
+				return results
+			default:
+				t.Fatalf("unexpected message %T", msg)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("batch did not finish")
+		}
+	}
+}
+
+func moderation(name string, confidence float64, parent string) Detection {
+	return Detection{Analysis: analysisModeration, Name: name, Confidence: confidence, Detail: parent}
+}
+
+func TestStartBatchFlagsByThreshold(t *testing.T) {
+	stub := &cannedAnalyses{
+		results: map[string][]Detection{
+			"violent.png": {moderation("Violence", 91.5, ""), moderation("Weapons", 99, "Violence")},
+			"edge.png":    {moderation("Suggestive", 80, "")},
+			// Only top-level labels count, however confident a child label is.
+			"child.png": {moderation("Suggestive", 60, ""), moderation("Swimwear", 95, "Suggestive")},
+			"clean.png": nil,
+		},
+		calls: make(map[string]int),
+	}
+	keys := []string{"violent.png", "edge.png", "child.png", "clean.png"}
+
+	results := collectBatch(t, StartBatch(stub.analyze, "avatars", keys, analysisModeration, 2, 80))
+
+	want := map[string]struct {
+		flagged    bool
+		moderation float64
+	}{
+		"violent.png": {true, 91.5},
+		"edge.png":    {true, 80},
+		"child.png":   {false, 60},
+		"clean.png":   {false, 0},
+	}
+	if len(results) != len(keys) {
+		t.Fatalf("got %d results, want %d", len(results), len(keys))
+	}
+	for i, key := range keys {
+		r := results[i]
+		if r.Key != key || r.Analysis != analysisModeration || r.Error != "" {
+			t.Errorf("result %d = %+v, want %s analysed without error", i, r, key)
+		}
+		if r.Flagged != want[key].flagged || r.Moderation != want[key].moderation {
+			t.Errorf("%s: flagged %v at %.1f, want %v at %.1f", key, r.Flagged, r.Moderation, want[key].flagged, want[key].moderation)
+		}
+	}
+}
+
+func TestStartBatchRetriesThrottledCalls(t *testing.T) {
+	stub := &cannedAnalyses{
+		results:  map[string][]Detection{"a.png": {{Analysis: analysisLabels, Name: "Cat", Confidence: 99}}},
+		throttle: map[string]int{"a.png": 1},
+		calls:    make(map[string]int),
+	}
+
+	results := collectBatch(t, StartBatch(stub.analyze, "avatars", []string{"a.png"}, analysisLabels, 1, 80))
+
+	r := results[0]
+	if r.Error != "" || r.Retries != 1 || len(r.Detections) != 1 {
+		t.Errorf("result = %+v, want one detection after one retry", r)
+	}
+	if stub.calls["a.png"] != 2 {
+		t.Errorf("analysed %d times, want 2", stub.calls["a.png"])
+	}
+}
+
+func TestAnalyzeWithRetryGivesUpOnOtherErrors(t *testing.T) {
+	calls := 0
+	analyze := func(RekognitionImage, string) ([]Detection, error) {
+		calls++
+		return nil, &statusError{StatusCode: http.StatusBadRequest, Body: "InvalidImageFormatException"}
+	}
+
+	_, retries, err := analyzeWithRetry(t.Context(), analyze, RekognitionImage{}, analysisLabels)
+	var se *statusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want the 400", err)
+	}
+	if calls != 1 || retries != 0 {
+		t.Errorf("calls = %d, retries = %d, want a single attempt", calls, retries)
+	}
+}
+
+func TestStartBatchCancel(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	analyze := func(img RekognitionImage, analysis string) ([]Detection, error) {
+		if img.S3Object.Name == "slow.png" {
+			close(started)
+			<-release
+		}
+		return nil, nil
+	}
+	keys := []string{"first.png", "slow.png", "never.png"}
+
+	run := StartBatch(analyze, "avatars", keys, analysisLabels, 1, 80)
+	first, ok := waitForBatch(run)().(BatchItemMsg)
+	if !ok || first.result.Key != "first.png" {
+		t.Fatalf("first message = %+v, want first.png", first)
+	}
+	<-started
+	run.cancel()
+	close(release)
+
+	results := collectBatch(t, run)
+	if _, ok := results[2]; ok {
+		t.Errorf("never.png was analysed after the run was cancelled")
+	}
+}
+
+func TestWriteBatchReport(t *testing.T) {
+	results := []BatchResult{
+		{Key: "b.png", Analysis: analysisLabels, Detections: []Detection{
+			{Name: "Dog", Confidence: 70}, {Name: "Cat", Confidence: 95.25},
+		}},
+		{Key: "c.png", Analysis: analysisModeration, Moderation: 91.5, Flagged: true, Retries: 2},
+		{Key: "a.png", Analysis: analysisLabels, Error: "access denied"},
+	}
+	base := filepath.Join(t.TempDir(), "report")
+
+	csvPath, jsonPath, err := WriteBatchReport(results, base)
+	if err != nil {
+		t.Fatalf("WriteBatchReport: %v", err)
+	}
+	if csvPath != base+".csv" || jsonPath != base+".json" {
+		t.Errorf("paths = %s, %s", csvPath, jsonPath)
+	}
+
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	want := [][]string{
+		{"key", "analysis", "flagged", "moderation_confidence", "top_detections", "retries", "error"},
+		{"c.png", "moderation", "true", "91.5", "", "2", ""},
+		{"a.png", "labels", "false", "0.0", "", "0", "access denied"},
+		{"b.png", "labels", "false", "0.0", "Cat (95.2%); Dog (70.0%)", "0", ""},
+	}
+	if got, wantCSV := joinRows(rows), joinRows(want); got != wantCSV {
+		t.Errorf("CSV:\n%s\nwant:\n%s", got, wantCSV)
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []BatchResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("decoding JSON: %v", err)
+	}
+	var keys []string
+	for _, r := range decoded {
+		keys = append(keys, r.Key)
+	}
+	if strings.Join(keys, ",") != "c.png,a.png,b.png" {
+		t.Errorf("JSON order = %v, want flagged first, then by key", keys)
+	}
+	if len(decoded[2].Detections) != 2 || decoded[0].Retries != 2 {
+		t.Errorf("JSON lost fields: %+v", decoded)
+	}
+}
+
+func joinRows(rows [][]string) string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, " | ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxVisibleBatchItems = 10
+
+type RekognitionBatch struct {
+	focusIndex   int
+	inputs       []textinput.Model
+	analysis     int
+	threshold    float64
+	keys         []string
+	results      []BatchResult
+	finished     int
+	run          *batchRun
+	bar          progress.Model
+	running      bool
+	done         bool
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type BatchKeysMsg struct {
+	bucket string
+	keys   []string
+	err    error
+}
+
+func InitialRekognitionBatch(token string, refreshToken string, user User) RekognitionBatch {
+	m := RekognitionBatch{
+		inputs:       make([]textinput.Model, 3),
+		analysis:     indexOf(rekognitionAnalyses, analysisModeration),
+		bar:          progress.New(progress.WithDefaultGradient(), progress.WithWidth(60)),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+
+	m.inputs[0] = newFormInput(fmt.Sprintf("s3://%s/avatars/", defaultS3Bucket), 1024, 60)
+	m.inputs[1] = newFormInput("Flag at moderation confidence (%)", 5, 40)
+	m.inputs[1].SetValue(strconv.Itoa(defaultFlagThreshold))
+	m.inputs[2] = newFormInput("Concurrent requests", 3, 40)
+	m.inputs[2].SetValue(strconv.Itoa(defaultBatchWorkers))
+	focusInput(m.inputs, 0)
+
+	return m
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m RekognitionBatch) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Batch Rekognition"), textinput.Blink)
+}
+
+func (m RekognitionBatch) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case BatchKeysMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if len(msg.keys) == 0 {
+			m.status = "No images found there."
+			return m, nil
+		}
+		workers, _ := strconv.Atoi(strings.TrimSpace(m.inputs[2].Value()))
+		m.keys = msg.keys
+		m.results = make([]BatchResult, len(msg.keys))
+		m.finished = 0
+		m.running = true
+		m.done = false
+		m.status = fmt.Sprintf("Analysing %d images with %d workers...", len(m.keys), max(workers, 1))
+		m.run = StartBatch(backendAnalyzer(m.token), msg.bucket, m.keys, rekognitionAnalyses[m.analysis], workers, m.threshold)
+		return m, waitForBatch(m.run)
+
+	case BatchItemMsg:
+		m.results[msg.index] = msg.result
+		m.finished++
+		return m, waitForBatch(m.run)
+
+	case BatchDoneMsg:
+		m.running = false
+		m.done = true
+		m.status = m.summary()
+
+		var results []BatchResult
+		for _, r := range m.results {
+			if r.Key != "" {
+				results = append(results, r)
+			}
+		}
+		csvPath, jsonPath, err := WriteBatchReport(results, fmt.Sprintf("rekognition-report-%d", time.Now().Unix()))
+		if err != nil {
+			m.status += fmt.Sprintf("\nError writing report: %v", err)
+		} else {
+			m.status += fmt.Sprintf("\nReport saved to %s and %s", csvPath, jsonPath)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			if m.run != nil {
+				m.run.cancel()
+			}
+			return m, tea.Quit
+		case "esc":
+			if m.running {
+				m.run.cancel()
+				m.status = "Cancelling..."
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		}
+		if m.loading || m.running {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+t":
+			m.analysis = (m.analysis + 1) % len(rekognitionAnalyses)
+			return m, nil
+		case "tab", "shift+tab", "enter", "up", "down":
+			s := msg.String()
+
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				bucket, prefix, err := parseS3Location(m.inputs[0].Value())
+				if err != nil {
+					m.status = err.Error()
+					return m, nil
+				}
+				threshold, err := strconv.ParseFloat(strings.TrimSpace(m.inputs[1].Value()), 64)
+				if err != nil || threshold < 0 || threshold > 100 {
+					m.status = "Threshold must be a percentage between 0 and 100."
+					return m, nil
+				}
+				if workers, err := strconv.Atoi(strings.TrimSpace(m.inputs[2].Value())); err != nil || workers < 1 {
+					m.status = "Concurrent requests must be at least 1."
+					return m, nil
+				}
+
+				m.threshold = threshold
+				m.loading = true
+				m.done = false
+				m.status = "Listing images..."
+				token := m.token
+				return m, func() tea.Msg {
+					keys, err := ListImageKeys(token, bucket, prefix)
+					return BatchKeysMsg{bucket: bucket, keys: keys, err: err}
+				}
+			}
+
+			m.focusIndex = nextFocus(m.focusIndex, len(m.inputs), s)
+			return m, focusInput(m.inputs, m.focusIndex)
+		}
+	}
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m RekognitionBatch) summary() string {
+	flagged, failed, retries := 0, 0, 0
+	for _, r := range m.results {
+		if r.Flagged {
+			flagged++
+		}
+		if r.Error != "" {
+			failed++
+		}
+		retries += r.Retries
+	}
+	return fmt.Sprintf("%d/%d analysed • %d flagged • %d failed • %d throttled retries", m.finished, len(m.keys), flagged, failed, retries)
+}
+
+func (m RekognitionBatch) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nBatch Rekognition\n\n")
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		b.WriteRune('\n')
+	}
+	fmt.Fprintf(&b, "Analysis: %s\n", focusedStyle.Render(rekognitionAnalyses[m.analysis]))
+
+	button := &blurredButton
+	if m.focusIndex == len(m.inputs) {
+		button = &focusedButton
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
+
+	if len(m.keys) > 0 {
+		b.WriteString(m.bar.ViewAs(float64(m.finished) / float64(len(m.keys))))
+		b.WriteString("\n")
+		if m.running {
+			b.WriteString(m.summary() + "\n")
+		}
+		b.WriteString("\n")
+
+		// Flagged and failed items are what need attention, so they are listed.
+		shown := 0
+		for _, r := range m.results {
+			if shown == maxVisibleBatchItems {
+				break
+			}
+			switch {
+			case r.Error != "":
+				fmt.Fprintf(&b, "✗ %-50.50s %s\n", r.Key, r.Error)
+			case r.Flagged:
+				fmt.Fprintf(&b, "⚑ %-50.50s %s\n", r.Key, ModerationVerdict(r.Detections))
+			default:
+				continue
+			}
+			shown++
+		}
+		b.WriteString("\n")
+	}
+
+	if m.running {
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("enter: start • ctrl+t: change analysis • esc: back"))
+	}
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
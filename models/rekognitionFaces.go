@@ -0,0 +1,501 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type facesMode int
+
+const (
+	facesCollections facesMode = iota
+	facesIndexed
+	facesMatches
+)
+
+// The prompts the face collections screen can ask for.
+const (
+	promptNewCollection = "new"
+	promptIndex         = "index"
+	promptSearch        = "search"
+)
+
+type FaceCollections struct {
+	mode         facesMode
+	list         table.Model
+	collections  []string
+	collection   string
+	faces        []IndexedFace
+	users        map[string]User
+	input        textinput.Model
+	prompt       string
+	confirming   bool
+	loading      bool
+	status       string
+	indexed      string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type FaceCollectionsMsg struct {
+	collections []string
+	users       map[string]User
+	err         error
+}
+
+type FacesListedMsg struct {
+	faces []IndexedFace
+	err   error
+}
+
+type FacesIndexedMsg struct {
+	indexed int
+	skipped []string
+	err     error
+}
+
+type FaceMatchesMsg struct {
+	title      string
+	duplicates bool
+	rows       []table.Row
+	err        error
+}
+
+func InitialFaceCollections(token string, refreshToken string, user User) FaceCollections {
+	m := FaceCollections{
+		users:        make(map[string]User),
+		input:        newFormInput("", 512, 60),
+		loading:      true,
+		status:       "Loading collections...",
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+	m.input.PromptStyle = focusedStyle
+	m.input.TextStyle = focusedStyle
+	m.list = table.New(table.WithHeight(15), table.WithFocused(true))
+	m.setMode(facesCollections)
+	return m
+}
+
+func (m FaceCollections) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Face Collections"), loadFaceCollections(m.token))
+}
+
+// loadFaceCollections also fetches the users so face IDs can be shown by name.
+func loadFaceCollections(token string) tea.Cmd {
+	return func() tea.Msg {
+		collections, err := ListFaceCollections(token)
+		if err != nil {
+			return FaceCollectionsMsg{err: err}
+		}
+		users, err := GetAllUsers(token)
+		if err != nil {
+			return FaceCollectionsMsg{err: fmt.Errorf("loading users: %w", err)}
+		}
+
+		byID := make(map[string]User, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
+		sort.Strings(collections)
+		return FaceCollectionsMsg{collections: collections, users: byID}
+	}
+}
+
+func listFaces(token string, collection string) tea.Cmd {
+	return func() tea.Msg {
+		faces, err := ListFaces(token, collection)
+		return FacesListedMsg{faces: faces, err: err}
+	}
+}
+
+// indexAvatars indexes one image or every image under an s3://bucket/prefix/,
+// using each file name as the user ID the face belongs to.
+func indexAvatars(token string, collection string, location string) tea.Cmd {
+	return func() tea.Msg {
+		bucket, key, err := parseS3Location(location)
+		if err != nil {
+			return FacesIndexedMsg{err: err}
+		}
+
+		var keys []string
+		// parseS3Location always returns a prefix, so a single image arrives as "key.png/".
+		if single := strings.TrimSuffix(key, "/"); !strings.HasSuffix(location, "/") && isImageKey(single) {
+			keys = []string{single}
+		} else {
+			objects, err := ListAllS3Objects(token, bucket, key)
+			if err != nil {
+				return FacesIndexedMsg{err: err}
+			}
+			for _, obj := range objects {
+				if isImageKey(obj.Key) {
+					keys = append(keys, obj.Key)
+				}
+			}
+		}
+
+		indexed := 0
+		var skipped []string
+		for _, k := range keys {
+			img := RekognitionImage{S3Object: &RekognitionS3Object{Bucket: bucket, Name: k}}
+			faces, err := IndexFaces(token, collection, img, avatarExternalID(k))
+			switch {
+			case err != nil:
+				skipped = append(skipped, fmt.Sprintf("%s: %v", k, err))
+			case len(faces) == 0:
+				skipped = append(skipped, k+": no face found")
+			default:
+				indexed++
+			}
+		}
+		return FacesIndexedMsg{indexed: indexed, skipped: skipped}
+	}
+}
+
+func (m FaceCollections) userName(id string) string {
+	if u, ok := m.users[id]; ok {
+		return u.Name
+	}
+	return "unknown user"
+}
+
+func (m FaceCollections) searchByImage(source string) tea.Cmd {
+	token, collection := m.token, m.collection
+	return func() tea.Msg {
+		img, _, err := rekognitionImageFor(token, source)
+		if err != nil {
+			return FaceMatchesMsg{err: err}
+		}
+		matches, err := SearchFacesByImage(token, collection, img)
+		if err != nil {
+			return FaceMatchesMsg{err: err}
+		}
+
+		rows := make([]table.Row, len(matches))
+		for i, match := range matches {
+			id := match.Face.ExternalImageID
+			rows[i] = table.Row{fmt.Sprintf("%.1f%%", match.Similarity), m.userName(id), m.users[id].Email, id}
+		}
+		return FaceMatchesMsg{title: "Users matching " + source, rows: rows}
+	}
+}
+
+// findDuplicates searches the collection for each indexed face and reports
+// pairs of different users whose avatars show the same person.
+func (m FaceCollections) findDuplicates() tea.Cmd {
+	token, collection, faces := m.token, m.collection, m.faces
+	return func() tea.Msg {
+		seen := make(map[[2]string]bool)
+		var rows []table.Row
+		for _, face := range faces {
+			matches, err := SearchFaces(token, collection, face.FaceID)
+			if err != nil {
+				return FaceMatchesMsg{err: err}
+			}
+			for _, match := range matches {
+				a, b := face.ExternalImageID, match.Face.ExternalImageID
+				if a == b {
+					continue
+				}
+				pair := [2]string{min(a, b), max(a, b)}
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+				rows = append(rows, table.Row{fmt.Sprintf("%.1f%%", match.Similarity), m.userName(pair[0]), m.userName(pair[1]), pair[0] + " / " + pair[1]})
+			}
+		}
+		return FaceMatchesMsg{title: "Possible duplicate accounts in " + collection, duplicates: true, rows: rows}
+	}
+}
+
+// setMode swaps the table columns; rows must be set afterwards.
+func (m *FaceCollections) setMode(mode facesMode) {
+	m.mode = mode
+	m.list.SetRows(nil)
+	switch mode {
+	case facesCollections:
+		m.list.SetColumns([]table.Column{{Title: "Collection", Width: 40}})
+	case facesIndexed:
+		m.list.SetColumns([]table.Column{
+			{Title: "User", Width: 25},
+			{Title: "User ID", Width: 38},
+			{Title: "Face ID", Width: 38},
+		})
+	case facesMatches:
+		m.list.SetColumns([]table.Column{
+			{Title: "Similarity", Width: 10},
+			{Title: "User", Width: 25},
+			{Title: "Email", Width: 30},
+			{Title: "User ID", Width: 38},
+		})
+	}
+	m.list.GotoTop()
+}
+
+func (m *FaceCollections) setRows(rows []table.Row) {
+	m.list.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.list.Cursor(); c < 0 || c >= len(rows) {
+		m.list.SetCursor(0)
+	}
+}
+
+func (m FaceCollections) selectedCollection() (string, bool) {
+	i := m.list.Cursor()
+	if m.mode != facesCollections || i < 0 || i >= len(m.collections) {
+		return "", false
+	}
+	return m.collections[i], true
+}
+
+func (m *FaceCollections) showCollections() {
+	m.setMode(facesCollections)
+	rows := make([]table.Row, len(m.collections))
+	for i, c := range m.collections {
+		rows[i] = table.Row{c}
+	}
+	m.setRows(rows)
+}
+
+func (m *FaceCollections) showFaces() {
+	m.setMode(facesIndexed)
+	rows := make([]table.Row, len(m.faces))
+	for i, f := range m.faces {
+		rows[i] = table.Row{m.userName(f.ExternalImageID), f.ExternalImageID, f.FaceID}
+	}
+	m.setRows(rows)
+}
+
+func (m *FaceCollections) ask(prompt string, placeholder string) tea.Cmd {
+	m.prompt = prompt
+	m.input.Placeholder = placeholder
+	m.input.SetValue("")
+	return m.input.Focus()
+}
+
+func (m FaceCollections) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case FaceCollectionsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.collections = msg.collections
+		m.users = msg.users
+		m.status = fmt.Sprintf("%d collections", len(m.collections))
+		m.showCollections()
+		return m, nil
+
+	case FacesListedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.faces = msg.faces
+		m.status = fmt.Sprintf("%d faces in %s", len(m.faces), m.collection)
+		if m.indexed != "" {
+			m.status = m.indexed + " " + m.status
+			m.indexed = ""
+		}
+		m.showFaces()
+		return m, nil
+
+	case FacesIndexedMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.indexed = fmt.Sprintf("Indexed %d faces.", msg.indexed)
+		if len(msg.skipped) > 0 {
+			m.indexed += fmt.Sprintf(" Skipped %d:\n%s", len(msg.skipped), strings.Join(msg.skipped, "\n"))
+		}
+		m.status = m.indexed + " Refreshing faces..."
+		return m, listFaces(m.token, m.collection)
+
+	case FaceMatchesMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.setMode(facesMatches)
+		if msg.duplicates {
+			m.list.SetColumns([]table.Column{
+				{Title: "Similarity", Width: 10},
+				{Title: "User", Width: 25},
+				{Title: "Same face as", Width: 25},
+				{Title: "User IDs", Width: 50},
+			})
+		}
+		m.setRows(msg.rows)
+		m.status = fmt.Sprintf("%s: %d found", msg.title, len(msg.rows))
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		if m.confirming {
+			m.confirming = false
+			if msg.String() != "y" {
+				m.status = "Cancelled."
+				return m, nil
+			}
+			collection, ok := m.selectedCollection()
+			if !ok {
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Deleting " + collection + "..."
+			return m, func() tea.Msg {
+				if err := DeleteFaceCollection(m.token, collection); err != nil {
+					return FaceCollectionsMsg{err: err}
+				}
+				return loadFaceCollections(m.token)()
+			}
+		}
+
+		if m.prompt != "" {
+			switch msg.String() {
+			case "esc":
+				m.prompt = ""
+				m.input.Blur()
+				return m, nil
+			case "enter":
+				value := strings.TrimSpace(m.input.Value())
+				prompt := m.prompt
+				m.prompt = ""
+				m.input.Blur()
+				if value == "" {
+					return m, nil
+				}
+				m.loading = true
+				switch prompt {
+				case promptNewCollection:
+					m.status = "Creating " + value + "..."
+					return m, func() tea.Msg {
+						if err := CreateFaceCollection(m.token, value); err != nil {
+							return FaceCollectionsMsg{err: err}
+						}
+						return loadFaceCollections(m.token)()
+					}
+				case promptIndex:
+					m.status = "Indexing faces from " + value + "..."
+					return m, indexAvatars(m.token, m.collection, value)
+				case promptSearch:
+					m.status = "Searching..."
+					return m, m.searchByImage(value)
+				}
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		switch m.mode {
+		case facesCollections:
+			switch msg.String() {
+			case "esc":
+				return m, func() tea.Msg {
+					return MainMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+				}
+			case "n":
+				return m, m.ask(promptNewCollection, "Collection ID, e.g. avatars")
+			case "x":
+				if collection, ok := m.selectedCollection(); ok {
+					m.confirming = true
+					m.status = fmt.Sprintf("Delete collection %s and all its faces? (y/n)", collection)
+				}
+				return m, nil
+			case "enter":
+				collection, ok := m.selectedCollection()
+				if !ok {
+					return m, nil
+				}
+				m.collection = collection
+				m.loading = true
+				m.status = "Loading faces..."
+				return m, listFaces(m.token, m.collection)
+			}
+
+		case facesIndexed:
+			switch msg.String() {
+			case "esc":
+				m.showCollections()
+				m.status = fmt.Sprintf("%d collections", len(m.collections))
+				return m, nil
+			case "i":
+				return m, m.ask(promptIndex, fmt.Sprintf("s3://%s/avatars/ or s3://bucket/key.png", defaultS3Bucket))
+			case "f":
+				return m, m.ask(promptSearch, "Local path or s3://bucket/key")
+			case "D":
+				if len(m.faces) == 0 {
+					return m, nil
+				}
+				m.loading = true
+				m.status = fmt.Sprintf("Searching %d faces for duplicates...", len(m.faces))
+				return m, m.findDuplicates()
+			}
+
+		case facesMatches:
+			if msg.String() == "esc" {
+				m.showFaces()
+				m.status = fmt.Sprintf("%d faces in %s", len(m.faces), m.collection)
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m FaceCollections) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nFace Collections")
+	if m.mode != facesCollections {
+		b.WriteString(" › " + m.collection)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.list.View())
+	b.WriteString("\n\n")
+
+	if m.prompt != "" {
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: confirm • esc: cancel"))
+	} else {
+		switch m.mode {
+		case facesCollections:
+			b.WriteString(helpStyle.Render("enter: open • n: new collection • x: delete • esc: back"))
+		case facesIndexed:
+			b.WriteString(helpStyle.Render("i: index avatars from S3 • f: search by face • D: find duplicate accounts • esc: collections"))
+		case facesMatches:
+			b.WriteString(helpStyle.Render("esc: back to faces"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,363 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultS3Bucket = "crispy-doodle"
+
+type S3Bucket struct {
+	Name         string    `json:"name"`
+	CreationDate time.Time `json:"creationDate"`
+}
+
+type S3Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	StorageClass string    `json:"storageClass"`
+	ETag         string    `json:"etag"`
+}
+
+type S3Listing struct {
+	Prefixes              []string   `json:"prefixes"`
+	Objects               []S3Object `json:"objects"`
+	NextContinuationToken string     `json:"nextContinuationToken"`
+}
+
+type S3ObjectInfo struct {
+	ContentType  string
+	Size         int64
+	LastModified string
+	ETag         string
+	Metadata     map[string]string
+}
+
+// s3API is the crispy-doodle S3 endpoint. S3_API_URL points it at another
+// S3-compatible stand-in, such as a local one for testing.
+func s3API() string {
+	if api := os.Getenv("S3_API_URL"); api != "" {
+		return strings.TrimSuffix(api, "/")
+	}
+	return crispyDoodleAPI + "/s3"
+}
+
+func s3URL(path string, q url.Values) string {
+	return s3API() + path + "?" + q.Encode()
+}
+
+func s3ObjectURL(bucket string, key string) string {
+	q := url.Values{}
+	q.Set("bucket", bucket)
+	q.Set("key", key)
+	return s3URL("/object", q)
+}
+
+func ListS3Buckets(token string) ([]S3Bucket, error) {
+	var buckets []S3Bucket
+	if err := getBackendJSON(token, s3URL("/buckets", url.Values{}), &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// ListS3Objects lists one page of bucket under prefix. With a "/" delimiter,
+// keys below the next "/" are rolled up into Prefixes like folders.
+func ListS3Objects(token string, bucket string, prefix string, delimiter string, continuationToken string) (*S3Listing, error) {
+	q := url.Values{}
+	q.Set("bucket", bucket)
+	q.Set("prefix", prefix)
+	if delimiter != "" {
+		q.Set("delimiter", delimiter)
+	}
+	if continuationToken != "" {
+		q.Set("continuationToken", continuationToken)
+	}
+
+	var listing S3Listing
+	if err := getBackendJSON(token, s3URL("/objects", q), &listing); err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// GetS3Object downloads an object through the crispy-doodle S3 endpoints.
+func GetS3Object(token string, bucket string, key string) ([]byte, error) {
+	return GetS3ObjectRange(token, bucket, key, 0)
+}
+
+// GetS3ObjectRange downloads at most limit bytes of an object, or all of it when limit is 0.
+func GetS3ObjectRange(token string, bucket string, key string, limit int64) ([]byte, error) {
+	req, err := http.NewRequest("GET", s3ObjectURL(bucket, key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if limit > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", limit-1))
+	}
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if limit > 0 {
+		body = io.LimitReader(resp.Body, limit)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return data, nil
+}
+
+func HeadS3Object(token string, bucket string, key string) (*S3ObjectInfo, error) {
+	req, err := http.NewRequest("HEAD", s3ObjectURL(bucket, key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	info := &S3ObjectInfo{
+		ContentType:  resp.Header.Get("Content-Type"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ETag:         resp.Header.Get("ETag"),
+		Metadata:     make(map[string]string),
+	}
+	info.Size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	for name, values := range resp.Header {
+		if len(values) > 0 && strings.HasPrefix(strings.ToLower(name), "x-amz-meta-") {
+			info.Metadata[name[len("x-amz-meta-"):]] = values[0]
+		}
+	}
+
+	return info, nil
+}
+
+// PutS3Object uploads data as a single object through the crispy-doodle S3 endpoints.
+func PutS3Object(token string, bucket string, key string, contentType string, data []byte) error {
+	return PutS3ObjectFrom(token, bucket, key, contentType, bytes.NewReader(data), int64(len(data)))
+}
+
+// OpenS3Object starts downloading an object; the caller reads and closes the body.
+func OpenS3Object(token string, bucket string, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", s3ObjectURL(bucket, key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// PutS3ObjectFrom streams r into an object. size is its length, or -1 when
+// it isn't known.
+func PutS3ObjectFrom(token string, bucket string, key string, contentType string, r io.Reader, size int64) error {
+	req, err := http.NewRequest("PUT", s3ObjectURL(bucket, key), r)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+type S3Part struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size,omitempty"`
+}
+
+func s3MultipartURL(path string, bucket string, key string, uploadID string) string {
+	q := url.Values{}
+	q.Set("bucket", bucket)
+	q.Set("key", key)
+	if uploadID != "" {
+		q.Set("uploadId", uploadID)
+	}
+	return s3URL("/multipart"+path, q)
+}
+
+func CreateS3MultipartUpload(token string, bucket string, key string, contentType string) (string, error) {
+	req, err := http.NewRequest("POST", s3MultipartURL("", bucket, key, ""), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var parsed struct {
+		UploadID string `json:"uploadId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	return parsed.UploadID, nil
+}
+
+// ListS3Parts returns the parts already stored for an unfinished multipart upload.
+func ListS3Parts(token string, bucket string, key string, uploadID string) ([]S3Part, error) {
+	var parts []S3Part
+	if err := getBackendJSON(token, s3MultipartURL("/parts", bucket, key, uploadID), &parts); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+func UploadS3Part(token string, bucket string, key string, uploadID string, partNumber int, body io.Reader, size int64) (string, error) {
+	endpoint := s3MultipartURL("/part", bucket, key, uploadID) + "&partNumber=" + strconv.Itoa(partNumber)
+	req, err := http.NewRequest("PUT", endpoint, body)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.ContentLength = size
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("ETag"), nil
+}
+
+func CompleteS3MultipartUpload(token string, bucket string, key string, uploadID string, parts []S3Part) (string, error) {
+	jsonData, err := json.Marshal(map[string][]S3Part{"parts": parts})
+	if err != nil {
+		return "", fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", s3MultipartURL("/complete", bucket, key, uploadID), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("ETag"), nil
+}
+
+// ListAllS3Objects pages through every object under prefix, without rolling up folders.
+func ListAllS3Objects(token string, bucket string, prefix string) ([]S3Object, error) {
+	var objects []S3Object
+	continuationToken := ""
+	for {
+		listing, err := ListS3Objects(token, bucket, prefix, "", continuationToken)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, listing.Objects...)
+		if listing.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = listing.NextContinuationToken
+	}
+}
+
+// PresignS3URL asks the backend for a presigned GET or PUT URL valid for expires.
+func PresignS3URL(token string, bucket string, key string, method string, expires time.Duration) (string, error) {
+	q := url.Values{}
+	q.Set("bucket", bucket)
+	q.Set("key", key)
+	q.Set("method", method)
+	q.Set("expires", strconv.Itoa(int(expires.Seconds())))
+
+	var parsed struct {
+		URL string `json:"url"`
+	}
+	if err := getBackendJSON(token, s3URL("/presign", q), &parsed); err != nil {
+		return "", err
+	}
+	return parsed.URL, nil
+}
+
+// CopyS3Object copies an object server-side and returns the new object's ETag.
+func CopyS3Object(token string, srcBucket string, srcKey string, dstBucket string, dstKey string) (string, error) {
+	jsonData, err := json.Marshal(map[string]string{
+		"srcBucket": srcBucket,
+		"srcKey":    srcKey,
+		"dstBucket": dstBucket,
+		"dstKey":    dstKey,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", s3URL("/copy", url.Values{}), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("ETag"), nil
+}
+
+func DeleteS3Object(token string, bucket string, key string) error {
+	req, err := http.NewRequest("DELETE", s3ObjectURL(bucket, key), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,523 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	s3PreviewBytes    = 64 * 1024
+	s3MaxImageBytes   = 10 * 1024 * 1024
+	s3PreviewMaxLines = 30
+)
+
+var previewPaneStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("240")).
+	Padding(0, 1).
+	Width(56)
+
+// s3Entry is one row of the browser: a bucket, a folder-like prefix or an object.
+type s3Entry struct {
+	name   string
+	bucket string
+	prefix string
+	object *S3Object
+}
+
+func (e s3Entry) isFolder() bool {
+	return e.object == nil
+}
+
+// id identifies an entry within a bucket for multi-select.
+func (e s3Entry) id() string {
+	if e.object != nil {
+		return e.object.Key
+	}
+	return e.prefix
+}
+
+type S3Browser struct {
+	bucket       string
+	prefix       string
+	entries      []s3Entry
+	marked       map[string]bool
+	nextToken    string
+	list         table.Model
+	preview      string
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type S3ListingMsg struct {
+	bucket    string
+	prefix    string
+	entries   []s3Entry
+	nextToken string
+	more      bool
+	err       error
+}
+
+// S3TransferRequestMsg asks for the upload or download screen for the browser's selection.
+type S3TransferRequestMsg struct {
+	upload bool
+	bucket string
+	prefix string
+	entry  s3Entry
+}
+
+// S3ShareRequestMsg asks for the presigned URL screen for an object.
+type S3ShareRequestMsg struct {
+	bucket string
+	key    string
+}
+
+// S3BulkRequestMsg asks for a bulk copy, move or delete of entries, or with sync
+// set, a sync of the current prefix.
+type S3BulkRequestMsg struct {
+	op      transferOp
+	sync    bool
+	bucket  string
+	prefix  string
+	entries []s3Entry
+}
+
+// ShowS3BrowserMsg returns to the browser from one of its sub-screens.
+type ShowS3BrowserMsg struct{}
+
+type S3PreviewMsg struct {
+	key     string
+	preview string
+	err     error
+}
+
+func InitialS3Browser(token string, refreshToken string, user User) S3Browser {
+	return S3Browser{
+		list: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Name", Width: 40},
+				{Title: "Size", Width: 10},
+				{Title: "Last Modified", Width: 16},
+				{Title: "Class", Width: 12},
+			}),
+			table.WithHeight(20),
+			table.WithFocused(true),
+		),
+		marked:       make(map[string]bool),
+		loading:      true,
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+func (m S3Browser) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("S3"), listS3(m.token, "", "", "", false))
+}
+
+// listS3 lists buckets when bucket is empty, otherwise one page of bucket/prefix.
+func listS3(token string, bucket string, prefix string, continuationToken string, more bool) tea.Cmd {
+	return func() tea.Msg {
+		if bucket == "" {
+			buckets, err := ListS3Buckets(token)
+			if err != nil {
+				return S3ListingMsg{err: err}
+			}
+			entries := make([]s3Entry, len(buckets))
+			for i, b := range buckets {
+				entries[i] = s3Entry{name: b.Name, bucket: b.Name}
+			}
+			return S3ListingMsg{entries: entries}
+		}
+
+		listing, err := ListS3Objects(token, bucket, prefix, "/", continuationToken)
+		if err != nil {
+			return S3ListingMsg{bucket: bucket, prefix: prefix, err: err}
+		}
+
+		var entries []s3Entry
+		for _, p := range listing.Prefixes {
+			entries = append(entries, s3Entry{name: strings.TrimPrefix(p, prefix), bucket: bucket, prefix: p})
+		}
+		for i := range listing.Objects {
+			obj := listing.Objects[i]
+			if obj.Key == prefix {
+				// The placeholder object some tools create for empty folders.
+				continue
+			}
+			entries = append(entries, s3Entry{name: strings.TrimPrefix(obj.Key, prefix), bucket: bucket, object: &obj})
+		}
+
+		return S3ListingMsg{
+			bucket:    bucket,
+			prefix:    prefix,
+			entries:   entries,
+			nextToken: listing.NextContinuationToken,
+			more:      more,
+		}
+	}
+}
+
+func previewS3Object(token string, bucket string, obj S3Object) tea.Cmd {
+	return func() tea.Msg {
+		info, err := HeadS3Object(token, bucket, obj.Key)
+		if err != nil {
+			return S3PreviewMsg{key: obj.Key, err: err}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s\n\n", obj.Key)
+		fmt.Fprintf(&b, "Size:          %s\n", formatBytes(obj.Size))
+		fmt.Fprintf(&b, "Last modified: %s\n", obj.LastModified.Local().Format("2006-01-02 15:04"))
+		fmt.Fprintf(&b, "Storage class: %s\n", obj.StorageClass)
+		fmt.Fprintf(&b, "ETag:          %s\n", strings.Trim(obj.ETag, `"`))
+		fmt.Fprintf(&b, "Content type:  %s\n", info.ContentType)
+		for k, v := range info.Metadata {
+			fmt.Fprintf(&b, "meta %s: %s\n", k, v)
+		}
+		b.WriteString("\n")
+
+		limit := int64(s3PreviewBytes)
+		if strings.HasPrefix(info.ContentType, "image/") && obj.Size <= s3MaxImageBytes {
+			limit = 0
+		}
+		data, err := GetS3ObjectRange(token, bucket, obj.Key, limit)
+		if err != nil {
+			return S3PreviewMsg{key: obj.Key, preview: b.String(), err: err}
+		}
+
+		b.WriteString(renderS3Content(info.ContentType, obj.Key, data))
+		return S3PreviewMsg{key: obj.Key, preview: b.String()}
+	}
+}
+
+// renderS3Content previews the start of an object: images are drawn, JSON is
+// indented and other text is shown as-is.
+func renderS3Content(contentType string, key string, data []byte) string {
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		img, err := DecodeImage(data)
+		if err != nil {
+			return fmt.Sprintf("(%v)", err)
+		}
+		return RenderImage(img, 50, 20)
+
+	case strings.Contains(contentType, "json") || path.Ext(key) == ".json":
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err == nil {
+			return firstLines(out.String(), s3PreviewMaxLines)
+		}
+		return firstLines(string(data), s3PreviewMaxLines)
+
+	case strings.HasPrefix(contentType, "text/") || utf8.Valid(data):
+		return firstLines(string(data), s3PreviewMaxLines)
+	}
+
+	return "(binary content)"
+}
+
+func firstLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "\n") + "\n…"
+}
+
+func (m S3Browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case S3ListingMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if msg.bucket != m.bucket || msg.prefix != m.prefix {
+			m.marked = make(map[string]bool)
+		}
+		m.bucket = msg.bucket
+		m.prefix = msg.prefix
+		m.nextToken = msg.nextToken
+		if msg.more {
+			m.entries = append(m.entries, msg.entries...)
+		} else {
+			m.entries = msg.entries
+			ReleaseImage(m.preview)
+			m.preview = ""
+		}
+		m.refreshRows()
+		m.status = fmt.Sprintf("%d entries", len(m.entries))
+		if m.nextToken != "" {
+			m.status += " (n: load more)"
+		}
+		return m, nil
+
+	case S3PreviewMsg:
+		m.loading = false
+		ReleaseImage(m.preview)
+		m.preview = msg.preview
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc":
+			ReleaseImage(m.preview)
+			return m, func() tea.Msg {
+				return MainMenuMsg{selected: 2, token: m.token, refreshToken: m.refreshToken, user: m.user}
+			}
+		case "enter", "right", "l":
+			entry, ok := m.selectedEntry()
+			if !ok {
+				return m, nil
+			}
+			m.loading = true
+			if !entry.isFolder() {
+				m.status = "Loading preview..."
+				return m, previewS3Object(m.token, entry.bucket, *entry.object)
+			}
+			m.status = "Loading..."
+			return m, listS3(m.token, entry.bucket, entry.prefix, "", false)
+		case "backspace", "left", "h":
+			if m.bucket == "" {
+				return m, nil
+			}
+			m.loading = true
+			if m.prefix == "" {
+				return m, listS3(m.token, "", "", "", false)
+			}
+			return m, listS3(m.token, m.bucket, parentPrefix(m.prefix), "", false)
+		case "n":
+			if m.nextToken == "" {
+				return m, nil
+			}
+			m.loading = true
+			return m, listS3(m.token, m.bucket, m.prefix, m.nextToken, true)
+		case "r":
+			cmd := m.refresh()
+			return m, cmd
+		case "u":
+			if m.bucket == "" {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return S3TransferRequestMsg{upload: true, bucket: m.bucket, prefix: m.prefix}
+			}
+		case "d":
+			entry, ok := m.selectedEntry()
+			if !ok || m.bucket == "" {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return S3TransferRequestMsg{bucket: m.bucket, prefix: m.prefix, entry: entry}
+			}
+		case " ":
+			entry, ok := m.selectedEntry()
+			if !ok || m.bucket == "" {
+				return m, nil
+			}
+			if m.marked[entry.id()] {
+				delete(m.marked, entry.id())
+			} else {
+				m.marked[entry.id()] = true
+			}
+			m.refreshRows()
+			m.list.MoveDown(1)
+			return m, nil
+		case "c", "m", "x":
+			entries := m.selection()
+			if len(entries) == 0 {
+				return m, nil
+			}
+			op := map[string]transferOp{"c": opCopy, "m": opMove, "x": opDelete}[msg.String()]
+			return m, func() tea.Msg {
+				return S3BulkRequestMsg{op: op, bucket: m.bucket, prefix: m.prefix, entries: entries}
+			}
+		case "y":
+			if m.bucket == "" {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return S3BulkRequestMsg{sync: true, bucket: m.bucket, prefix: m.prefix}
+			}
+		case "s":
+			entry, ok := m.selectedEntry()
+			if !ok || entry.isFolder() {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return S3ShareRequestMsg{bucket: entry.bucket, key: entry.object.Key}
+			}
+		case "a":
+			entry, ok := m.selectedEntry()
+			if !ok || entry.isFolder() {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return RekognitionRequestMsg{bucket: entry.bucket, key: entry.object.Key}
+			}
+		case "t":
+			entry, ok := m.selectedEntry()
+			if !ok || entry.isFolder() {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return ClickUpAttachmentsRequestMsg{bucket: entry.bucket, key: entry.object.Key}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// parentPrefix turns "a/b/c/" into "a/b/" and "a/" into "".
+func parentPrefix(prefix string) string {
+	trimmed := strings.TrimSuffix(prefix, "/")
+	i := strings.LastIndex(trimmed, "/")
+	if i < 0 {
+		return ""
+	}
+	return trimmed[:i+1]
+}
+
+// selection is the marked entries, or the one under the cursor if none are marked.
+func (m S3Browser) selection() []s3Entry {
+	if m.bucket == "" {
+		return nil
+	}
+	var entries []s3Entry
+	for _, e := range m.entries {
+		if m.marked[e.id()] {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) == 0 {
+		if e, ok := m.selectedEntry(); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (m S3Browser) selectedEntry() (s3Entry, bool) {
+	i := m.list.Cursor()
+	if i < 0 || i >= len(m.entries) {
+		return s3Entry{}, false
+	}
+	return m.entries[i], true
+}
+
+// taskDraft links a quick task to the object or folder under the cursor.
+func (m S3Browser) taskDraft() ClickUpTaskDraft {
+	e, ok := m.selectedEntry()
+	if !ok {
+		return ClickUpTaskDraft{}
+	}
+	if e.isFolder() {
+		link := "https://s3.console.aws.amazon.com/s3/buckets/" + e.bucket + "?prefix=" + url.QueryEscape(e.prefix)
+		return ClickUpTaskDraft{
+			Title:       "Follow up on s3://" + e.bucket + "/" + e.prefix,
+			Description: fmt.Sprintf("[s3://%s/%s](%s)", e.bucket, e.prefix, link),
+		}
+	}
+	link := "https://s3.console.aws.amazon.com/s3/object/" + e.bucket + "?prefix=" + url.QueryEscape(e.object.Key)
+	return ClickUpTaskDraft{
+		Title:       "Follow up on " + path.Base(e.object.Key),
+		Description: fmt.Sprintf("[s3://%s/%s](%s)\n\n%s, last modified %s", e.bucket, e.object.Key, link, formatBytes(e.object.Size), e.object.LastModified.Format("2006-01-02 15:04")),
+	}
+}
+
+func (m *S3Browser) refreshRows() {
+	rows := make([]table.Row, len(m.entries))
+	for i, e := range m.entries {
+		name := e.name
+		if m.marked[e.id()] {
+			name = "✓ " + name
+		}
+		if e.isFolder() {
+			if m.bucket == "" {
+				name += "/"
+			}
+			rows[i] = table.Row{name, "", "", ""}
+			continue
+		}
+		rows[i] = table.Row{
+			name,
+			formatBytes(e.object.Size),
+			e.object.LastModified.Local().Format("2006-01-02 15:04"),
+			e.object.StorageClass,
+		}
+	}
+	m.list.SetRows(rows)
+	// An empty table leaves the cursor at -1.
+	if c := m.list.Cursor(); c < 0 || c >= len(rows) {
+		m.list.SetCursor(0)
+	}
+}
+
+func (m S3Browser) location() string {
+	if m.bucket == "" {
+		return "s3://"
+	}
+	return fmt.Sprintf("s3://%s/%s", m.bucket, m.prefix)
+}
+
+func (m S3Browser) View() string {
+	var b strings.Builder
+
+	b.WriteString("\nS3 " + m.location() + "\n\n")
+
+	left := m.list.View()
+	if m.preview != "" {
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, left, " ", previewPaneStyle.Render(m.preview)))
+	} else {
+		b.WriteString(left)
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("enter: open/preview • backspace: up • u: upload • d: download • s: share • r: refresh • esc: back"))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("space: select • c: copy • m: move • x: delete • y: sync with local directory • a: analyse image • t: attach to ClickUp task"))
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func (m *S3Browser) refresh() tea.Cmd {
+	m.loading = true
+	return listS3(m.token, m.bucket, m.prefix, "", false)
+}
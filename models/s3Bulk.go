@@ -0,0 +1,335 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type bulkStage int
+
+const (
+	bulkForm bulkStage = iota
+	bulkPreview
+)
+
+// deleteConfirmation must be typed before a bulk delete runs.
+const deleteConfirmation = "delete"
+
+type S3Bulk struct {
+	op           transferOp
+	sync         bool
+	stage        bulkStage
+	entries      []s3Entry
+	bucket       string
+	prefix       string
+	input        textinput.Model
+	syncUpload   bool
+	syncDelete   bool
+	confirm      textinput.Model
+	jobs         []TransferJob
+	partSize     int64
+	preview      table.Model
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type BulkPlanMsg struct {
+	jobs    []TransferJob
+	actions []SyncAction
+	err     error
+}
+
+// S3RunJobsMsg hands a confirmed plan to the transfer screen.
+type S3RunJobsMsg struct {
+	title string
+	jobs  []TransferJob
+}
+
+// InitialS3Bulk prepares a copy, move or delete of entries.
+func InitialS3Bulk(token string, refreshToken string, user User, op transferOp, bucket string, prefix string, entries []s3Entry) S3Bulk {
+	m := newS3Bulk(token, refreshToken, user, bucket, prefix)
+	m.op = op
+	m.entries = entries
+	if op == opDelete {
+		// Deletes have no destination, so go straight to listing what would go.
+		m.stage = bulkPreview
+		m.loading = true
+		m.status = "Listing objects..."
+	}
+
+	m.input = newFormInput("Destination s3://bucket/prefix/", 1024, 60)
+	m.input.SetValue(fmt.Sprintf("s3://%s/%s", bucket, prefix))
+	m.input.Focus()
+	m.input.PromptStyle = focusedStyle
+	m.input.TextStyle = focusedStyle
+
+	return m
+}
+
+// InitialS3Sync prepares a sync between a local directory and bucket/prefix.
+func InitialS3Sync(token string, refreshToken string, user User, config Config, bucket string, prefix string) S3Bulk {
+	m := newS3Bulk(token, refreshToken, user, bucket, prefix)
+	m.sync = true
+	m.partSize = s3PartSize(config)
+	m.syncUpload = true
+
+	m.input = newFormInput("Local directory", 1024, 60)
+	m.input.SetValue(".")
+	m.input.Focus()
+	m.input.PromptStyle = focusedStyle
+	m.input.TextStyle = focusedStyle
+
+	return m
+}
+
+func newS3Bulk(token string, refreshToken string, user User, bucket string, prefix string) S3Bulk {
+	confirm := newFormInput(fmt.Sprintf("Type %q to confirm", deleteConfirmation), 16, 30)
+	confirm.PromptStyle = focusedStyle
+	confirm.TextStyle = focusedStyle
+
+	return S3Bulk{
+		bucket:  bucket,
+		prefix:  prefix,
+		confirm: confirm,
+		preview: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Action", Width: 9},
+				{Title: "Path", Width: 50},
+				{Title: "Detail", Width: 40},
+			}),
+			table.WithHeight(15),
+			table.WithFocused(true),
+		),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+func (m S3Bulk) Init() tea.Cmd {
+	if m.op == opDelete && !m.sync {
+		return tea.Batch(tea.SetWindowTitle("S3 Delete"), m.plan())
+	}
+	return tea.Batch(tea.SetWindowTitle("S3 Bulk"), textinput.Blink)
+}
+
+// parseS3Location splits s3://bucket/prefix into its parts.
+func parseS3Location(s string) (string, string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("expected s3://bucket/prefix/, got %q", s)
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("missing bucket in %q", s)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return bucket, prefix, nil
+}
+
+func (m S3Bulk) plan() tea.Cmd {
+	token := m.token
+	if m.sync {
+		dir, bucket, prefix := strings.TrimSpace(m.input.Value()), m.bucket, m.prefix
+		upload, deleteExtra, partSize := m.syncUpload, m.syncDelete, m.partSize
+		return func() tea.Msg {
+			actions, err := PlanSync(token, dir, bucket, prefix, upload, deleteExtra, partSize)
+			return BulkPlanMsg{actions: actions, err: err}
+		}
+	}
+
+	op, entries := m.op, m.entries
+	destBucket, destPrefix := "", ""
+	if op != opDelete {
+		var err error
+		destBucket, destPrefix, err = parseS3Location(m.input.Value())
+		if err != nil {
+			return func() tea.Msg { return BulkPlanMsg{err: err} }
+		}
+	}
+	return func() tea.Msg {
+		jobs, err := BucketJobs(token, op, entries, destBucket, destPrefix)
+		return BulkPlanMsg{jobs: jobs, err: err}
+	}
+}
+
+func (m S3Bulk) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case BulkPlanMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.setPlan(msg)
+		m.stage = bulkPreview
+		if len(m.jobs) == 0 {
+			m.status = "Nothing to do."
+			return m, nil
+		}
+		if m.needsTypedConfirmation() {
+			m.status = fmt.Sprintf("Dry run: %d changes. Type %q and press enter to run them.", len(m.jobs), deleteConfirmation)
+			return m, m.confirm.Focus()
+		}
+		m.status = fmt.Sprintf("Dry run: %d changes. Press y to run them.", len(m.jobs))
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+		if msg.String() == "esc" {
+			return m, func() tea.Msg { return ShowS3BrowserMsg{} }
+		}
+
+		switch m.stage {
+		case bulkForm:
+			switch msg.String() {
+			case "ctrl+t":
+				if m.sync {
+					m.syncUpload = !m.syncUpload
+				}
+				return m, nil
+			case "ctrl+d":
+				if m.sync {
+					m.syncDelete = !m.syncDelete
+				}
+				return m, nil
+			case "enter":
+				m.loading = true
+				m.status = "Planning..."
+				return m, m.plan()
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+
+		case bulkPreview:
+			if len(m.jobs) == 0 {
+				return m, nil
+			}
+			if m.needsTypedConfirmation() {
+				if msg.String() == "enter" {
+					if strings.TrimSpace(m.confirm.Value()) != deleteConfirmation {
+						m.status = fmt.Sprintf("Type %q exactly to confirm.", deleteConfirmation)
+						return m, nil
+					}
+					return m, m.run()
+				}
+				var cmd tea.Cmd
+				m.confirm, cmd = m.confirm.Update(msg)
+				return m, cmd
+			}
+			if msg.String() == "y" {
+				return m, m.run()
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.preview, cmd = m.preview.Update(msg)
+	return m, cmd
+}
+
+// needsTypedConfirmation is true when the plan deletes anything, including the
+// sources of a move.
+func (m S3Bulk) needsTypedConfirmation() bool {
+	for _, job := range m.jobs {
+		if job.Op == opDelete || job.Op == opMove {
+			return true
+		}
+	}
+	return false
+}
+
+func (m S3Bulk) run() tea.Cmd {
+	title := m.title()
+	jobs := m.jobs
+	return func() tea.Msg { return S3RunJobsMsg{title: title, jobs: jobs} }
+}
+
+func (m *S3Bulk) setPlan(plan BulkPlanMsg) {
+	var rows []table.Row
+	m.jobs = nil
+
+	if m.sync {
+		for _, a := range plan.actions {
+			m.jobs = append(m.jobs, a.Job)
+			rows = append(rows, table.Row{a.Job.Op.String(), a.Path, a.Reason})
+		}
+	} else {
+		m.jobs = plan.jobs
+		for _, job := range plan.jobs {
+			detail := formatBytes(job.Size)
+			if job.Op != opDelete {
+				detail = fmt.Sprintf("→ s3://%s/%s", job.DestBucket, job.DestKey)
+			}
+			rows = append(rows, table.Row{job.Op.String(), job.Key, detail})
+		}
+	}
+
+	m.preview.SetRows(rows)
+	m.preview.GotoTop()
+}
+
+func (m S3Bulk) title() string {
+	if m.sync {
+		if m.syncUpload {
+			return fmt.Sprintf("Sync %s → s3://%s/%s", m.input.Value(), m.bucket, m.prefix)
+		}
+		return fmt.Sprintf("Sync s3://%s/%s → %s", m.bucket, m.prefix, m.input.Value())
+	}
+	return fmt.Sprintf("Bulk %s of %d selected in s3://%s/%s", m.op, len(m.entries), m.bucket, m.prefix)
+}
+
+func (m S3Bulk) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n" + m.title() + "\n\n")
+
+	switch m.stage {
+	case bulkForm:
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+		if m.sync {
+			direction := "local → S3"
+			if !m.syncUpload {
+				direction = "S3 → local"
+			}
+			fmt.Fprintf(&b, "Direction: %s\nDelete files missing from the source: %t\n\n", direction, m.syncDelete)
+			b.WriteString(helpStyle.Render("enter: dry run • ctrl+t: direction • ctrl+d: toggle delete • esc: back"))
+		} else {
+			b.WriteString(helpStyle.Render("enter: dry run • esc: back"))
+		}
+
+	case bulkPreview:
+		b.WriteString(m.preview.View())
+		b.WriteString("\n\n")
+		if len(m.jobs) > 0 && m.needsTypedConfirmation() {
+			b.WriteString(m.confirm.View())
+			b.WriteString("\n\n")
+			b.WriteString(helpStyle.Render("enter: run • esc: cancel"))
+		} else {
+			b.WriteString(helpStyle.Render("y: run • esc: cancel"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// S3 SigV4 presigned URLs can't outlive seven days.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+type S3Share struct {
+	bucket       string
+	key          string
+	method       string
+	expiry       textinput.Model
+	url          string
+	qr           string
+	channel      int
+	loading      bool
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type PresignedURLMsg struct {
+	url string
+	qr  string
+	err error
+}
+
+type ChannelPostedMsg struct {
+	channel string
+	err     error
+}
+
+func InitialS3Share(token string, refreshToken string, user User, bucket string, key string) S3Share {
+	expiry := newFormInput("Expiry, e.g. 15m, 1h, 7d", 8, 20)
+	expiry.SetValue("1h")
+	expiry.Focus()
+	expiry.PromptStyle = focusedStyle
+	expiry.TextStyle = focusedStyle
+
+	return S3Share{
+		bucket:       bucket,
+		key:          key,
+		method:       "GET",
+		expiry:       expiry,
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+func (m S3Share) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Share S3 Object"), textinput.Blink)
+}
+
+// parseExpiry accepts Go durations plus a "d" suffix for whole days.
+func parseExpiry(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid expiry %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid expiry %q", s)
+		}
+	}
+
+	if d <= 0 || d > maxPresignExpiry {
+		return 0, fmt.Errorf("expiry must be between 1s and 7d")
+	}
+	return d, nil
+}
+
+func presign(token string, bucket string, key string, method string, expires time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		url, err := PresignS3URL(token, bucket, key, method, expires)
+		if err != nil {
+			return PresignedURLMsg{err: err}
+		}
+
+		qr, err := qrcode.New(url, qrcode.Low)
+		if err != nil {
+			return PresignedURLMsg{url: url, err: fmt.Errorf("rendering QR code: %w", err)}
+		}
+		return PresignedURLMsg{url: url, qr: qr.ToSmallString(false)}
+	}
+}
+
+func (m S3Share) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case PresignedURLMsg:
+		m.loading = false
+		m.url = msg.url
+		m.qr = msg.qr
+		m.status = ""
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		}
+		return m, nil
+
+	case ChannelPostedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error posting to channel: %v", msg.err)
+			return m, nil
+		}
+		m.status = "Posted to channel " + msg.channel + "."
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return ShowS3BrowserMsg{} }
+		case "ctrl+t":
+			if m.method == "GET" {
+				m.method = "PUT"
+			} else {
+				m.method = "GET"
+			}
+			m.url, m.qr = "", ""
+			return m, nil
+		case "enter":
+			expires, err := parseExpiry(m.expiry.Value())
+			if err != nil {
+				m.status = err.Error()
+				return m, nil
+			}
+			m.loading = true
+			m.status = "Generating URL..."
+			return m, presign(m.token, m.bucket, m.key, m.method, expires)
+		}
+
+		if m.url != "" {
+			switch msg.String() {
+			case "ctrl+y":
+				if err := clipboard.WriteAll(m.url); err != nil {
+					m.status = fmt.Sprintf("Error copying: %v", err)
+				} else {
+					m.status = "Copied to clipboard."
+				}
+				return m, nil
+			case "ctrl+left":
+				if m.channel > 0 {
+					m.channel--
+				}
+				return m, nil
+			case "ctrl+right":
+				if m.channel < len(m.user.Channels)-1 {
+					m.channel++
+				}
+				return m, nil
+			case "ctrl+p":
+				if len(m.user.Channels) == 0 {
+					m.status = "You aren't in any channels."
+					return m, nil
+				}
+				channel := m.user.Channels[m.channel]
+				text := fmt.Sprintf("%s shared %s (%s, expires in %s): %s", m.user.Name, m.key, m.method, m.expiry.Value(), m.url)
+				m.loading = true
+				m.status = "Posting..."
+				return m, func() tea.Msg {
+					return ChannelPostedMsg{channel: channel, err: PostChannelMessage(m.token, channel, m.user.ID, text)}
+				}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.expiry, cmd = m.expiry.Update(msg)
+	return m, cmd
+}
+
+func (m S3Share) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\nShare s3://%s/%s\n\n", m.bucket, m.key)
+	fmt.Fprintf(&b, "Method: %s\n", focusedStyle.Render(m.method))
+	b.WriteString(m.expiry.View())
+	b.WriteString("\n\n")
+
+	if m.url != "" {
+		b.WriteString(m.qr)
+		b.WriteString("\n")
+		b.WriteString(m.url)
+		b.WriteString("\n\n")
+		if len(m.user.Channels) > 0 {
+			fmt.Fprintf(&b, "Channel: %s\n\n", m.user.Channels[m.channel])
+		}
+		b.WriteString(helpStyle.Render("ctrl+y: copy • ctrl+p: post to channel • ctrl+←/→: choose channel"))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render("enter: generate • ctrl+t: toggle GET/PUT • esc: back"))
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type SyncAction struct {
+	Job    TransferJob
+	Path   string
+	Reason string
+}
+
+type localFile struct {
+	path string
+	size int64
+}
+
+// PlanSync compares localDir with bucket/prefix by relative path, size and ETag and
+// returns what a sync in the given direction would do, without changing anything.
+// With deleteExtra, files only present at the destination are deleted. partSize
+// is the multipart part size uploads use, which multipart ETags are checked with.
+func PlanSync(token string, localDir string, bucket string, prefix string, upload bool, deleteExtra bool, partSize int64) ([]SyncAction, error) {
+	local := make(map[string]localFile)
+	err := filepath.WalkDir(localDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		local[filepath.ToSlash(rel)] = localFile{path: p, size: info.Size()}
+		return nil
+	})
+	if err != nil && !(os.IsNotExist(err) && !upload) {
+		return nil, err
+	}
+
+	objects, err := ListAllS3Objects(token, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]S3Object)
+	for _, obj := range objects {
+		if !strings.HasSuffix(obj.Key, "/") {
+			remote[strings.TrimPrefix(obj.Key, prefix)] = obj
+		}
+	}
+
+	var actions []SyncAction
+	if upload {
+		for rel, f := range local {
+			obj, exists := remote[rel]
+			reason, changed := syncDifference(f, obj, exists, partSize)
+			if !changed {
+				continue
+			}
+			actions = append(actions, SyncAction{
+				Job:    TransferJob{Op: opUpload, LocalPath: f.path, Bucket: bucket, Key: prefix + rel, Size: f.size},
+				Path:   rel,
+				Reason: reason,
+			})
+		}
+		if deleteExtra {
+			for rel, obj := range remote {
+				if _, ok := local[rel]; !ok {
+					actions = append(actions, SyncAction{
+						Job:    TransferJob{Op: opDelete, Bucket: bucket, Key: obj.Key},
+						Path:   rel,
+						Reason: "not in local directory",
+					})
+				}
+			}
+		}
+	} else {
+		for rel, obj := range remote {
+			f, exists := local[rel]
+			reason, changed := syncDifference(f, obj, exists, partSize)
+			if !changed {
+				continue
+			}
+			actions = append(actions, SyncAction{
+				Job: TransferJob{
+					Op:        opDownload,
+					LocalPath: filepath.Join(localDir, filepath.FromSlash(rel)),
+					Bucket:    bucket,
+					Key:       obj.Key,
+					Size:      obj.Size,
+					ETag:      obj.ETag,
+				},
+				Path:   rel,
+				Reason: reason,
+			})
+		}
+		if deleteExtra {
+			for rel, f := range local {
+				if _, ok := remote[rel]; !ok {
+					actions = append(actions, SyncAction{
+						Job:    TransferJob{Op: opDelete, LocalPath: f.path},
+						Path:   rel,
+						Reason: "not in bucket",
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Path < actions[j].Path })
+	return actions, nil
+}
+
+// syncDifference reports whether a local file and an object differ, and why.
+// When sizes match the ETag decides; ETags from another uploader's part size
+// can't be compared, so those are treated as unchanged.
+func syncDifference(f localFile, obj S3Object, exists bool, partSize int64) (string, bool) {
+	if !exists {
+		return "missing", true
+	}
+	if f.size != obj.Size {
+		return fmt.Sprintf("size differs (local %s, remote %s)", formatBytes(f.size), formatBytes(obj.Size)), true
+	}
+
+	etag := strings.Trim(obj.ETag, `"`)
+	if etag == "" {
+		return "", false
+	}
+	local, err := localETag(f.path, f.size, strings.Contains(etag, "-"), partSize)
+	if err != nil {
+		return fmt.Sprintf("unreadable: %v", err), true
+	}
+	if strings.Contains(etag, "-") && !strings.HasSuffix(local, etag[strings.LastIndex(etag, "-"):]) {
+		return "", false
+	}
+	if local != etag {
+		return "content differs (ETag)", true
+	}
+	return "", false
+}
+
+// localETag computes the ETag S3 would give the file: a plain MD5, or for
+// multipart uploads in parts of partSize the MD5 of part MD5s and the part count.
+func localETag(path string, size int64, multipart bool, partSize int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if !multipart {
+		h := md5.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	var partSums []byte
+	parts := 0
+	for offset := int64(0); offset < size; offset += partSize {
+		h := md5.New()
+		if _, err := io.Copy(h, io.NewSectionReader(f, offset, min(partSize, size-offset))); err != nil {
+			return "", err
+		}
+		partSums = append(partSums, h.Sum(nil)...)
+		parts++
+	}
+	whole := md5.Sum(partSums)
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(whole[:]), parts), nil
+}
@@ -0,0 +1,364 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxVisibleTransfers = 10
+
+type transferStage int
+
+const (
+	transferPickSource transferStage = iota
+	transferPickDest
+	transferRunning
+	transferDone
+)
+
+type S3Transfers struct {
+	stage        transferStage
+	title        string
+	upload       bool
+	bucket       string
+	prefix       string
+	source       s3Entry
+	picker       filepicker.Model
+	dest         textinput.Model
+	jobs         []TransferJob
+	done         []int64
+	finished     []bool
+	verified     []bool
+	errs         []error
+	run          *transferRun
+	fileBar      progress.Model
+	overallBar   progress.Model
+	workers      int
+	partSize     int64
+	status       string
+	token        string
+	refreshToken string
+	user         User
+}
+
+type TransferJobsMsg struct {
+	jobs []TransferJob
+	err  error
+}
+
+func InitialS3Upload(token string, refreshToken string, user User, config Config, bucket string, prefix string) S3Transfers {
+	m := newS3Transfers(token, refreshToken, user, config)
+	m.upload = true
+	m.bucket = bucket
+	m.prefix = prefix
+	m.stage = transferPickSource
+
+	m.picker = filepicker.New()
+	m.picker.CurrentDirectory, _ = os.Getwd()
+	m.picker.DirAllowed = true
+	m.picker.FileAllowed = true
+	m.picker.SetHeight(15)
+
+	return m
+}
+
+func InitialS3Download(token string, refreshToken string, user User, config Config, source s3Entry) S3Transfers {
+	m := newS3Transfers(token, refreshToken, user, config)
+	m.bucket = source.bucket
+	m.source = source
+	m.stage = transferPickDest
+
+	m.dest = newFormInput("Download to directory", 512, 60)
+	m.dest.SetValue(".")
+	m.dest.Focus()
+	m.dest.PromptStyle = focusedStyle
+	m.dest.TextStyle = focusedStyle
+
+	return m
+}
+
+// InitialS3Run runs jobs planned elsewhere, such as a bulk copy or a sync.
+func InitialS3Run(token string, refreshToken string, user User, config Config, title string, jobs []TransferJob) S3Transfers {
+	m := newS3Transfers(token, refreshToken, user, config)
+	m.title = title
+	m.jobs = jobs
+	m.stage = transferRunning
+	return m
+}
+
+// s3PartSize is the configured part size for multipart uploads in bytes.
+func s3PartSize(config Config) int64 {
+	partSizeMB := config.S3PartSizeMB
+	if partSizeMB < 5 {
+		// S3 rejects parts under 5 MiB other than the last one.
+		partSizeMB = defaultS3PartSizeMB
+	}
+	return int64(partSizeMB) * 1024 * 1024
+}
+
+func newS3Transfers(token string, refreshToken string, user User, config Config) S3Transfers {
+	workers := config.S3Workers
+	if workers <= 0 {
+		workers = defaultS3Workers
+	}
+
+	return S3Transfers{
+		fileBar:      progress.New(progress.WithDefaultGradient(), progress.WithWidth(30)),
+		overallBar:   progress.New(progress.WithDefaultGradient(), progress.WithWidth(60)),
+		workers:      workers,
+		partSize:     s3PartSize(config),
+		token:        token,
+		refreshToken: refreshToken,
+		user:         user,
+	}
+}
+
+func (m S3Transfers) Init() tea.Cmd {
+	if m.title != "" {
+		jobs := m.jobs
+		return tea.Batch(tea.SetWindowTitle(m.title), func() tea.Msg { return TransferJobsMsg{jobs: jobs} })
+	}
+	if m.upload {
+		return tea.Batch(tea.SetWindowTitle("S3 Upload"), m.picker.Init())
+	}
+	return tea.Batch(tea.SetWindowTitle("S3 Download"), textinput.Blink)
+}
+
+func (m S3Transfers) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TransferJobsMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if len(msg.jobs) == 0 {
+			m.status = "Nothing to transfer."
+			m.stage = transferDone
+			return m, nil
+		}
+		m.jobs = msg.jobs
+		m.done = make([]int64, len(m.jobs))
+		m.finished = make([]bool, len(m.jobs))
+		m.verified = make([]bool, len(m.jobs))
+		m.errs = make([]error, len(m.jobs))
+		m.stage = transferRunning
+		m.status = fmt.Sprintf("Running %d jobs with %d workers...", len(m.jobs), m.workers)
+		m.run = StartTransfers(m.token, m.jobs, m.workers, m.partSize)
+		return m, waitForTransfer(m.run)
+
+	case TransferProgressMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.done[msg.index] = msg.done
+		return m, waitForTransfer(m.run)
+
+	case TransferFinishedMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.finished[msg.index] = true
+		m.verified[msg.index] = msg.verified
+		m.errs[msg.index] = msg.err
+		if msg.err == nil {
+			m.done[msg.index] = m.jobs[msg.index].Size
+		}
+		return m, waitForTransfer(m.run)
+
+	case TransfersDoneMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.stage = transferDone
+		failed := 0
+		for _, err := range m.errs {
+			if err != nil {
+				failed++
+			}
+		}
+		m.status = fmt.Sprintf("Done: %d succeeded, %d failed.", len(m.jobs)-failed, failed)
+		if failed > 0 {
+			m.status += " Run the same transfer again to resume."
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			if m.run != nil {
+				m.run.cancel()
+			}
+			return m, tea.Quit
+		case "esc":
+			if m.run != nil {
+				m.run.cancel()
+			}
+			return m, func() tea.Msg { return ShowS3BrowserMsg{} }
+		}
+
+		if m.stage == transferPickDest && msg.String() == "enter" {
+			dest := strings.TrimSpace(m.dest.Value())
+			if dest == "" {
+				return m, nil
+			}
+			m.status = "Listing objects..."
+			m.stage = transferRunning
+			source := m.source
+			return m, func() tea.Msg {
+				key := source.prefix
+				if source.object != nil {
+					key = source.object.Key
+				}
+				jobs, err := DownloadJobs(m.token, source.bucket, key, source.isFolder(), dest)
+				return TransferJobsMsg{jobs: jobs, err: err}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.stage {
+	case transferPickSource:
+		m.picker, cmd = m.picker.Update(msg)
+		if ok, path := m.picker.DidSelectFile(msg); ok {
+			m.stage = transferRunning
+			m.status = "Scanning " + path + "..."
+			bucket, prefix := m.bucket, m.prefix
+			return m, func() tea.Msg {
+				jobs, err := UploadJobs(path, bucket, prefix)
+				return TransferJobsMsg{jobs: jobs, err: err}
+			}
+		}
+	case transferPickDest:
+		m.dest, cmd = m.dest.Update(msg)
+	}
+
+	return m, cmd
+}
+
+func (m S3Transfers) View() string {
+	var b strings.Builder
+
+	switch {
+	case m.title != "":
+		fmt.Fprintf(&b, "\n%s\n\n", m.title)
+	case m.upload:
+		fmt.Fprintf(&b, "\nUpload to s3://%s/%s\n\n", m.bucket, m.prefix)
+	default:
+		fmt.Fprintf(&b, "\nDownload from %s\n\n", m.sourceName())
+	}
+
+	switch m.stage {
+	case transferPickSource:
+		b.WriteString(m.picker.View())
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("enter: upload file or directory • esc: back"))
+	case transferPickDest:
+		b.WriteString(m.dest.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: start download • esc: back"))
+	default:
+		b.WriteString(m.transfersView())
+		b.WriteString("\n")
+		if m.stage == transferDone {
+			b.WriteString(helpStyle.Render("esc: back to browser"))
+		} else {
+			b.WriteString(helpStyle.Render("esc: cancel"))
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func (m S3Transfers) sourceName() string {
+	if m.source.object != nil {
+		return fmt.Sprintf("s3://%s/%s", m.source.bucket, m.source.object.Key)
+	}
+	return fmt.Sprintf("s3://%s/%s", m.source.bucket, m.source.prefix)
+}
+
+// transfersView shows the overall bar and one bar per file, keeping unfinished
+// and failed files in view ahead of the ones already done.
+func (m S3Transfers) transfersView() string {
+	if len(m.jobs) == 0 || len(m.done) != len(m.jobs) {
+		return ""
+	}
+
+	var b strings.Builder
+	var total, done int64
+	finished := 0
+	for i, job := range m.jobs {
+		total += job.Size
+		done += m.done[i]
+		if m.finished[i] {
+			finished++
+		}
+	}
+
+	percent := 1.0
+	if total > 0 {
+		percent = float64(done) / float64(total)
+	}
+	fmt.Fprintf(&b, "%s  %d/%d files, %s of %s\n\n", m.overallBar.ViewAs(percent), finished, len(m.jobs), formatBytes(done), formatBytes(total))
+
+	var order []int
+	for i := range m.jobs {
+		if !m.finished[i] || m.errs[i] != nil {
+			order = append(order, i)
+		}
+	}
+	for i := range m.jobs {
+		if m.finished[i] && m.errs[i] == nil {
+			order = append(order, i)
+		}
+	}
+
+	for _, i := range order[:min(len(order), maxVisibleTransfers)] {
+		job := m.jobs[i]
+		percent := 0.0
+		if job.Size > 0 {
+			percent = float64(m.done[i]) / float64(job.Size)
+		} else if m.finished[i] {
+			percent = 1.0
+		}
+
+		state := ""
+		switch {
+		case m.errs[i] != nil:
+			state = fmt.Sprintf("✗ %v", m.errs[i])
+		case m.finished[i] && m.verified[i]:
+			state = "✓ checksum ok"
+		case m.finished[i]:
+			state = "✓ unverified"
+		}
+
+		name := job.Key
+		switch job.Op {
+		case opDownload:
+			name = filepath.Base(job.LocalPath)
+		case opCopy, opMove:
+			name = job.DestKey
+		case opDelete:
+			if job.Bucket == "" {
+				name = job.LocalPath
+			}
+		}
+		fmt.Fprintf(&b, "%-40.40s %s %s\n", name, m.fileBar.ViewAs(percent), state)
+	}
+	if len(order) > maxVisibleTransfers {
+		fmt.Fprintf(&b, "… and %d more\n", len(order)-maxVisibleTransfers)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newS3StandIn serves the recorded listings in testdata/s3 and points the S3
+// endpoints at them. A listing of bucket media under prefix photos/ is answered
+// from objects_media_photos.json, its next page from
+// objects_media_photos_page2.json, and a "/" delimited listing adds _folders.
+// It returns the number of requests served so far.
+func newS3StandIn(t *testing.T, token string) *int {
+	t.Helper()
+	requests := new(int)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if got := r.Header.Get("Authorization"); got != "Bearer "+token {
+			http.Error(w, `{"error":"invalid token"}`, http.StatusUnauthorized)
+			return
+		}
+		q := r.URL.Query()
+		name := strings.TrimPrefix(r.URL.Path, "/api/s3/")
+		for _, part := range []string{q.Get("bucket"), strings.Trim(q.Get("prefix"), "/")} {
+			if part != "" {
+				name += "_" + strings.ReplaceAll(part, "/", "-")
+			}
+		}
+		if q.Get("delimiter") == "/" {
+			name += "_folders"
+		}
+		if token := q.Get("continuationToken"); token != "" {
+			name += "_" + token
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", "s3", name+".json"))
+		if err != nil {
+			http.Error(w, `{"error":"NoSuchBucket"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("S3_API_URL", srv.URL+"/api/s3")
+	return requests
+}
+
+func objectKeys(objects []S3Object) string {
+	keys := make([]string, len(objects))
+	for i, o := range objects {
+		keys[i] = o.Key
+	}
+	return strings.Join(keys, ", ")
+}
+
+func TestListS3Buckets(t *testing.T) {
+	newS3StandIn(t, "tok")
+
+	buckets, err := ListS3Buckets("tok")
+	if err != nil {
+		t.Fatalf("ListS3Buckets: %v", err)
+	}
+	if len(buckets) != 2 || buckets[1].Name != "media" || buckets[1].CreationDate.Year() != 2024 {
+		t.Errorf("buckets = %+v", buckets)
+	}
+}
+
+func TestListS3Objects(t *testing.T) {
+	newS3StandIn(t, "tok")
+
+	tests := []struct {
+		name              string
+		prefix            string
+		delimiter         string
+		continuationToken string
+		prefixes          string
+		objects           string
+		next              string
+	}{
+		{
+			name:      "delimiter rolls keys up into folders",
+			delimiter: "/",
+			prefixes:  "photos/, videos/",
+			objects:   "readme.txt",
+		},
+		{
+			name:    "first page hands back a continuation token",
+			prefix:  "photos/",
+			objects: "photos/2024/beach.jpg, photos/2024/hike.jpg",
+			next:    "page2",
+		},
+		{
+			name:              "continuation token fetches the last page",
+			prefix:            "photos/",
+			continuationToken: "page2",
+			objects:           "photos/2025/snow.jpg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listing, err := ListS3Objects("tok", "media", tt.prefix, tt.delimiter, tt.continuationToken)
+			if err != nil {
+				t.Fatalf("ListS3Objects: %v", err)
+			}
+			if got := strings.Join(listing.Prefixes, ", "); got != tt.prefixes {
+				t.Errorf("prefixes = %q, want %q", got, tt.prefixes)
+			}
+			if got := objectKeys(listing.Objects); got != tt.objects {
+				t.Errorf("objects = %q, want %q", got, tt.objects)
+			}
+			if listing.NextContinuationToken != tt.next {
+				t.Errorf("next token = %q, want %q", listing.NextContinuationToken, tt.next)
+			}
+		})
+	}
+}
+
+func TestListAllS3Objects(t *testing.T) {
+	requests := newS3StandIn(t, "tok")
+
+	objects, err := ListAllS3Objects("tok", "media", "photos/")
+	if err != nil {
+		t.Fatalf("ListAllS3Objects: %v", err)
+	}
+	if got, want := objectKeys(objects), "photos/2024/beach.jpg, photos/2024/hike.jpg, photos/2025/snow.jpg"; got != want {
+		t.Errorf("objects = %q, want %q", got, want)
+	}
+	if *requests != 2 {
+		t.Errorf("made %d requests, want one per page", *requests)
+	}
+	if last := objects[2]; last.Size != 390541 || last.StorageClass != "GLACIER" {
+		t.Errorf("last object = %+v", last)
+	}
+}
+
+func TestListS3ObjectsError(t *testing.T) {
+	newS3StandIn(t, "tok")
+
+	tests := []struct {
+		name   string
+		token  string
+		bucket string
+		status int
+	}{
+		{"revoked token", "expired", "media", http.StatusUnauthorized},
+		{"missing bucket", "tok", "nope", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ListAllS3Objects(tt.token, tt.bucket, "")
+			var se *statusError
+			if !errors.As(err, &se) || se.StatusCode != tt.status {
+				t.Fatalf("err = %v, want a %d status error", err, tt.status)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,58 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// crispyDoodleAPI is the crispy-doodle server the rest of the app talks to.
+const crispyDoodleAPI = "http://localhost:8080/api"
+
+// statusError is a non-2xx reply, kept typed so callers can tell throttling apart.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// doBackendRequest sends req to the crispy-doodle server with the session token
+// and turns any non-2xx reply into an error. The caller closes the body of a
+// successful response.
+func doBackendRequest(token string, req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return resp, nil
+}
+
+func getBackendJSON(token string, endpoint string, out any) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := doBackendRequest(token, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
 type RequestMenu struct {
 	cursor       int
 	choices      []string
@@ -220,3 +273,38 @@ func GetUserByID(token string, id string) (*User, error) {
 
 	return &user, nil
 }
+
+func PostChannelMessage(token string, channelID string, senderID string, text string) error {
+	data := map[string]string{
+		"channel": channelID,
+		"sender":  senderID,
+		"text":    text,
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", "http://localhost:8080/api/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package models
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type UsageEntry struct {
+	Time             time.Time `json:"time"`
+	Model            string    `json:"model"`
+	Conversation     string    `json:"conversation"`
+	PromptTokens     int       `json:"promptTokens"`
+	CompletionTokens int       `json:"completionTokens"`
+	Images           int       `json:"images,omitempty"`
+	Cost             float64   `json:"cost"`
+}
+
+type modelPrice struct {
+	prompt     float64 // USD per million prompt tokens
+	completion float64 // USD per million completion tokens
+	image      float64 // USD per generated image
+}
+
+// modelPrices is matched by longest prefix, so dated model names such as
+// gpt-4o-mini-2024-07-18 resolve to their family's price.
+var modelPrices = map[string]modelPrice{
+	"gpt-4o":        {prompt: 2.50, completion: 10.00},
+	"gpt-4o-mini":   {prompt: 0.15, completion: 0.60},
+	"gpt-4.1":       {prompt: 2.00, completion: 8.00},
+	"gpt-4.1-mini":  {prompt: 0.40, completion: 1.60},
+	"gpt-4.1-nano":  {prompt: 0.10, completion: 0.40},
+	"gpt-4-turbo":   {prompt: 10.00, completion: 30.00},
+	"gpt-3.5-turbo": {prompt: 0.50, completion: 1.50},
+	"o3-mini":       {prompt: 1.10, completion: 4.40},
+	"dall-e-2":      {image: 0.02},
+	"dall-e-3":      {image: 0.04},
+	"gpt-image-1":   {image: 0.04},
+}
+
+func priceFor(model string) modelPrice {
+	best := ""
+	for name := range modelPrices {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	return modelPrices[best]
+}
+
+func EstimateCost(model string, promptTokens int, completionTokens int, images int) float64 {
+	p := priceFor(model)
+	return float64(promptTokens)*p.prompt/1e6 +
+		float64(completionTokens)*p.completion/1e6 +
+		float64(images)*p.image
+}
+
+// usageLedger serialises writes to the ledger file and caches the spend for
+// the current month so the status bar doesn't re-read the file every frame.
+var usageLedger struct {
+	sync.Mutex
+	month      string
+	monthTotal float64
+	loaded     bool
+}
+
+func usageLedgerPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
+
+// ErrUsageNotRecorded marks a request that succeeded but couldn't be written
+// to the usage ledger; the result comes back along with the error.
+var ErrUsageNotRecorded = errors.New("usage not recorded")
+
+// NewConversation names one session of a screen in the usage ledger, so its
+// requests can be totalled together.
+func NewConversation(screen string) string {
+	return screen + " " + time.Now().Format("2006-01-02 15:04:05")
+}
+
+// usageWarning is a status suffix for an error wrapping ErrUsageNotRecorded.
+func usageWarning(err error) string {
+	if err == nil {
+		return ""
+	}
+	return "\n" + statusWarningStyle.Render(err.Error())
+}
+
+// RecordUsage appends an entry to the local usage ledger.
+func RecordUsage(entry UsageEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Cost == 0 {
+		entry.Cost = EstimateCost(entry.Model, entry.PromptTokens, entry.CompletionTokens, entry.Images)
+	}
+
+	path, err := usageLedgerPath()
+	if err != nil {
+		return err
+	}
+
+	usageLedger.Lock()
+	defer usageLedger.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding usage: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing usage: %w", err)
+	}
+
+	if usageLedger.loaded && usageLedger.month == entry.Time.Format("2006-01") {
+		usageLedger.monthTotal += entry.Cost
+	}
+	return nil
+}
+
+func LoadUsage() ([]UsageEntry, error) {
+	path, err := usageLedgerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var entries []UsageEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry UsageEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// MonthToDateCost is the estimated spend recorded in the ledger this calendar month.
+func MonthToDateCost() float64 {
+	usageLedger.Lock()
+	defer usageLedger.Unlock()
+
+	month := time.Now().Format("2006-01")
+	if usageLedger.loaded && usageLedger.month == month {
+		return usageLedger.monthTotal
+	}
+
+	entries, err := LoadUsage()
+	if err != nil {
+		return 0
+	}
+
+	total := 0.0
+	for _, e := range entries {
+		if e.Time.Format("2006-01") == month {
+			total += e.Cost
+		}
+	}
+	usageLedger.month = month
+	usageLedger.monthTotal = total
+	usageLedger.loaded = true
+	return total
+}
+
+type UsageTotal struct {
+	Key              string
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	Images           int
+	Cost             float64
+}
+
+// SummarizeUsage groups entries by the key function, most expensive first.
+func SummarizeUsage(entries []UsageEntry, key func(UsageEntry) string) []UsageTotal {
+	byKey := make(map[string]*UsageTotal)
+	for _, e := range entries {
+		k := key(e)
+		t, ok := byKey[k]
+		if !ok {
+			t = &UsageTotal{Key: k}
+			byKey[k] = t
+		}
+		t.Requests++
+		t.PromptTokens += e.PromptTokens
+		t.CompletionTokens += e.CompletionTokens
+		t.Images += e.Images
+		t.Cost += e.Cost
+	}
+
+	totals := make([]UsageTotal, 0, len(byKey))
+	for _, t := range byKey {
+		totals = append(totals, *t)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Cost != totals[j].Cost {
+			return totals[i].Cost > totals[j].Cost
+		}
+		return totals[i].Key < totals[j].Key
+	})
+	return totals
+}